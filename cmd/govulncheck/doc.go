@@ -14,6 +14,52 @@ https://vuln.go.dev/privacy.html for more. Use the -db flag to specify a
 different database, which must implement the specification at
 https://go.dev/security/vuln/database.
 
+The -db flag also accepts a directory holding a copy of the database, either
+as a file:// URL or as a plain path. A relative path is resolved against the
+-C directory, if given, so a snapshot committed to the repository can be used
+for reproducible results:
+
+	$ govulncheck -C ./app -db ../vulndb ./...
+
+Requests to a database served over HTTP that fail with a timeout or a server
+error are retried with exponential backoff, up to the number of times given by
+the -db-retries flag (2 by default). A warning is printed to standard error
+before each retry. Other failures, such as 404 Not Found, are not retried.
+A 429 Too Many Requests response is retried too, after the delay given by its
+Retry-After header, if any; a request asked to wait more than a minute fails.
+
+To keep within the quota of a self-hosted database, -db-rate=n limits the
+requests to n per second, such as -db-rate=5 or -db-rate=0.5. Requests are not
+limited by default. A warning is printed to standard error the first time a
+request is delayed.
+
+A mirror behind a gateway may need extra headers, such as a tenant ID. The
+repeatable -db-header=Key:Value flag adds a header to each request to a
+database served over HTTP:
+
+	$ govulncheck -db https://vulndb.example.com -db-header=X-Tenant-ID:acme ./...
+
+With -verbose, the headers are logged to standard error, except for the values
+of those that look like secrets, such as Authorization or X-Api-Key headers and
+Bearer or Basic credentials, which are shown as [redacted]. Errors about
+malformed headers never show their values.
+
+The schema version of the database is recorded in the JSON output. If the
+database follows a newer version than govulncheck supports, a warning is
+printed to standard error. To fail instead, for example when using a
+self-hosted mirror, pass the expected version with -db-schema=1.
+
+A mirror that stopped syncing silently misses recent vulnerabilities. Pass a
+maximum age, such as -max-db-age=72h, to print a warning to standard error when
+the database was last modified longer ago than that, and -strict as well to
+fail instead. The age of the database is then shown with its last modified
+time, and recorded in the JSON output as db_age_seconds.
+
+The -verbose flag logs the time taken to load packages, to fetch
+vulnerabilities from the database, and to match them against the analyzed
+code. The timings are written to standard error, so they do not interfere
+with the -json output.
+
 Govulncheck looks for vulnerabilities in Go programs using a specific build
 configuration. For analyzing source code, that configuration is the Go version
 specified by the “go” command found on the PATH. For binaries, the build
@@ -37,13 +83,326 @@ For example, it might say
 
 	main.go:[line]:[column]: mypackage.main calls golang.org/x/text/language.Parse
 
+Package patterns can also be read, one per line, from a file named by the
+-pkg-file flag, or from standard input by passing - as a pattern or as the
+-pkg-file value:
+
+	$ cat changed-packages.txt | govulncheck -
+
+Patterns read this way are not checked for being a file, so the error that
+suggests -mode=binary when a single file is given is only reported for
+patterns on the command line.
+
+In source mode, a warning is printed to standard error for each pattern that
+is given twice or that a wildcard pattern such as ./... already matches. The
+scan goes ahead as usual.
+
+Without any patterns, govulncheck prints its usage and fails. Scripts that
+compute the patterns, and may end up with none, can pass -allow-empty to exit
+successfully without scanning instead.
+
+Patterns that match no packages, for instance because of a typo, make for a
+scan that finds nothing, which looks like a pass. In CI, pass -require-packages
+to fail instead when the patterns match no packages in source mode.
+
+For quick checks before a commit, -changed takes a comma-separated list of
+changed files, relative to the current directory or to -C, and only scans the
+packages matched by the patterns that contain one of those files or import,
+directly or not, a package that does:
+
+	$ govulncheck -changed=$(git diff --name-only HEAD | paste -sd, -) ./...
+
+This is an approximation of a full scan. Packages are still loaded in full,
+but vulnerable code that is only reachable from unchanged packages is not
+reported, changes to files other than Go source files, such as go.mod, are
+ignored, and if none of the packages is affected by the changes, nothing is
+scanned. Run a full scan before releasing.
+
+As a coarse pre-filter for very large dependency sets, -db-index-only checks
+the modules required by the go.mod file of the -C directory, after its
+replacements, against the modules index of the vulnerability database only:
+
+	$ govulncheck -db-index-only || govulncheck ./...
+
+No packages are loaded and no OSV entries are fetched. A vulnerability is a
+potential match unless the required version is at or after its latest fixed
+version. The required version may not be the one selected in the build, the
+vulnerability may have been introduced later, and the standard library and
+modules replaced by directories are not checked, so matches are labeled as
+potential, not confirmed. The exit code is 3 if there are any. Package
+patterns are not needed, and are ignored if given. As there are no findings,
+the flags that select or report them, such as -exclude, -ignore-file and
+-metrics, cannot be combined with -db-index-only.
+
+To analyze a module that is not checked out, pass a single module@version
+pattern instead:
+
+	$ govulncheck golang.org/x/text@v0.3.7
+
+Govulncheck downloads the module through the module proxy into a temporary
+directory, which is removed afterwards, and analyzes all of its packages. The
+version that was fetched is reported in the output.
+
 To control which files are processed, use the -tags flag to provide a
 comma-separated list of build tags, and the -test flag to indicate that test
 files should be included.
 
+To audit test tooling alone, -test-only analyzes the tests instead of the code
+they test. Only the test packages are scanned, and calls are only followed from
+functions declared in _test.go files, so production code is reported only where
+tests call it. Such findings are tagged as test findings: "(test code)" follows
+their ID in text output, and JSON findings have a test field. The flag is only
+supported in source mode, and cannot be combined with -test, which analyzes both.
+
+Source code is analyzed for the host platform, or for the GOOS and GOARCH set
+in the environment. Use the -platform flag to analyze for a different target,
+for example -platform=windows/amd64. Vulnerabilities that only affect other
+platforms are reported as informational.
+
 To include more detailed stack traces, pass -show=traces, this will cause it to
 print the full call stack for each entry.
 
+Pass -show=symbols to also print a sorted list of the vulnerable symbols that
+your code calls, each with the IDs of the vulnerabilities affecting it, as a
+checklist for code review.
+
+Pass -show=raw-osv to print the full OSV entry of each vulnerability, as JSON,
+after its details. This helps debug the data behind a finding without fetching
+it from the database. The -json output always includes the full entries, so
+the option is accepted there but changes nothing.
+
+Pass -show=depth to follow each example trace with its depth, the number of
+frames from your code to the vulnerable symbol. Vulnerabilities at depth 1 or
+2 are usually in direct dependencies and easier to address. With -json, the
+option adds the depth to each finding with a call stack.
+
+Pass -show=signatures to follow the vulnerable symbol of each example trace
+with its signature, as in language.Parse(s string) (t language.Tag, err error),
+to help judge how the call site uses it. Signatures come from type information,
+so binaries have none. With -json, the option adds the signature to the
+vulnerable frame of each finding with a call stack.
+
+Pass -show=reachability-summary to print, before the vulnerabilities are
+listed, how many are called, how many are in imported packages but not called,
+and how many are in required modules whose vulnerable packages are not
+imported. Each vulnerability is counted once, at the most precise level it was
+found at. With -json, the option adds the same counts to the summary message,
+as its reachability field.
+
+Pass -show=fix-command to follow each "Fixed in" version with the command that
+upgrades the module to it, as in (run: go get example.com/mod@v1.2.3), for
+copy-paste remediation. There is none for the standard library, which is
+upgraded with Go itself, or when no fix is available.
+
+For planning, -show=effort follows each "Fixed in" version with the kind of
+semver bump that upgrades to it from the found version: (patch upgrade),
+(minor upgrade) or (major upgrade). A major upgrade to v2 or later changes the
+import path of the module, so every import has to be updated too; it is shown
+as (major upgrade, new import path).
+
+Pass -show=version-delta to follow each "Fixed in" version with how many
+releases it is ahead of the found version, as in (6 releases behind), counting
+the released versions after the found one up to the fixed one. The versions
+are listed by the first module proxy of GOPROXY, once per module. The delta is
+left out when the proxy cannot be reached, when GOPROXY starts with direct or
+off, and for the standard library.
+
+For audit evidence, -show=provenance records which copy of each database entry
+the findings were matched against. Each vulnerability gets a "Database entry"
+line with the path of the entry in the database, its modification time and the
+SHA-256 hash of its content as read, so that a report can be reproduced and
+checked later. With -json, the option adds the endpoint and hash to each
+finding, as its provenance field, rather than to the OSV entry, whose schema
+has no such field.
+
+For classification, -show=cwe adds a "CWE" line to each vulnerability, as in
+CWE: CWE-79, CWE-89, listing the CWE weaknesses given by the database-specific
+data of its entry and by those of its aliases that are CWE IDs. The line is left
+out when the entry has none. With -json, the option gathers the same IDs in the
+cwe_ids field of the database_specific object of each OSV entry.
+
+To check a finding against the advisory, -show=ranges adds an "Affected" line to
+each module, with the version ranges that the OSV entry declares vulnerable for
+it, as in "from v1.0.0 before v1.0.4, from v1.1.2". This helps confirm that the
+found version is in range, and spot errors in the database. JSON output always
+has the full ranges, in the OSV entries.
+
+The call graph is not followed past calls into C through cgo, through which C
+code can call back into Go, nor past functions implemented in assembly. A call
+to a vulnerable symbol behind such a boundary is not found, so its finding is
+only informational. With -show=cgo-notes, each informational finding lists the
+cgo calls and assembly functions in the vulnerable package and in the packages
+that import it, in a "Not followed past" line and in the "boundaries" field of
+its JSON finding. Such a finding may be called after all. The call graph is
+only built in source mode at -scan=symbol, so -show=cgo-notes is rejected
+otherwise.
+
+When the text output is colored, with -show=color, the ID of each vulnerability
+is red if it is called and green if it is only imported. Pass -color-by=severity
+to color the IDs by the severity reported by the database instead: red for
+critical, orange for high, yellow for moderate and green for low.
+
+The -strip-ansi flag removes every terminal escape sequence from text output
+as it is written, including any that come from the vulnerability data itself,
+whether or not -show=color is given. Use it when the output is captured by a
+pager or a log that cannot display them.
+
+Text output is indented by two spaces per level. The -indent flag changes the
+unit of indentation to a number of spaces, to a tab with -indent=tab, or to any
+other prefix, which helps with log collectors that strip leading whitespace.
+
+Symbols in example traces are qualified by package name, as in
+language.Parse, and in full traces by import path. The -symbol-format flag
+applies one style to both: short for the function alone, qualified for the
+package name or full for the import path.
+
+Called vulnerabilities are listed by ID. Pass -sort=stacks to list the ones
+reached by the most distinct call stacks first, which are often the most
+urgent to fix. Vulnerabilities reached by as many stacks keep their order by ID.
+
+To triage a long report, -top=N lists only the N most severe called
+vulnerabilities in detail, by the severity reported by the database and then
+by the number of distinct call stacks reaching them, and counts the rest in an
+"And M more" line. Informational vulnerabilities are listed as usual, and the
+exit code still reflects every finding, shown or not. As -top has its own
+order, it cannot be used with -sort=stacks.
+
+Example traces are printed one per line unless -show=traces is given. To keep
+those lines short in narrow logs, -compact-width=N truncates each of them to N
+characters by replacing its middle with an ellipsis, keeping the vulnerable
+function at the end.
+
+Vulnerability descriptions are wrapped to the width given by -width=N. Without
+it, govulncheck uses $COLUMNS when set, then the width of the terminal, and
+80 characters when neither is known, for instance in CI logs.
+
+Vulnerabilities in packages that are imported but never called are reported as
+informational. Pass -show=import-stacks to also print the chain of modules
+through which each of them enters the build, which can help decide whether the
+dependency can be removed.
+
+To answer why a vulnerable module is a dependency at all, -show=why adds an
+"Import path" line to each module, called or not, with a shortest chain of
+imported packages from the main module to a package of the module, as go mod
+why -m prints it. The chain is also in the "why" field of JSON findings. It is
+only known in source mode.
+
+To prune dependencies, pass -group=module to list the informational findings
+by the module that brings them in instead, with the number of vulnerabilities
+each module accounts for, most first, and the lowest version that fixes all of
+them, if any. Called vulnerabilities are still listed one by one.
+
+To rank the modules by how dangerous they are, pass -show=module-severity. Each
+affected module, whether its vulnerabilities are called or not, gets a "Highest
+severity" line with the worst severity of all of its vulnerabilities, and with
+-group=module the most severe modules come first.
+
+For reports by severity, pass -group=severity to list the called
+vulnerabilities in sections from Critical, High, Medium and Low down to
+Unclassified, for entries without a severity in the database. Each keeps its
+usual block, and informational findings are listed as usual. It cannot be
+combined with -split-fixable.
+
+To apply your own assessment of some vulnerabilities, list their severities in
+a file passed with -severity-override, one per line in the form "ID: severity",
+where ID is an OSV ID or alias and severity is critical, high, moderate (or
+medium) or low. Text after a # is a comment. The severities replace those of
+the database before they are used by -group=severity, -color-by=severity and
+the other output formats, and the text output marks each of these
+vulnerabilities with "(severity overridden)".
+
+Text output ends with a request for feedback. Pass -no-footer-on-clean to leave
+it out when no vulnerabilities are called, which keeps the output of clean
+scheduled scans short. The findings themselves are printed as usual.
+
+The -min-stacks=N flag reports a called vulnerability as informational when
+fewer than N distinct call stacks lead to it, giving the reason in its place.
+This is a heuristic for ranking findings in large programs, where a
+vulnerability reached by a single unusual path may matter less; it is not a
+guarantee that the vulnerability is unreachable, so use it with care.
+
+The -lang flag selects the language of the labels and section headings of the
+text output, such as "Found in" and "Informational". Only English, en, the
+default, is available so far; translations are added as catalogs in
+internal/scan/messages.go.
+
+To act on module upgrades only, pass -no-traces to leave the "Example traces
+found" block out of each called vulnerability. The modules, their found and
+fixed versions, and the exit code stay the same.
+
+Each example trace starts with its number, as in "#1:". To post-process text
+output with patterns that "#" gets in the way of, -trace-marker=dashes starts
+each trace with "- " instead, and -trace-marker=none with nothing.
+
+The -split-fixable flag lists the called vulnerabilities of the text output in
+two sections, "Fixable" for those with a fixed version and "No fix available"
+for the others, which usually need mitigation or monitoring instead of an
+upgrade.
+
+To accept the risk of known vulnerabilities, list them in a file passed with
+-ignore-file, one per line in the form "ignore: entry". An entry is an OSV ID
+or alias, such as GO-2021-0113 or CVE-2021-38561, or a module path pattern,
+such as example.com/legacy/*, which drops the findings in the matching
+modules, for instance a vendored module that cannot be updated. A * matches any
+string. Text after a # is a comment. The vulnerabilities whose findings were
+dropped are still listed, in a message before the findings.
+
+For a one-off run, such as while investigating a specific advisory, pass the
+IDs or aliases to leave out with -exclude instead, as a comma-separated list or
+by repeating the flag. The findings are dropped the same way, and the number of
+excluded vulnerabilities is reported with their IDs.
+
+To weigh the security debt of a dependency, -blame=module keeps only the
+findings whose trace passes through the module, in any frame: its own
+vulnerabilities, and those of its dependencies that it calls on the way from
+your code. The summary tells how many of the vulnerabilities found were kept,
+and the JSON summary has the counts in its blame field. Informational findings
+have no call stacks, so only those of the module itself are kept.
+
+To focus on what you can fix yourself, -direct-only reports the called
+vulnerabilities of indirect dependencies, those marked // indirect in the
+go.mod file of the main module, as informational, with the reason in their
+place. The standard library counts as a direct dependency. The flag is only
+supported in source mode.
+
+For critical dependencies, -error-modules=list makes any vulnerability of the
+modules in the comma-separated list fail the scan, even if it is only
+imported or required and not called. Such vulnerabilities stay in the
+informational section, flagged "error by -error-modules", and the summary
+counts them. Modules are given by path, with stdlib for the standard library.
+The flag only affects text output, and cannot be combined with -called-only,
+which leaves those vulnerabilities out.
+
+To ratchet down known vulnerabilities over time, -max-findings=N sets a budget:
+the scan fails only if more than N distinct vulnerabilities are called, whatever
+their severity, and the summary says whether the budget was kept. The default,
+0, fails on any called vulnerability. Vulnerabilities in modules given to
+-error-modules fail the scan regardless of the budget. The flag only affects
+text output.
+
+The -called-only flag leaves informational findings, for vulnerabilities that
+are imported or required but not called, out of the output, together with the
+OSV entries only they refer to. The text output then has no informational
+section. The exit code does not change, since it only depends on the called
+vulnerabilities.
+
+To share a report without revealing local paths or internal module names, pass
+-redact. It replaces the home directory with ~ in positions and other paths of
+the output. The -redact-prefix flag, which implies -redact, takes a
+comma-separated list of further path or module prefixes to replace with
+<redacted>, for example -redact-prefix=corp.example.com/internal.
+
+To scan several independent modules of a repository in one run, list their
+go.mod files with -modfile, relative to the -C directory:
+
+	$ govulncheck -modfile=api/go.mod,worker/go.mod ./...
+
+The package patterns are loaded in the module of each go.mod file in turn,
+and the findings are merged into one report. Each example trace starts with
+the module directory it was found in, and the summary ends with the number of
+called vulnerabilities of each directory, including those with none. The flag is only supported in source
+mode.
+
 To run govulncheck on a compiled binary, pass it the path to the binary file
 with the -mode=binary flag:
 
@@ -52,9 +411,237 @@ with the -mode=binary flag:
 Govulncheck uses the binary's symbol information to find mentions of vulnerable
 functions. Its output omits call stacks, which require source code analysis.
 
+The file may also be a zip or tar archive, optionally gzip-compressed, such as
+a release artifact. Govulncheck then extracts the Go binaries in it to a
+temporary directory, scans each of them, and tags each finding with the
+archive path and the path of the binary within it, as in release.zip:bin/tool.
+Other files in the archive are ignored, and not extracted. The scan fails if
+an executable in it is larger than 1 GiB, if its executables are larger than
+4 GiB together, or if two of them have the same path.
+
+A file with the .so extension is scanned as a Go plugin, built with
+-buildmode=plugin, and each finding is tagged with the plugin path. Plugins
+carry no build information, so govulncheck derives their module versions
+from the module cache paths of their source files and their platform from
+the file header. Modules replaced by a directory or vendored are therefore
+not seen. The Go version is only known exactly if the plugin was built by a
+toolchain from the module cache; otherwise the oldest Go version with the
+plugin's symbol table format is assumed, which can over-report standard
+library vulnerabilities.
+
+On Linux, -pid=N scans the executable of the running process N instead of a
+binary file, to audit what is actually running rather than what was built:
+
+	$ govulncheck -mode=binary -pid=$(pidof server)
+
+The executable is read from /proc/N/exe, so it can still be scanned after the
+file on disk was replaced, but only by the user of the process or by root.
+
+Build tags passed with -tags in binary mode are recorded in the output for
+provenance, but do not change the analysis, which always uses the build
+configuration of the binary.
+
+The -format flag selects the output format. The default, text, is meant for
+people; -format=json (or -json) writes govulncheck's own JSON message stream;
+and -format=osv writes a JSON array of the OSV entries that were found to
+affect the code, for use by tools that consume OSV directly. Each affected
+package of those entries records where it was matched in a database_specific
+"govulncheck_matches" field. Entries matched several times are written once.
+
+For scripts that only need the findings, -format=ndjson-findings writes each
+finding as a single line of JSON, with the same fields as in the message
+stream, and nothing else:
+
+	$ govulncheck -format=ndjson-findings ./... | jq -r .osv
+
+Each finding in the JSON message stream records the scan_level that produced
+it, which can differ from the one requested with -scan: source scans at
+module level still match vulnerabilities by package, and stripped binaries
+only allow a module level match. Only at symbol level does a finding without a
+function in its trace mean that the vulnerability is not called.
+
+The JSON message stream ends with a summary message whose affected field is
+true when any vulnerability is called, and false otherwise. It is the
+canonical pass/fail signal for scripts, which can branch on it without
+grouping the findings themselves:
+
+	$ govulncheck -json ./... | jq -s 'last.summary.affected'
+
+The summary message was added in version v1.1.0 of the protocol, given by the
+protocol_version field of the config message, and earlier streams end with
+their last finding. In convert mode, the counts of -blame in the summary of
+the input are printed as they are.
+
+Progress messages are written to the JSON stream as soon as they are reported,
+so that a tool wrapping govulncheck can show them during long scans. When an
+archive of binaries or several -modfile modules are scanned, each progress
+message also has the number of the step it starts, from 1, in its done field,
+and the total number of steps.
+
+The fix format writes a script with one go get command per module that has a
+finding, upgrading it to the latest version listed as fixed for its findings:
+
+	$ govulncheck -format=fix ./... > upgrade.sh
+
+Modules with no fixed version are left out. Vulnerabilities in the standard
+library are fixed by upgrading Go, which the script recommends in a comment.
+
+To decide where to start, pass -plan. Instead of the findings, it prints one
+upgrade per module with called vulnerabilities, to the highest fixed version
+among them, ordered by how many called vulnerabilities each upgrade clears:
+
+	upgrade golang.org/x/text@v0.3.7 clears 1 vuln (GO-2021-0113)
+
+The standard library and the go command are upgraded together as Go. Called
+vulnerabilities that no upgrade clears are listed at the end, with the modules
+they have no fix in if an upgrade of another module clears them. As with the
+findings, the exit code is 3 when any vulnerability is called.
+
+To carry out the plan, pass -apply-fixes. In source mode, it raises the require
+directives of the go.mod file of the module of the -C directory to the fixed
+versions, runs go mod tidy, and prints each change, with -dry-run printing them
+without touching go.mod:
+
+	$ govulncheck -apply-fixes -dry-run ./...
+	Would update go.mod:
+	  golang.org/x/text v0.3.0 => v0.3.7 clears 1 vuln (GO-2021-0113)
+
+Upgrades of Go itself, of modules that go.mod replaces, to fixed versions with
+a new module path, such as a v2 module, and of modules that go.mod already
+requires at or above the fixed version are not made but listed as needing
+manual handling. If go mod tidy fails, go.mod is left unchanged. The exit code
+is 3 when called vulnerabilities are left, because they need manual handling,
+no upgrade clears them, or -dry-run was given.
+
+To follow called vulnerabilities over time, save the JSON output of regular
+scans and pass the reports to trend mode:
+
+	$ govulncheck -mode=trend reports/*.json
+
+For every vulnerability called in any of the reports, it prints the first and
+the last report it was called in, and whether it is still called in the latest
+one. Reports are ordered by the scan time that govulncheck records in them;
+if any report lacks one, they are taken in the order given. Pass -format=json
+for the same information as JSON.
+
+To combine the reports of scans run separately, for instance one per service,
+pass them to merge mode:
+
+	$ govulncheck -mode=merge reports/*.json
+
+The reports are replayed as one, in text or, with -format=json, as JSON. Each
+OSV entry is reported once, each finding is tagged with the report it comes
+from, shown as "in reports/api.json: " in example traces and as a source field
+in JSON, and the configuration of the first report is kept. The summary totals
+the called vulnerabilities across all the reports, followed by the count of
+each report.
+
+To check that saved reports still read back correctly after an upgrade, pass
+each one to convert mode with -verify:
+
+	$ govulncheck -mode=convert -verify reports/2023-06-01.json
+
+Instead of converting the report to text, govulncheck writes it again as JSON
+and compares the two, message by message. Fields that are lost or changed are
+listed, and the exit code is 1. Empty fields, and a summary that the report
+does not have, are not compared.
+
+To graph vulnerability counts with Prometheus, pass -metrics=file in addition
+to any other output. After the scan, govulncheck writes the gauges
+govulncheck_called_vulnerabilities, govulncheck_informational,
+govulncheck_modules_affected and govulncheck_scan_timestamp_seconds to file,
+labeled with the scanner version, in a format the node exporter textfile
+collector reads. The file is replaced in a single step, so the collector never
+sees a partial one.
+
+Short-lived CI jobs, which have no textfile collector, can instead push the
+same gauges to a Prometheus Pushgateway with -pushgateway=url. They are
+grouped under the job label given by -pushgateway-job, govulncheck by default,
+and the instance label given by -pushgateway-instance, if any; each push
+replaces the previous metrics of the group.
+
+To feed a log pipeline, -syslog logs a line for each called vulnerability to
+the local syslog daemon at the warning level, with its ID, module, version,
+fixed version and compact trace as key=value pairs, such as
+
+	vuln=GO-2021-0113 module=golang.org/x/text version=v0.3.5 fixed=v0.3.7 trace="main.main calls language.Parse"
+
+The facility is set with -syslog-facility, user by default, and the tag with
+-syslog-tag, govulncheck by default. A failure to log is reported as a
+warning and does not change the exit code. Syslog is not available on Windows
+or Plan 9.
+
+For SBOM tooling based on SPDX, -format=spdx-vuln writes an SPDX 2.3 document
+in JSON with a package for each module that has a finding. Each vulnerability
+of the module is attached to its package as a SECURITY external reference to
+the advisory, whose comment tells whether the vulnerability is called or only
+informational. The comment of the package gives the highest severity of its
+vulnerabilities, when the database rates any of them.
+
+On TeamCity, -format=teamcity writes service messages that the build log
+renders inline: each called vulnerability is a failed test named after its ID,
+with its example traces as the failure message, and each informational one is
+an inspection of the module it was found in.
+
+On GitLab, -format=codeclimate writes a code quality report in the Code Climate
+format, with an issue for each call stack of a called vulnerability. The issue
+is located at the call out of the scanned module, relative to the -C directory,
+and its fingerprint depends only on the vulnerability and the called symbols, so
+it stays the same while the call is not fixed.
+
+To follow findings in a feed reader or aggregate them across repositories,
+-format=rss writes an RSS 2.0 feed with an item for each called vulnerability.
+The title of an item is the OSV ID and summary of the vulnerability, its link is
+the database page, and its publication date is when the OSV entry was last
+modified. The GUID of an item depends only on the vulnerability and the called
+symbols, so readers show it once until other symbols are called.
+
+For shell scripts, -machine-text writes text output as lines that start with
+a fixed prefix, so that grep and awk can pick out fields by position:
+
+	VULN: <id> <called|informational> <summary>
+	MODULE: <id> <module> <found version> <fixed version, or ->
+	TRACE: <id> <compact trace>
+	SUMMARY: called=<n> informational=<n>
+
+Called vulnerabilities come first, each with a TRACE line per distinct trace,
+then informational ones, and a single SUMMARY line ends the output. Only the
+last field of a line can contain spaces. The exit code is that of text output,
+and the flags that change its layout, such as -show, -group and -top, are
+rejected with -machine-text rather than ignored.
+
+A trace position can point to a file that no longer exists, for instance one
+that was generated during the build and deleted since. Such dangling references
+break annotations in code scanning dashboards, so -missing-files=omit leaves
+these positions out of text output, and -missing-files=flag follows them with
+"(file not found)". In Code Climate reports, either value locates the issue at
+the vulnerable module instead, and flag also notes the position in its
+description. The default, keep, shows positions without checking their files.
+The files cannot be checked once -redact has rewritten their paths, so the two
+flags cannot be combined.
+
+To aggregate results with those of osv-scanner, -format=osv-scanner writes
+findings in the JSON results format of osv-scanner, with a single source and
+a package in the Go ecosystem for each module that has a finding. Each
+vulnerability of the module is listed with its OSV entry and a group, whose
+experimentalAnalysis tells whether it is called, as osv-scanner's own call
+analysis does. The mapping loses some information: call stacks are dropped,
+imported but not called vulnerabilities are not told apart from only required
+ones, max_severity is empty as the Go vulnerability database has no CVSS
+scores, and versions are given without their "v" prefix. The source is the
+go.mod file of the -C directory in source mode, and the binary in binary mode.
+
 Govulncheck exits successfully (exit code 0) if there are no vulnerabilities,
-and exits unsuccessfully if there are. It also exits successfully if the -json flag
-is provided, regardless of the number of detected vulnerabilities.
+and exits unsuccessfully if there are. It also exits successfully if the -json
+flag, or a -format other than text, is provided, regardless of the number of
+detected vulnerabilities.
+
+For shell scripts that only branch on the result, -check-only runs the full
+scan but prints nothing, not even the summary, and only sets the exit code of
+text output: 3 if more vulnerabilities are called than -max-findings allows,
+or one is in a module given to -error-modules, and 0 otherwise. Errors and warnings are still printed to
+standard error, with a non-zero exit code for errors, so that a failed scan is
+not mistaken for a clean one.
 
 # Limitations
 
@@ -82,6 +82,27 @@ var fixups = []fixup{
 	}, {
 		pattern: `"go_version": "go[^\s"]*"`,
 		replace: `"go_version": "go1.18"`,
+	}, {
+		// The default platform is the host platform.
+		pattern: `"platform": "[^"]*"`,
+		replace: `"platform": "goos/goarch"`,
+	}, {
+		pattern: `"scan_time": "[^"]*"`,
+		replace: `"scan_time": "2000-01-01T00:00:00Z"`,
+	}, {
+		// SPDX documents are identified by their creation time and contents.
+		pattern: `"created": "[^"]*"`,
+		replace: `"created": "2000-01-01T00:00:00Z"`,
+	}, {
+		pattern: `"documentNamespace": "[^"]*"`,
+		replace: `"documentNamespace": "https://pkg.go.dev/golang.org/x/vuln/spdx/0"`,
+	}, {
+		pattern: `"Tool: govulncheck-[^"]*"`,
+		replace: `"Tool: govulncheck-v0.0.0-00000000000-20000101010101"`,
+	}, {
+		// Timings reported by -verbose vary from run to run.
+		pattern: `(govulncheck: [a-z ]*) took \S+`,
+		replace: `$1 took 1ms`,
 	},
 }
 
@@ -126,6 +147,9 @@ func TestCommand(t *testing.T) {
 	}
 
 	os.Setenv("moddir", filepath.Join(testDir, "testdata", "modules"))
+	// Text output is wrapped to $COLUMNS, when set, so clear it to
+	// keep the expected output independent of the terminal.
+	os.Unsetenv("COLUMNS")
 	for _, md := range moduleDirs {
 		// Skip nogomod module. It has intended build issues.
 		if filepath.Base(md) == "nogomod" {
@@ -231,6 +255,10 @@ func runTestSuite(t *testing.T, dir string, govulndb string, update bool) {
 			if err := gather.Write(h); err != nil {
 				return nil, err
 			}
+			// Flushing writes the closing summary message.
+			if err := h.(interface{ Flush() error }).Flush(); err != nil {
+				return nil, err
+			}
 		}
 		out := sorted.Bytes()
 		for _, fix := range fixups {
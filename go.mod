@@ -7,11 +7,9 @@ require (
 	github.com/google/go-cmp v0.5.8
 	golang.org/x/mod v0.10.0
 	golang.org/x/sync v0.1.0
+	golang.org/x/sys v0.7.0
 	golang.org/x/tools v0.8.1-0.20230421161920-b9619ee54b47
 	mvdan.cc/unparam v0.0.0-20230312165513-e84e2d14e3b8
 )
 
-require (
-	github.com/google/renameio v0.1.0 // indirect
-	golang.org/x/sys v0.7.0 // indirect
-)
+require github.com/google/renameio v0.1.0 // indirect
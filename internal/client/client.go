@@ -11,6 +11,8 @@ package client
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,6 +22,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -32,10 +35,65 @@ import (
 // A Client for reading vulnerability databases.
 type Client struct {
 	source
+
+	provenance bool // whether to record the Provenance of entries
+
+	mu          sync.Mutex
+	provenances map[string]*Provenance // by entry ID
+}
+
+// Provenance identifies the copy of an entry that was read from a
+// vulnerability database, so that a scan can be reproduced and audited.
+// With Entry.Modified, which versions the entry, it pins down the data
+// that the findings for the entry were matched against.
+type Provenance struct {
+	// Endpoint is the path of the entry in the database, relative to
+	// the database URL, as in "ID/GO-2021-0113.json".
+	Endpoint string
+
+	// SHA256 is the hex-encoded SHA-256 hash of the entry, as read
+	// from the database.
+	SHA256 string
+}
+
+// Provenance returns the provenance of the entry with the given ID, or
+// nil if the client did not read it or does not record provenance.
+func (c *Client) Provenance(id string) *Provenance {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.provenances[id]
 }
 
 type Options struct {
 	HTTPClient *http.Client
+
+	// Header holds the headers added to each request to an HTTP
+	// database, such as the ones a gateway routes on.
+	Header http.Header
+
+	// Retries is the number of times a request to an HTTP database is
+	// retried after a transient failure, such as a timeout or a 5xx
+	// status code. Requests are not retried if Retries is zero.
+	Retries int
+
+	// OnRetry, if non-nil, is called before a failed request is retried
+	// with the error that caused the retry, the number of the upcoming
+	// retry starting at 1, and the delay before it is made.
+	OnRetry func(err error, retry int, delay time.Duration)
+
+	// Rate, if positive, is the maximum number of requests per second
+	// made to an HTTP database. Requests are not limited if Rate is
+	// zero.
+	Rate float64
+
+	// OnThrottle, if non-nil, is called when a request to an HTTP
+	// database is delayed to keep to Rate, with the delay.
+	OnThrottle func(delay time.Duration)
+
+	// Provenance, if true, makes the client record the Provenance of
+	// each entry it reads: where the entry was read from and the hash of
+	// its content.
+	Provenance bool
 }
 
 // NewClient returns a client that reads the vulnerability database
@@ -49,14 +107,20 @@ func NewClient(source string, opts *Options) (_ *Client, err error) {
 	if err != nil {
 		return nil, err
 	}
+	var c *Client
 	switch uri.Scheme {
 	case "http", "https":
-		return newHTTPClient(uri, opts)
+		c, err = newHTTPClient(uri, opts)
 	case "file":
-		return newLocalClient(uri)
+		c, err = newLocalClient(uri)
 	default:
 		return nil, fmt.Errorf("source %q has unsupported scheme", uri)
 	}
+	if err != nil {
+		return nil, err
+	}
+	c.provenance = opts != nil && opts.Provenance
+	return c, nil
 }
 
 var errUnknownSchema = errors.New("unrecognized vulndb format; see https://go.dev/security/vuln/database#api for accepted schema")
@@ -67,7 +131,7 @@ func newHTTPClient(uri *url.URL, opts *Options) (*Client, error) {
 	// v1 returns true if the source likely follows the V1 schema.
 	v1 := func() bool {
 		return source == "https://vuln.go.dev" ||
-			endpointExistsHTTP(source, "index/modules.json.gz")
+			endpointExistsHTTP(source, "index/modules.json.gz", opts)
 	}
 
 	if v1() {
@@ -77,9 +141,33 @@ func newHTTPClient(uri *url.URL, opts *Options) (*Client, error) {
 	return nil, errUnknownSchema
 }
 
-func endpointExistsHTTP(source, endpoint string) bool {
-	r, err := http.Head(source + "/" + endpoint)
-	return err == nil && r.StatusCode == http.StatusOK
+func endpointExistsHTTP(source, endpoint string, opts *Options) bool {
+	req, err := http.NewRequest(http.MethodHead, source+"/"+endpoint, nil)
+	if err != nil {
+		return false
+	}
+	c := http.DefaultClient
+	if opts != nil {
+		if opts.HTTPClient != nil {
+			c = opts.HTTPClient
+		}
+		addHeader(req, opts.Header)
+	}
+	r, err := c.Do(req)
+	if err != nil {
+		return false
+	}
+	r.Body.Close()
+	return r.StatusCode == http.StatusOK
+}
+
+// addHeader adds the values of header to the header of req.
+func addHeader(req *http.Request, header http.Header) {
+	for key, values := range header {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
 }
 
 func newLocalClient(uri *url.URL) (*Client, error) {
@@ -136,17 +224,50 @@ func NewInMemoryClient(entries []*osv.Entry) (*Client, error) {
 func (c *Client) LastModifiedTime(ctx context.Context) (_ time.Time, err error) {
 	derrors.Wrap(&err, "LastModifiedTime()")
 
-	b, err := c.source.get(ctx, dbEndpoint)
+	modified, _, err := c.DBInfo(ctx)
+	return modified, err
+}
+
+// SupportedSchemaVersion is the version of the database schema that
+// the client understands.
+const SupportedSchemaVersion = 1
+
+// SchemaVersion returns the version of the schema followed by the
+// database. Databases that do not record a version follow version 1,
+// the schema described at https://go.dev/security/vuln/database#api.
+func (c *Client) SchemaVersion(ctx context.Context) (_ int, err error) {
+	derrors.Wrap(&err, "SchemaVersion()")
+
+	_, version, err := c.DBInfo(ctx)
+	return version, err
+}
+
+// DBInfo returns both the last modified time of the database and the
+// version of its schema, as LastModifiedTime and SchemaVersion do, with
+// a single request.
+func (c *Client) DBInfo(ctx context.Context) (modified time.Time, schemaVersion int, err error) {
+	derrors.Wrap(&err, "DBInfo()")
+
+	dbMeta, err := c.dbMeta(ctx)
 	if err != nil {
-		return time.Time{}, err
+		return time.Time{}, 0, err
 	}
+	if dbMeta.SchemaVersion == 0 {
+		return dbMeta.Modified, 1, nil
+	}
+	return dbMeta.Modified, dbMeta.SchemaVersion, nil
+}
 
+func (c *Client) dbMeta(ctx context.Context) (*dbMeta, error) {
+	b, err := c.source.get(ctx, dbEndpoint)
+	if err != nil {
+		return nil, err
+	}
 	var dbMeta dbMeta
 	if err := json.Unmarshal(b, &dbMeta); err != nil {
-		return time.Time{}, err
+		return nil, err
 	}
-
-	return dbMeta.Modified, nil
+	return &dbMeta, nil
 }
 
 type ModuleRequest struct {
@@ -203,6 +324,31 @@ func (c *Client) ByModules(ctx context.Context, reqs []*ModuleRequest) (_ []*Mod
 	return resps, nil
 }
 
+// IDsByModules returns, for each request, the IDs of the
+// vulnerabilities that the modules index of the database lists for its
+// module, without fetching their OSV entries. The order of the requests
+// is preserved.
+//
+// Only the latest fixed version in the index is compared with the
+// requested version, so some of the IDs may not affect it.
+func (c *Client) IDsByModules(ctx context.Context, reqs []*ModuleRequest) (_ [][]string, err error) {
+	derrors.Wrap(&err, "IDsByModules(%v)", reqs)
+
+	metas, err := c.moduleMetas(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([][]string, len(reqs))
+	for i, req := range reqs {
+		if metas[i] == nil {
+			continue
+		}
+		ids[i] = metas[i].unfixedIDs(req.Version)
+		sort.Strings(ids[i])
+	}
+	return ids, nil
+}
+
 func (c *Client) moduleMetas(ctx context.Context, reqs []*ModuleRequest) (_ []*moduleMeta, err error) {
 	b, err := c.source.get(ctx, modulesEndpoint)
 	if err != nil {
@@ -233,6 +379,19 @@ func (c *Client) moduleMetas(ctx context.Context, reqs []*ModuleRequest) (_ []*m
 
 // byModule returns the OSV entries matching the ModuleRequest,
 // or (nil, nil) if there are none.
+// unfixedIDs returns the IDs of the vulnerabilities of m that are not
+// fixed at version, going by the latest fixed version in the index, or
+// all of them if version is "".
+func (m *moduleMeta) unfixedIDs(version string) []string {
+	var ids []string
+	for _, v := range m.Vulns {
+		if v.Fixed == "" || isem.Less(version, v.Fixed) {
+			ids = append(ids, v.ID)
+		}
+	}
+	return ids
+}
+
 func (c *Client) byModule(ctx context.Context, req *ModuleRequest, m *moduleMeta) (_ []*osv.Entry, err error) {
 	// This module isn't in the database.
 	if m == nil {
@@ -247,13 +406,7 @@ func (c *Client) byModule(ctx context.Context, req *ModuleRequest, m *moduleMeta
 		return nil, fmt.Errorf("version %s is not valid semver", req.Version)
 	}
 
-	var ids []string
-	for _, v := range m.Vulns {
-		if v.Fixed == "" || isem.Less(req.Version, v.Fixed) {
-			ids = append(ids, v.ID)
-		}
-	}
-
+	ids := m.unfixedIDs(req.Version)
 	if len(ids) == 0 {
 		return nil, nil
 	}
@@ -328,6 +481,18 @@ func (c *Client) byID(ctx context.Context, id string) (_ *osv.Entry, err error)
 	if err := json.Unmarshal(b, &entry); err != nil {
 		return nil, err
 	}
+	if c.provenance {
+		sum := sha256.Sum256(b)
+		c.mu.Lock()
+		if c.provenances == nil {
+			c.provenances = make(map[string]*Provenance)
+		}
+		c.provenances[id] = &Provenance{
+			Endpoint: entryEndpoint(id) + ".json",
+			SHA256:   hex.EncodeToString(sum[:]),
+		}
+		c.mu.Unlock()
+	}
 
 	return &entry, nil
 }
@@ -6,6 +6,8 @@ package client
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -163,6 +165,38 @@ func TestLastModifiedTime(t *testing.T) {
 	testAllClientTypes(t, test)
 }
 
+func TestSchemaVersion(t *testing.T) {
+	t.Run("unrecorded", func(t *testing.T) {
+		testAllClientTypes(t, func(t *testing.T, c *Client) {
+			got, err := c.SchemaVersion(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != 1 {
+				t.Errorf("SchemaVersion = %d, want 1", got)
+			}
+		})
+	})
+	t.Run("recorded", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.Mkdir(filepath.Join(dir, indexDir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		db := `{"modified":"2023-04-03T15:57:51Z","schema_version":2}`
+		if err := os.WriteFile(filepath.Join(dir, dbEndpoint+".json"), []byte(db), 0644); err != nil {
+			t.Fatal(err)
+		}
+		c := &Client{source: newLocalSource(dir)}
+		got, err := c.SchemaVersion(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != 2 {
+			t.Errorf("SchemaVersion = %d, want 2", got)
+		}
+	})
+}
+
 func TestByModules(t *testing.T) {
 	tcs := []struct {
 		module  *ModuleRequest
@@ -294,6 +328,91 @@ func TestByModules(t *testing.T) {
 	})
 }
 
+func TestIDsByModules(t *testing.T) {
+	reqs := []*ModuleRequest{
+		{Path: "github.com/beego/beego"},
+		// Only the fixed version in the index is compared, so
+		// GO-2022-0463 is left out.
+		{Path: "github.com/beego/beego", Version: "1.12.10"},
+		{Path: "golang.org/x/crypto", Version: "1.13.7"},
+		{Path: "does.not/exist"},
+	}
+	want := [][]string{
+		{"GO-2022-0463", "GO-2022-0569", "GO-2022-0572"},
+		{"GO-2022-0569", "GO-2022-0572"},
+		nil,
+		nil,
+	}
+	testAllClientTypes(t, func(t *testing.T, c *Client) {
+		got, err := c.IDsByModules(context.Background(), reqs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("IDsByModules() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestProvenance(t *testing.T) {
+	const id = "GO-2021-0068"
+	b, err := os.ReadFile(filepath.Join(testVulndb, "ID", id+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(b)
+	want := &Provenance{Endpoint: "ID/" + id + ".json", SHA256: hex.EncodeToString(sum[:])}
+
+	srv := newTestServer(testVulndb)
+	t.Cleanup(srv.Close)
+	for _, source := range []string{srv.URL, testVulndbFileURL} {
+		for _, provenance := range []bool{false, true} {
+			c, err := NewClient(source, &Options{HTTPClient: srv.Client(), Provenance: provenance})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.byID(context.Background(), id); err != nil {
+				t.Fatal(err)
+			}
+			got := c.Provenance(id)
+			if !provenance {
+				if got != nil {
+					t.Errorf("%s: got provenance %v; want none", source, got)
+				}
+				continue
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("%s: provenance mismatch (-want +got):\n%s", source, diff)
+			}
+		}
+	}
+}
+
+func TestHeader(t *testing.T) {
+	// The server only serves requests routed by the X-Tenant header, as
+	// a gateway might, including the one that checks the schema.
+	files := http.FileServer(http.Dir(testVulndb))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Tenant") != "acme" {
+			http.Error(w, "unknown tenant", http.StatusForbidden)
+			return
+		}
+		files.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	if _, err := NewClient(srv.URL, &Options{HTTPClient: srv.Client()}); err == nil {
+		t.Fatal("got a client without the header; want an error")
+	}
+	c, err := NewClient(srv.URL, &Options{HTTPClient: srv.Client(), Header: http.Header{"X-Tenant": {"acme"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.byID(context.Background(), "GO-2021-0068"); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // testAllClientTypes runs a given test for all client types.
 func testAllClientTypes(t *testing.T, test func(t *testing.T, c *Client)) {
 	t.Run("http", func(t *testing.T) {
@@ -30,6 +30,9 @@ type dbMeta struct {
 	// Modified is the time the database was last modified, calculated
 	// as the most recent time any single OSV entry was modified.
 	Modified time.Time `json:"modified"`
+	// SchemaVersion is the version of the database schema. It is not
+	// recorded by databases that follow version 1.
+	SchemaVersion int `json:"schema_version,omitempty"`
 }
 
 // moduleMeta contains metadata about a Go module that has one
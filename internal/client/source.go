@@ -8,12 +8,17 @@ import (
 	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
 
 	"golang.org/x/vuln/internal/derrors"
 	"golang.org/x/vuln/internal/osv"
@@ -29,34 +34,116 @@ type source interface {
 }
 
 func newHTTPSource(url string, opts *Options) *httpSource {
-	c := http.DefaultClient
-	if opts != nil && opts.HTTPClient != nil {
-		c = opts.HTTPClient
+	hs := &httpSource{url: url, c: http.DefaultClient}
+	if opts != nil {
+		if opts.HTTPClient != nil {
+			hs.c = opts.HTTPClient
+		}
+		hs.header = opts.Header
+		hs.retries = opts.Retries
+		hs.onRetry = opts.OnRetry
+		if opts.Rate > 0 {
+			hs.interval = time.Duration(float64(time.Second) / opts.Rate)
+		}
+		hs.onThrottle = opts.OnThrottle
 	}
-	return &httpSource{url: url, c: c}
+	return hs
 }
 
 // httpSource reads a vulnerability database from an http(s) source.
 type httpSource struct {
-	url string
-	c   *http.Client
+	url    string
+	c      *http.Client
+	header http.Header // added to each request
+
+	retries int
+	onRetry func(err error, retry int, delay time.Duration)
+
+	interval   time.Duration // between requests, or 0 if they are not limited
+	onThrottle func(delay time.Duration)
+	mu         sync.Mutex
+	next       time.Time // when the next request can be made, guarded by mu
 }
 
+// retryDelay is the delay before the first retry of a failed request.
+// It doubles with each subsequent retry.
+var retryDelay = 500 * time.Millisecond
+
+// maxRetryAfter is the longest delay asked for by a Retry-After header
+// that is waited for. Requests asked to wait longer fail.
+const maxRetryAfter = time.Minute
+
 func (hs *httpSource) get(ctx context.Context, endpoint string) (_ []byte, err error) {
 	derrors.Wrap(&err, "get(%s)", endpoint)
 
 	reqURL := fmt.Sprintf("%s/%s", hs.url, endpoint+".json.gz")
+	delay := retryDelay
+	for retry := 1; ; retry++ {
+		b, err := hs.fetch(ctx, reqURL)
+		if err == nil || retry > hs.retries || !isRetryable(ctx, err) {
+			return b, err
+		}
+		var se *statusError
+		if errors.As(err, &se) && se.retryAfter > 0 {
+			if se.retryAfter > maxRetryAfter {
+				return nil, err
+			}
+			delay = se.retryAfter
+		}
+		if hs.onRetry != nil {
+			hs.onRetry(err, retry, delay)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// statusError is returned when an HTTP database responds with a status
+// code other than 200 OK.
+type statusError struct {
+	code       int
+	retryAfter time.Duration // asked for by a Retry-After header, if any
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status code: %d", e.code)
+}
+
+// isRetryable reports whether err, returned by a request made with ctx,
+// is likely to be transient. Server errors, rate limiting and timeouts
+// are retried; other status codes, such as 404 or 403, are not.
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code >= 500 || se.code == http.StatusTooManyRequests
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+func (hs *httpSource) fetch(ctx context.Context, reqURL string) ([]byte, error) {
+	if err := hs.wait(ctx); err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
+	addHeader(req, hs.header)
 	resp, err := hs.c.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected HTTP status code: %d", resp.StatusCode)
+		return nil, &statusError{code: resp.StatusCode, retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
 	}
 
 	// Uncompress the result.
@@ -69,6 +156,55 @@ func (hs *httpSource) get(ctx context.Context, endpoint string) (_ []byte, err e
 	return io.ReadAll(r)
 }
 
+// wait waits until the next request can be made without exceeding the
+// rate of hs, and reserves the slot after it for the following request.
+func (hs *httpSource) wait(ctx context.Context) error {
+	if hs.interval == 0 {
+		return nil
+	}
+	hs.mu.Lock()
+	now := time.Now()
+	at := hs.next
+	if at.Before(now) {
+		at = now
+	}
+	hs.next = at.Add(hs.interval)
+	hs.mu.Unlock()
+
+	delay := at.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	if hs.onThrottle != nil {
+		hs.onThrottle(delay)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// parseRetryAfter returns the delay asked for by the value of a
+// Retry-After header, either a number of seconds or an HTTP date, at
+// time now. It returns 0 if there is none, or if it cannot be parsed.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
 func newLocalSource(dir string) *localSource {
 	return &localSource{fs: os.DirFS(dir)}
 }
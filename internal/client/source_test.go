@@ -6,8 +6,11 @@ package client
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestGet(t *testing.T) {
@@ -42,6 +45,103 @@ func TestGet(t *testing.T) {
 	}
 }
 
+func TestGetRetry(t *testing.T) {
+	defer func(d time.Duration) { retryDelay = d }(retryDelay)
+	retryDelay = time.Millisecond
+
+	files := http.FileServer(http.Dir(testVulndb))
+	for _, tc := range []struct {
+		name        string
+		status      int    // status of the failing responses
+		failures    int    // number of failing responses before success
+		retryAfter  string // Retry-After header of the failing responses
+		wantRetries int
+		wantErr     bool
+	}{
+		{name: "server error", status: http.StatusServiceUnavailable, failures: 2, wantRetries: 2},
+		{name: "rate limited", status: http.StatusTooManyRequests, failures: 1, wantRetries: 1},
+		{name: "retry after too long", status: http.StatusTooManyRequests, failures: 1, retryAfter: "3600", wantRetries: 0, wantErr: true},
+		{name: "too many failures", status: http.StatusBadGateway, failures: 4, wantRetries: 3, wantErr: true},
+		{name: "not found", status: http.StatusNotFound, failures: 1, wantRetries: 0, wantErr: true},
+		{name: "forbidden", status: http.StatusForbidden, failures: 1, wantRetries: 0, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= tc.failures {
+					if tc.retryAfter != "" {
+						w.Header().Set("Retry-After", tc.retryAfter)
+					}
+					w.WriteHeader(tc.status)
+					return
+				}
+				files.ServeHTTP(w, r)
+			}))
+			defer srv.Close()
+
+			retries := 0
+			hs := newHTTPSource(srv.URL, &Options{
+				HTTPClient: srv.Client(),
+				Retries:    3,
+				OnRetry:    func(error, int, time.Duration) { retries++ },
+			})
+			_, err := hs.get(context.Background(), "index/db")
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("get() error = %v, want error %t", err, tc.wantErr)
+			}
+			if retries != tc.wantRetries {
+				t.Errorf("got %d retries, want %d", retries, tc.wantRetries)
+			}
+		})
+	}
+}
+
+func TestGetRate(t *testing.T) {
+	srv := newTestServer(testVulndb)
+	defer srv.Close()
+
+	throttled := 0
+	hs := newHTTPSource(srv.URL, &Options{
+		HTTPClient: srv.Client(),
+		Rate:       50,
+		OnThrottle: func(time.Duration) { throttled++ },
+	})
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := hs.get(context.Background(), "index/db"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first request is made at once, and each of the others 20ms
+	// after the previous one.
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("3 requests at 50 per second took %v, want at least 40ms", elapsed)
+	}
+	if throttled != 2 {
+		t.Errorf("got %d throttled requests, want 2", throttled)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{"Sat, 01 Apr 2023 12:00:30 GMT", 30 * time.Second},
+		{"Sat, 01 Apr 2023 11:00:00 GMT", 0},
+		{"soon", 0},
+	} {
+		if got := parseRetryAfter(tc.value, now); got != tc.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tc.value, got, tc.want)
+		}
+	}
+}
+
 // testAllSourceTypes runs a given test for all source types.
 func testAllSourceTypes(t *testing.T, test func(t *testing.T, s source)) {
 	t.Run("http", func(t *testing.T) {
@@ -12,8 +12,9 @@ import (
 )
 
 const (
-	// ProtocolVersion is the current protocol version this file implements
-	ProtocolVersion = "v1.0.0"
+	// ProtocolVersion is the current protocol version this file implements.
+	// Version v1.1.0 added the summary message.
+	ProtocolVersion = "v1.1.0"
 )
 
 // Message is an entry in the output stream. It will always have exactly one
@@ -23,6 +24,94 @@ type Message struct {
 	Progress *Progress  `json:"progress,omitempty"`
 	OSV      *osv.Entry `json:"osv,omitempty"`
 	Finding  *Finding   `json:"finding,omitempty"`
+	Summary  *Summary   `json:"summary,omitempty"`
+}
+
+// Summary is the last message of a stream. It records the outcome of the
+// scan, so that clients need not work it out from the findings: Affected
+// is the canonical pass/fail signal, and a client that fails a check on
+// anything other than it may disagree with the exit code of govulncheck.
+// Streams of protocol versions before v1.1.0 have no summary.
+type Summary struct {
+	// Affected is true when any finding has a trace leading to the use of
+	// a vulnerable symbol, that is, when a vulnerability is called.
+	Affected bool `json:"affected"`
+
+	// Reachability counts the vulnerabilities by how far their use was
+	// established. It is only set when asked for.
+	Reachability *Reachability `json:"reachability,omitempty"`
+
+	// Blame counts the vulnerabilities kept by -blame. It is only set
+	// with -blame.
+	Blame *Blame `json:"blame,omitempty"`
+}
+
+// Blame counts the vulnerabilities whose findings were kept because
+// their traces pass through a module.
+type Blame struct {
+	// Module is the module that the traces of the kept findings pass
+	// through.
+	Module string `json:"module"`
+
+	// Kept is the number of vulnerabilities with a finding through
+	// Module.
+	Kept int `json:"kept"`
+
+	// Total is the number of vulnerabilities with a finding.
+	Total int `json:"total"`
+}
+
+// Reachability counts the vulnerabilities of a scan by the most precise
+// level at which they were found.
+type Reachability struct {
+	// Called is the number of vulnerabilities with a finding at the
+	// symbol level, that is, whose vulnerable symbols are called.
+	Called int `json:"called"`
+
+	// Imported is the number of vulnerabilities whose most precise
+	// findings are at the package level: a vulnerable package is
+	// imported, but none of its vulnerable symbols is called.
+	Imported int `json:"imported"`
+
+	// Required is the number of vulnerabilities with findings only at
+	// the module level: a vulnerable module is required, but none of its
+	// vulnerable packages is imported.
+	Required int `json:"required"`
+}
+
+// CountReachability returns the reachability counts of the
+// vulnerabilities of findings.
+func CountReachability(findings []*Finding) *Reachability {
+	// The rank of a vulnerability is the most precise level of its
+	// findings: 0 for module, 1 for package and 2 for symbol.
+	ranks := map[string]int{}
+	for _, f := range findings {
+		if len(f.Trace) == 0 {
+			continue
+		}
+		rank := 0
+		switch {
+		case f.Trace[0].Function != "":
+			rank = 2
+		case f.Trace[0].Package != "":
+			rank = 1
+		}
+		if r, ok := ranks[f.OSV]; !ok || rank > r {
+			ranks[f.OSV] = rank
+		}
+	}
+	r := &Reachability{}
+	for _, rank := range ranks {
+		switch rank {
+		case 2:
+			r.Called++
+		case 1:
+			r.Imported++
+		default:
+			r.Required++
+		}
+	}
+	return r
 }
 
 // Config must occur as the first message of a stream and informs the client
@@ -48,6 +137,18 @@ type Config struct {
 	// LastModified is the last modified time of the data source.
 	DBLastModified *time.Time `json:"db_last_modified,omitempty"`
 
+	// DBSchemaVersion is the version of the schema followed by the data
+	// source.
+	DBSchemaVersion int `json:"db_schema_version,omitempty"`
+
+	// ScanTime is the time at which the analysis was started.
+	ScanTime *time.Time `json:"scan_time,omitempty"`
+
+	// DBAgeSeconds is the time between DBLastModified and ScanTime, in
+	// seconds. It is only set when a maximum age of the data source was
+	// asked for.
+	DBAgeSeconds int64 `json:"db_age_seconds,omitempty"`
+
 	// GoVersion is the version of Go used for analyzing standard library
 	// vulnerabilities.
 	GoVersion string `json:"go_version,omitempty"`
@@ -55,6 +156,25 @@ type Config struct {
 	// ScanLevel instructs govulncheck to analyze at a specific level of detail.
 	// Valid values include module, package and symbol.
 	ScanLevel ScanLevel `json:"scan_level,omitempty"`
+
+	// BuildTags are the build tags supplied with the -tags flag.
+	//
+	// In binary mode the tags are recorded for provenance only, as the
+	// build configuration is the one the binary was built with.
+	BuildTags []string `json:"build_tags,omitempty"`
+
+	// Platform is the GOOS/GOARCH pair that source code was analyzed for.
+	// Findings for vulnerabilities that do not affect it are informational.
+	Platform string `json:"platform,omitempty"`
+
+	// Module is the module@version that was fetched and analyzed when
+	// source mode is given a module at a version instead of local packages.
+	// The version is the one the module proxy resolved the query to.
+	Module string `json:"module,omitempty"`
+
+	// TestOnly reports whether only test code was analyzed, as asked for
+	// with -test-only.
+	TestOnly bool `json:"test_only,omitempty"`
 }
 
 // Progress messages are informational only, intended to allow users to monitor
@@ -67,6 +187,13 @@ type Progress struct {
 
 	// Message is the progress message.
 	Message string `json:"message,omitempty"`
+
+	// Done and Total count the steps of the scan, when it is made of
+	// several steps, such as the binaries of an archive: Done is the
+	// number of the step that the message starts, from 1, so that it is
+	// never left out, and Total is the number of steps.
+	Done  int `json:"done,omitempty"`
+	Total int `json:"total,omitempty"`
 }
 
 // Vuln represents a single OSV entry.
@@ -102,6 +229,82 @@ type Finding struct {
 	// When a package is imported but no vulnerable symbol is called, the trace
 	// will contain a single-frame with no symbol or position information.
 	Trace []*Frame `json:"trace,omitempty"`
+
+	// Reason is a short explanation of why the finding is informational,
+	// for example "no call stack found". It is empty when the trace leads to
+	// the use of a vulnerable symbol.
+	Reason string `json:"reason,omitempty"`
+
+	// ScanLevel is the level of detail at which the finding was made. It
+	// is set by the analysis that produced the finding, and can differ from
+	// the requested Config.ScanLevel, for instance for stripped
+	// binaries, which only allow a module level scan.
+	ScanLevel ScanLevel `json:"scan_level,omitempty"`
+
+	// ImportChain lists the modules through which the vulnerable module is
+	// imported, starting with the module of an analyzed package and ending
+	// with the vulnerable module. It is only set for informational findings,
+	// and only when requested.
+	ImportChain []string `json:"import_chain,omitempty"`
+
+	// Boundaries lists the cgo calls and assembly functions past which
+	// the call graph could not be followed, in the vulnerable package or
+	// in packages that import it, as the kind of boundary, "cgo" or
+	// "assembly", followed by a colon, a space and the function. A call
+	// to the vulnerable symbol can hide behind them, so the finding may
+	// be informational only because of them. It is only set for
+	// informational findings, and only when requested.
+	Boundaries []string `json:"boundaries,omitempty"`
+
+	// Why lists the packages of a shortest chain of imports from a
+	// package of the main module to a package of the vulnerable module,
+	// which need not be the vulnerable one, as go mod why -m reports it.
+	// It is only set in source mode, and only when requested.
+	Why []string `json:"why,omitempty"`
+
+	// Binary is the path of the binary the finding is for, as the path of
+	// the archive followed by a colon and the path of the binary within
+	// it, or as the path of the plugin. It is only set when an archive of
+	// binaries or a Go plugin is scanned.
+	Binary string `json:"binary,omitempty"`
+
+	// Root is the directory of the go.mod file of the module the finding
+	// is for, as given with -modfile. It is only set when the modules of
+	// several go.mod files are scanned.
+	Root string `json:"root,omitempty"`
+
+	// Test reports whether the finding comes from test code. It is only
+	// set when only test code is scanned, with -test-only.
+	Test bool `json:"test,omitempty"`
+
+	// Source is the path of the JSON report the finding was read from. It
+	// is only set when several reports are merged with -mode=merge.
+	Source string `json:"source,omitempty"`
+
+	// Depth is the number of frames of the trace, from the main module to
+	// the vulnerable symbol. It is only set for findings with a call
+	// stack, and only when requested.
+	Depth int `json:"depth,omitempty"`
+
+	// Provenance identifies the copy of the OSV entry of the finding that
+	// was read from the database. It is not part of the OSV schema, so it
+	// is kept here rather than in the entry. It is only set when
+	// requested.
+	Provenance *Provenance `json:"provenance,omitempty"`
+}
+
+// Provenance identifies the copy of an OSV entry that was read from a
+// vulnerability database, so that a scan can be reproduced and audited.
+// With the modified time of the entry, it pins down the data that the
+// finding was matched against.
+type Provenance struct {
+	// Endpoint is the path of the entry in the database, relative to
+	// the database URL, as in "ID/GO-2021-0113.json".
+	Endpoint string `json:"endpoint"`
+
+	// SHA256 is the hex-encoded SHA-256 hash of the entry, as read
+	// from the database.
+	SHA256 string `json:"sha256"`
 }
 
 // Frame represents an entry in a finding trace.
@@ -126,6 +329,11 @@ type Frame struct {
 	// prepending Receiver to FuncName.
 	Receiver string `json:"receiver,omitempty"`
 
+	// Signature is the signature of the function, without the func
+	// keyword and the receiver, such as "(s string) (int, error)". It is
+	// only set for the vulnerable symbol of source scans, when asked for.
+	Signature string `json:"signature,omitempty"`
+
 	// Position describes an arbitrary source position
 	// including the file, line, and column location.
 	// A Position is valid if the line number is > 0.
@@ -148,11 +356,11 @@ type Position struct {
 type ScanLevel string
 
 const (
-	scanLevelModule  = "module"
-	scanLevelPackage = "package"
-	scanLevelSymbol  = "symbol"
+	ScanLevelModule  ScanLevel = "module"
+	ScanLevelPackage ScanLevel = "package"
+	ScanLevelSymbol  ScanLevel = "symbol"
 )
 
 // WantSymbols can be used to check whether the scan level is one that is able
 // to generate symbols called findings.
-func (l ScanLevel) WantSymbols() bool { return l == scanLevelSymbol }
+func (l ScanLevel) WantSymbols() bool { return l == ScanLevelSymbol }
@@ -5,8 +5,12 @@
 package govulncheck_test
 
 import (
+	"bufio"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"golang.org/x/vuln/internal/govulncheck"
 	"golang.org/x/vuln/internal/test"
 )
 
@@ -15,3 +19,98 @@ func TestImports(t *testing.T) {
 		"golang.org/x/vuln/internal/osv", // allowed to pull in the osv json entries
 	)
 }
+
+func TestJSONHandlerFlushesProgress(t *testing.T) {
+	var buf strings.Builder
+	w := bufio.NewWriterSize(&buf, 4096)
+	h := govulncheck.NewJSONHandler(w)
+	if err := h.Progress(&govulncheck.Progress{Message: "Scanning...", Done: 1, Total: 2}); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "progress": {
+    "message": "Scanning...",
+    "done": 1,
+    "total": 2
+  }
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONHandlerFlushesResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	h := govulncheck.NewJSONHandler(w)
+	if err := h.Progress(&govulncheck.Progress{Message: "Scanning..."}); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Flushed {
+		t.Error("the response was not flushed after a progress message")
+	}
+}
+
+func TestJSONHandlerReachability(t *testing.T) {
+	var buf strings.Builder
+	h := govulncheck.NewReachabilityJSONHandler(&buf)
+	for _, f := range []*govulncheck.Finding{
+		{OSV: "GO-0000-0001", Trace: []*govulncheck.Frame{{Module: "golang.org/a"}}},
+		{OSV: "GO-0000-0001", Trace: []*govulncheck.Frame{{Module: "golang.org/a", Package: "golang.org/a"}}},
+		{OSV: "GO-0000-0001", Trace: []*govulncheck.Frame{{Module: "golang.org/a", Package: "golang.org/a", Function: "F"}}},
+		{OSV: "GO-0000-0002", Trace: []*govulncheck.Frame{{Module: "golang.org/b", Package: "golang.org/b"}}},
+		{OSV: "GO-0000-0003", Trace: []*govulncheck.Frame{{Module: "golang.org/c"}}},
+	} {
+		if err := h.Finding(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf.Reset()
+	if err := h.(interface{ Flush() error }).Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "summary": {
+    "affected": true,
+    "reachability": {
+      "called": 1,
+      "imported": 1,
+      "required": 1
+    }
+  }
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHandleJSONSummary(t *testing.T) {
+	in := `{"finding": {"osv": "GO-0000-0001", "trace": [{"module": "golang.org/a", "package": "golang.org/a", "function": "F"}]}}
+{"summary": {"affected": true, "blame": {"module": "golang.org/a", "kept": 1, "total": 2}}}
+`
+	var buf strings.Builder
+	h := govulncheck.NewJSONHandler(&buf)
+	if err := govulncheck.HandleJSON(strings.NewReader(in), h); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := h.(interface{ Flush() error }).Flush(); err != nil {
+		t.Fatal(err)
+	}
+	// The counts of -blame cannot be recomputed, so they are kept.
+	want := `{
+  "summary": {
+    "affected": true,
+    "blame": {
+      "module": "golang.org/a",
+      "kept": 1,
+      "total": 2
+    }
+  }
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
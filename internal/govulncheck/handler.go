@@ -6,13 +6,19 @@ package govulncheck
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 
 	"golang.org/x/vuln/internal/osv"
 )
 
 // Handler handles messages to be presented in a vulnerability scan output
-// stream.
+// stream. The messages of a scan arrive in the order of the stream: the
+// config, then any number of progress messages, and then any number of
+// OSV entries and findings, each finding after the entry of its
+// vulnerability. Handlers that buffer messages write them when flushed,
+// after the last one.
 type Handler interface {
 	// Config communicates introductory message to the user.
 	Config(config *Config) error
@@ -27,15 +33,28 @@ type Handler interface {
 	Finding(finding *Finding) error
 }
 
+// SummaryHandler is implemented by the handlers that take the summary
+// message of a stream, to keep what cannot be recomputed from the other
+// messages, such as the counts of -blame.
+type SummaryHandler interface {
+	Summary(summary *Summary) error
+}
+
 // HandleJSON reads the json from the supplied stream and hands the decoded
-// output to the handler.
+// output to the handler. Messages are decoded and handed over one at a
+// time, as they are read, so the stream is never held in memory as a whole.
+// The summary is only handed to a SummaryHandler; other handlers drop it,
+// and recompute what they report of it when flushed.
 func HandleJSON(from io.Reader, to Handler) error {
 	dec := json.NewDecoder(from)
-	for dec.More() {
+	for n := 1; dec.More(); n++ {
 		msg := Message{}
 		// decode the next message in the stream
 		if err := dec.Decode(&msg); err != nil {
-			return err
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return fmt.Errorf("input ends in the middle of message %d; it may be truncated", n)
+			}
+			return fmt.Errorf("decoding message %d: %w", n, err)
 		}
 		// dispatch the message
 		var err error
@@ -51,6 +70,9 @@ func HandleJSON(from io.Reader, to Handler) error {
 		if msg.Finding != nil {
 			err = to.Finding(msg.Finding)
 		}
+		if sh, ok := to.(SummaryHandler); ok && msg.Summary != nil {
+			err = sh.Summary(msg.Summary)
+		}
 		if err != nil {
 			return err
 		}
@@ -13,14 +13,31 @@ import (
 )
 
 type jsonHandler struct {
-	enc *json.Encoder
+	w        io.Writer
+	enc      *json.Encoder
+	affected bool
+
+	reachability bool       // whether the summary counts reachability
+	findings     []*Finding // the findings to count, if reachability is set
+	blame        *Blame     // the counts of -blame, if given
+	input        *Summary   // the summary of the JSON input, if any
 }
 
 // NewJSONHandler returns a handler that writes govulncheck output as json.
 func NewJSONHandler(w io.Writer) Handler {
+	return newJSONHandler(w, false)
+}
+
+// NewReachabilityJSONHandler returns a handler like NewJSONHandler whose
+// summary also counts the vulnerabilities by reachability.
+func NewReachabilityJSONHandler(w io.Writer) Handler {
+	return newJSONHandler(w, true)
+}
+
+func newJSONHandler(w io.Writer, reachability bool) *jsonHandler {
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "  ")
-	return &jsonHandler{enc: enc}
+	return &jsonHandler{w: w, enc: enc, reachability: reachability}
 }
 
 // Config writes config block in JSON to the underlying writer.
@@ -28,9 +45,22 @@ func (h *jsonHandler) Config(config *Config) error {
 	return h.enc.Encode(Message{Config: config})
 }
 
-// Progress writes a progress message in JSON to the underlying writer.
+// Progress writes a progress message in JSON to the underlying writer,
+// so that a consumer can show the progress while the scan goes on. Each
+// message is written in a single Write, which an unbuffered writer such
+// as os.Stdout passes on at once; a buffered writer, such as a
+// bufio.Writer or an http.ResponseWriter, is flushed after it.
 func (h *jsonHandler) Progress(progress *Progress) error {
-	return h.enc.Encode(Message{Progress: progress})
+	if err := h.enc.Encode(Message{Progress: progress}); err != nil {
+		return err
+	}
+	switch f := h.w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+	return nil
 }
 
 // OSV writes an osv entry in JSON to the underlying writer.
@@ -40,5 +70,41 @@ func (h *jsonHandler) OSV(entry *osv.Entry) error {
 
 // Finding writes a finding in JSON to the underlying writer.
 func (h *jsonHandler) Finding(finding *Finding) error {
+	if len(finding.Trace) > 0 && finding.Trace[0].Function != "" {
+		h.affected = true
+	}
+	if h.reachability {
+		h.findings = append(h.findings, finding)
+	}
 	return h.enc.Encode(Message{Finding: finding})
 }
+
+// BlameSummary records the counts of -blame for the summary.
+func (h *jsonHandler) BlameSummary(blame *Blame) {
+	h.blame = blame
+}
+
+// Summary records the summary of the JSON input, whose counts are kept
+// where the handler does not count them itself.
+func (h *jsonHandler) Summary(summary *Summary) error {
+	h.input = summary
+	return nil
+}
+
+// Flush writes the summary of the findings in JSON to the underlying
+// writer, as the last message.
+func (h *jsonHandler) Flush() error {
+	summary := &Summary{Affected: h.affected, Blame: h.blame}
+	if h.reachability {
+		summary.Reachability = CountReachability(h.findings)
+	}
+	if h.input != nil {
+		if summary.Blame == nil {
+			summary.Blame = h.input.Blame
+		}
+		if summary.Reachability == nil {
+			summary.Reachability = h.input.Reachability
+		}
+	}
+	return h.enc.Encode(Message{Summary: summary})
+}
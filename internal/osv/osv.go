@@ -235,4 +235,13 @@ type DatabaseSpecific struct {
 	// The URL of the Go advisory for this vulnerability, of the form
 	// "https://pkg.go.dev/GO-YYYY-XXXX".
 	URL string `json:"url,omitempty"`
+
+	// Severity is the qualitative severity rating of the vulnerability,
+	// one of "CRITICAL", "HIGH", "MODERATE" or "LOW", when the database
+	// provides one.
+	Severity string `json:"severity,omitempty"`
+
+	// CWEIDs are the IDs of the CWE weaknesses of the vulnerability, such
+	// as "CWE-79", when the database provides them.
+	CWEIDs []string `json:"cwe_ids,omitempty"`
 }
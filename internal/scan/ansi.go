@@ -0,0 +1,75 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import "io"
+
+// States of ansiStripper between writes.
+const (
+	ansiText   = iota
+	ansiEscape // after ESC
+	ansiCSI    // in a control sequence, after ESC [
+	ansiOSC    // in an operating system command, after ESC ]
+	ansiOSCEsc // after ESC in an operating system command
+)
+
+// ansiStripper is a writer that removes ANSI escape sequences from what
+// is written to it before passing it on to w. Sequences may be split
+// across writes.
+type ansiStripper struct {
+	w     io.Writer
+	state int
+	buf   []byte
+}
+
+// Write writes p to the underlying writer, without escape sequences.
+// It reports all of p as written unless the underlying writer fails.
+func (s *ansiStripper) Write(p []byte) (int, error) {
+	s.buf = s.buf[:0]
+	for _, c := range p {
+		switch s.state {
+		case ansiText:
+			if c == 0x1b {
+				s.state = ansiEscape
+			} else {
+				s.buf = append(s.buf, c)
+			}
+		case ansiEscape:
+			switch {
+			case c == '[':
+				s.state = ansiCSI
+			case c == ']':
+				s.state = ansiOSC
+			case c >= 0x20 && c <= 0x2f:
+				// An intermediate byte; the sequence goes on.
+			default:
+				s.state = ansiText
+			}
+		case ansiCSI:
+			// Parameter and intermediate bytes are in 0x20-0x3f, and
+			// anything else ends the sequence.
+			if c < 0x20 || c > 0x3f {
+				s.state = ansiText
+			}
+		case ansiOSC:
+			switch c {
+			case 0x07:
+				s.state = ansiText
+			case 0x1b:
+				s.state = ansiOSCEsc
+			}
+		case ansiOSCEsc:
+			// ESC \ is the string terminator; anything else is
+			// malformed, so end the command anyway.
+			s.state = ansiText
+		}
+	}
+	if len(s.buf) > 0 {
+		if _, err := s.w.Write(s.buf); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestANSIStripper(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"plain", []string{"no escapes\n"}, "no escapes\n"},
+		{"colors", []string{fgRed + "GO-0000-0001" + colorReset + " " + fgOrange + "x"}, "GO-0000-0001 x"},
+		{"split", []string{"a\033", "[3", "1mb\033[0", "m"}, "ab"},
+		{"cursor movement", []string{"a\033[2Kb\033[1;1Hc"}, "abc"},
+		{"two byte sequence", []string{"a\033cb"}, "ab"},
+		{"osc with bell", []string{"a\033]0;title\007b"}, "ab"},
+		{"osc with terminator", []string{"a\033]8;;https://go.dev\033\\link\033]8;;\033\\b"}, "alinkb"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf strings.Builder
+			s := &ansiStripper{w: &buf}
+			for _, w := range tc.writes {
+				if n, err := s.Write([]byte(w)); err != nil || n != len(w) {
+					t.Fatalf("Write(%q) = %d, %v; want %d, nil", w, n, err, len(w))
+				}
+			}
+			if got := buf.String(); got != tc.want {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// goModChange is an update of a require directive of go.mod.
+type goModChange struct {
+	module   string
+	from, to string   // from is "" if the module was not required
+	cleared  []string // IDs of the called vulnerabilities it clears
+}
+
+// manualFix is an upgrade of the plan that is not made in go.mod, and why.
+type manualFix struct {
+	step   *planStep
+	reason string
+}
+
+// applyFixesHandler carries out the remediation plan of the called
+// vulnerabilities, for the -apply-fixes flag: it updates the require
+// directives of the go.mod file of the -C directory to the fixed
+// versions, runs go mod tidy, and writes what it changed. With
+// -dry-run, it only writes what it would change.
+type applyFixesHandler struct {
+	ctx      context.Context
+	w        io.Writer
+	cfg      *config
+	osvs     []*osv.Entry
+	findings []*findingSummary
+}
+
+// newApplyFixesHandler returns a handler that applies the fixes for the
+// scan of cfg and reports to w.
+func newApplyFixesHandler(ctx context.Context, w io.Writer, cfg *config) *applyFixesHandler {
+	return &applyFixesHandler{ctx: ctx, w: w, cfg: cfg}
+}
+
+func (h *applyFixesHandler) Config(config *govulncheck.Config) error {
+	return nil
+}
+
+func (h *applyFixesHandler) Progress(progress *govulncheck.Progress) error {
+	return nil
+}
+
+// OSV gathers osv entries for the findings.
+func (h *applyFixesHandler) OSV(entry *osv.Entry) error {
+	h.osvs = append(h.osvs, entry)
+	return nil
+}
+
+// Finding gathers the called vulnerability findings to be fixed.
+func (h *applyFixesHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	if finding.Trace[0].Function != "" {
+		h.findings = append(h.findings, newFindingSummary(finding))
+	}
+	return nil
+}
+
+// Flush updates go.mod and writes the changes, followed by the upgrades
+// left for manual handling and the vulnerabilities that no upgrade
+// clears. Like the findings it replaces, it fails with
+// errVulnerabilitiesFound if any called vulnerability is left, because it
+// needs manual handling, no upgrade clears it, or -dry-run was given.
+func (h *applyFixesHandler) Flush() error {
+	fixupFindings(h.osvs, h.findings)
+	steps, unfixed := plan(h.findings)
+	if len(steps) == 0 && len(unfixed) == 0 {
+		_, err := io.WriteString(h.w, "No called vulnerabilities to remediate.\n")
+		return err
+	}
+	file := goEnv(h.cfg, "GOMOD")
+	if file == "" || file == os.DevNull {
+		return errors.New("govulncheck: -apply-fixes found no go.mod file for the module")
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	out, changes, manual, err := updateGoMod(file, data, steps)
+	if err != nil {
+		return err
+	}
+	if !h.cfg.dryRun && len(changes) > 0 {
+		if err := h.apply(file, data, out); err != nil {
+			return err
+		}
+	}
+
+	var b strings.Builder
+	switch {
+	case len(changes) == 0:
+		b.WriteString("No go.mod changes to make.\n")
+	case h.cfg.dryRun:
+		b.WriteString("Would update go.mod:\n")
+	default:
+		b.WriteString("Updated go.mod, and ran go mod tidy:\n")
+	}
+	for _, c := range changes {
+		from := c.from
+		if from == "" {
+			from = "(not required)"
+		}
+		fmt.Fprintf(&b, "  %s %s => %s clears %d %s (%s)\n", c.module, from, c.to,
+			len(c.cleared), choose(len(c.cleared) == 1, "vuln", "vulns"), strings.Join(c.cleared, ", "))
+	}
+	if len(manual) > 0 {
+		b.WriteString("\nNeeds manual handling:\n")
+		for _, m := range manual {
+			target := fmt.Sprintf("upgrade %s@%s", m.step.module, m.step.version)
+			if m.step.module == "" {
+				target = "upgrade Go to " + semverToGoTag(m.step.version)
+			}
+			fmt.Fprintf(&b, "  %s: %s\n", target, m.reason)
+		}
+	}
+	if len(unfixed) > 0 {
+		fmt.Fprintf(&b, "\nNo upgrade clears %s: %s\n", choose(len(unfixed) == 1, "1 vuln", fmt.Sprintf("%d vulns", len(unfixed))), strings.Join(unfixed, ", "))
+	}
+	if _, err := io.WriteString(h.w, b.String()); err != nil {
+		return err
+	}
+	if len(manual) > 0 || len(unfixed) > 0 || (h.cfg.dryRun && len(changes) > 0) {
+		return errVulnerabilitiesFound
+	}
+	return nil
+}
+
+// apply writes data to the go.mod file, whose content was orig, and tidies
+// the module. If go mod tidy fails, orig is written back.
+func (h *applyFixesHandler) apply(file string, orig, data []byte) error {
+	fi, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, data, fi.Mode().Perm()); err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(h.ctx, "go", "mod", "tidy")
+	cmd.Dir = h.cfg.dir
+	if len(h.cfg.env) > 0 {
+		cmd.Env = h.cfg.env
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if werr := os.WriteFile(file, orig, fi.Mode().Perm()); werr != nil {
+			return fmt.Errorf("govulncheck: updated %s, but go mod tidy failed: %v\n%s\nrestoring %s: %v", file, err, stderr.Bytes(), file, werr)
+		}
+		return fmt.Errorf("govulncheck: go mod tidy failed, so %s was left unchanged: %v\n%s", file, err, stderr.Bytes())
+	}
+	return nil
+}
+
+// updateGoMod returns the go.mod file data with the require directives
+// of the modules of steps raised to their versions, and the changes
+// made. An upgrade is left for manual handling, and not made, when it is
+// of Go itself, when go.mod replaces the module, when the fixed version
+// is of a new major version, whose module path differs, or when go.mod
+// already requires the fixed version or a later one, so that the
+// vulnerable version comes from elsewhere.
+func updateGoMod(file string, data []byte, steps []*planStep) ([]byte, []*goModChange, []manualFix, error) {
+	f, err := modfile.Parse(file, data, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var changes []*goModChange
+	var manual []manualFix
+	for _, s := range steps {
+		if s.module == "" {
+			manual = append(manual, manualFix{s, "go.mod does not select the version of Go"})
+			continue
+		}
+		if isReplaced(f, s.module) {
+			manual = append(manual, manualFix{s, "go.mod replaces the module"})
+			continue
+		}
+		from := ""
+		for _, r := range f.Require {
+			if r.Mod.Path == s.module {
+				from = r.Mod.Version
+			}
+		}
+		if semver.Major(from) != semver.Major(s.version) && newModulePath(s.module, s.version) {
+			manual = append(manual, manualFix{s, "the fixed version has a new module path"})
+			continue
+		}
+		if semver.Compare(from, s.version) >= 0 {
+			manual = append(manual, manualFix{s, fmt.Sprintf("go.mod already requires %s, at or above the fixed version", from)})
+			continue
+		}
+		if err := f.AddRequire(s.module, s.version); err != nil {
+			return nil, nil, nil, err
+		}
+		changes = append(changes, &goModChange{module: s.module, from: from, to: s.version, cleared: s.cleared})
+	}
+	f.Cleanup()
+	out, err := f.Format()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return out, changes, manual, nil
+}
+
+// isReplaced reports whether the go.mod file f replaces any version of
+// module.
+func isReplaced(f *modfile.File, module string) bool {
+	for _, r := range f.Replace {
+		if r.Old.Path == module {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+const applyFixesGoMod = `module golang.org/main
+
+go 1.20
+
+require (
+	golang.org/a v1.0.0
+	golang.org/b v1.0.0
+	golang.org/d v1.5.0
+)
+
+replace golang.org/b => ../b
+`
+
+func TestUpdateGoMod(t *testing.T) {
+	steps := []*planStep{
+		{module: "golang.org/a", version: "v1.2.0", cleared: []string{"GO-0000-0001"}},
+		{module: "golang.org/b", version: "v1.1.0", cleared: []string{"GO-0000-0002"}},
+		{module: "golang.org/c", version: "v0.3.0", cleared: []string{"GO-0000-0003"}},
+		{module: "golang.org/d", version: "v2.0.0", cleared: []string{"GO-0000-0004"}},
+		{module: "", version: "v1.20.5", cleared: []string{"GO-0000-0005"}},
+		{module: "golang.org/d", version: "v1.4.0", cleared: []string{"GO-0000-0006"}},
+	}
+	out, changes, manual, err := updateGoMod("go.mod", []byte(applyFixesGoMod), steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `module golang.org/main
+
+go 1.20
+
+require (
+	golang.org/a v1.2.0
+	golang.org/b v1.0.0
+	golang.org/d v1.5.0
+	golang.org/c v0.3.0
+)
+
+replace golang.org/b => ../b
+`
+	if diff := cmp.Diff(want, string(out)); diff != "" {
+		t.Errorf("go.mod mismatch (-want, +got):\n%s", diff)
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.module+" "+c.from+" => "+c.to)
+	}
+	if diff := cmp.Diff([]string{"golang.org/a v1.0.0 => v1.2.0", "golang.org/c  => v0.3.0"}, got); diff != "" {
+		t.Errorf("changes mismatch (-want, +got):\n%s", diff)
+	}
+	got = nil
+	for _, m := range manual {
+		got = append(got, m.step.module+": "+m.reason)
+	}
+	wantManual := []string{
+		"golang.org/b: go.mod replaces the module",
+		"golang.org/d: the fixed version has a new module path",
+		": go.mod does not select the version of Go",
+		"golang.org/d: go.mod already requires v1.5.0, at or above the fixed version",
+	}
+	if diff := cmp.Diff(wantManual, got); diff != "" {
+		t.Errorf("manual fixes mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestApplyFixesDryRun(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "go.mod")
+	if err := os.WriteFile(file, []byte(applyFixesGoMod), 0666); err != nil {
+		t.Fatal(err)
+	}
+	var buf strings.Builder
+	h := newApplyFixesHandler(context.Background(), &buf, &config{dir: dir, dryRun: true})
+	for _, id := range []string{"GO-0000-0001", "GO-0000-0002"} {
+		h.OSV(&osv.Entry{ID: id})
+	}
+	for _, f := range []*govulncheck.Finding{
+		{OSV: "GO-0000-0001", FixedVersion: "v1.2.0", Trace: []*govulncheck.Frame{
+			{Module: "golang.org/a", Version: "v1.0.0", Package: "golang.org/a", Function: "F"},
+		}},
+		{OSV: "GO-0000-0002", FixedVersion: "v2.0.0", Trace: []*govulncheck.Frame{
+			{Module: "golang.org/d", Version: "v1.5.0", Package: "golang.org/d", Function: "G"},
+		}},
+	} {
+		if err := h.Finding(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Nothing is fixed with -dry-run, so the called vulnerabilities fail
+	// the scan.
+	if err := h.Flush(); err != errVulnerabilitiesFound {
+		t.Fatalf("got error %v, want %v", err, errVulnerabilitiesFound)
+	}
+	want := `Would update go.mod:
+  golang.org/a v1.0.0 => v1.2.0 clears 1 vuln (GO-0000-0001)
+
+Needs manual handling:
+  upgrade golang.org/d@v2.0.0: the fixed version has a new module path
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("output mismatch (-want, +got):\n%s", diff)
+	}
+	if data, err := os.ReadFile(file); err != nil || string(data) != applyFixesGoMod {
+		t.Errorf("go.mod changed with -dry-run: %s (%v)", data, err)
+	}
+}
+
+func TestApplyFixesTidyFails(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "go.mod")
+	if err := os.WriteFile(file, []byte(applyFixesGoMod), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nimport _ \"golang.org/a\"\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	// go mod tidy cannot download golang.org/a.
+	env := append(os.Environ(), "GOPROXY=off", "GOFLAGS=-mod=mod")
+	h := newApplyFixesHandler(context.Background(), io.Discard, &config{dir: dir, env: env})
+	h.OSV(&osv.Entry{ID: "GO-0000-0001"})
+	h.Finding(&govulncheck.Finding{OSV: "GO-0000-0001", FixedVersion: "v1.2.0", Trace: []*govulncheck.Frame{
+		{Module: "golang.org/a", Version: "v1.0.0", Package: "golang.org/a", Function: "F"},
+	}})
+	if err := h.Flush(); err == nil || !strings.Contains(err.Error(), "go mod tidy failed") {
+		t.Fatalf("got error %v, want a go mod tidy failure", err)
+	}
+	if data, err := os.ReadFile(file); err != nil || string(data) != applyFixesGoMod {
+		t.Errorf("go.mod not restored after go mod tidy failed: %s (%v)", data, err)
+	}
+}
+
+func TestNewModulePath(t *testing.T) {
+	for _, tc := range []struct {
+		module, version string
+		want            bool
+	}{
+		{"golang.org/d", "v1.5.0", false},
+		{"golang.org/d", "v2.0.0", true},
+		{"golang.org/d", "v2.0.0+incompatible", false},
+		{"golang.org/d/v2", "v2.1.0", false},
+		{"golang.org/d/v2", "v3.0.0", true},
+		{"gopkg.in/yaml.v2", "v2.4.0", false},
+		{"stdlib", "v1.20.5", false},
+	} {
+		if got := newModulePath(tc.module, tc.version); got != tc.want {
+			t.Errorf("newModulePath(%q, %q) = %t, want %t", tc.module, tc.version, got, tc.want)
+		}
+	}
+}
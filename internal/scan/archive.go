@@ -0,0 +1,216 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"debug/buildinfo"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Kinds of archive that can be scanned in binary mode.
+const (
+	archiveNone = ""
+	archiveZip  = "zip"
+	archiveTar  = "tar"
+	archiveTgz  = "tar.gz"
+)
+
+// archiveKind returns the kind of archive file is, from its extension
+// or, failing that, its first bytes, or archiveNone if it is not an
+// archive.
+func archiveKind(file string) string {
+	name := strings.ToLower(file)
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return archiveZip
+	case strings.HasSuffix(name, ".tar"):
+		return archiveTar
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return archiveTgz
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return archiveNone
+	}
+	defer f.Close()
+	head := make([]byte, 262)
+	n, _ := io.ReadFull(f, head)
+	head = head[:n]
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		return archiveZip
+	case bytes.HasPrefix(head, []byte("\x1f\x8b")):
+		return archiveTgz
+	case len(head) >= 262 && string(head[257:262]) == "ustar":
+		return archiveTar
+	}
+	return archiveNone
+}
+
+// Limits on what extractArchive writes, so that an archive cannot fill
+// the disk.
+var (
+	maxArchiveFileSize  int64 = 1 << 30 // per extracted file
+	maxArchiveTotalSize int64 = 4 << 30 // for all of them
+)
+
+// executableMagics are the first bytes of the executable formats that Go
+// binaries are built in: ELF, PE, Mach-O in either byte order, and
+// universal Mach-O.
+var executableMagics = [][]byte{
+	[]byte("\x7fELF"),
+	[]byte("MZ"),
+	{0xfe, 0xed, 0xfa, 0xce}, {0xfe, 0xed, 0xfa, 0xcf},
+	{0xce, 0xfa, 0xed, 0xfe}, {0xcf, 0xfa, 0xed, 0xfe},
+	{0xca, 0xfe, 0xba, 0xbe},
+}
+
+// isExecutable reports whether head, the first bytes of a file, are
+// those of an executable.
+func isExecutable(head []byte) bool {
+	for _, m := range executableMagics {
+		if bytes.HasPrefix(head, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractArchive extracts the regular files of the archive file, of the
+// given kind, that are executables to dir, and returns their paths within
+// the archive, in the archive's order. Files are written to the same
+// path under dir, with any ".." elements dropped so that nothing is
+// written outside of it. It fails if two executables have the same path
+// once cleaned, or if the files are larger than maxArchiveFileSize each
+// or maxArchiveTotalSize together.
+func extractArchive(file, kind, dir string) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	total := int64(0)
+	extract := func(name string, r io.Reader) error {
+		head := make([]byte, 4)
+		n, err := io.ReadFull(r, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		head = head[:n]
+		if !isExecutable(head) {
+			return nil
+		}
+		clean := strings.TrimPrefix(path.Clean("/"+name), "/")
+		if seen[clean] {
+			return fmt.Errorf("more than one executable at %s", clean)
+		}
+		seen[clean] = true
+		dst := filepath.Join(dir, filepath.FromSlash(clean))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return err
+		}
+		f, err := os.Create(dst)
+		if err != nil {
+			return err
+		}
+		limit := maxArchiveFileSize
+		if left := maxArchiveTotalSize - total; left < limit {
+			limit = left
+		}
+		written, err := io.Copy(f, io.LimitReader(io.MultiReader(bytes.NewReader(head), r), limit+1))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		total += written
+		switch {
+		case written <= limit:
+		case limit == maxArchiveFileSize:
+			return fmt.Errorf("%s is larger than %d bytes", name, maxArchiveFileSize)
+		default:
+			return fmt.Errorf("the executables are larger than %d bytes together", maxArchiveTotalSize)
+		}
+		names = append(names, clean)
+		return nil
+	}
+	if kind == archiveZip {
+		zr, err := zip.OpenReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		for _, zf := range zr.File {
+			if !zf.Mode().IsRegular() {
+				continue
+			}
+			r, err := zf.Open()
+			if err != nil {
+				return nil, err
+			}
+			err = extract(zf.Name, r)
+			r.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return names, nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if kind == archiveTgz {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		r = gr
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := extract(hdr.Name, tr); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// goBinaries returns the names of the files under dir that are Go
+// binaries, with the build information that govulncheck needs.
+func goBinaries(dir string, names []string) []string {
+	var bins []string
+	for _, name := range names {
+		if _, err := buildinfo.ReadFile(filepath.Join(dir, filepath.FromSlash(name))); err == nil {
+			bins = append(bins, name)
+		}
+	}
+	return bins
+}
+
+// archiveBinaryName returns the name by which findings refer to the
+// binary name of archive.
+func archiveBinaryName(archive, name string) string {
+	return fmt.Sprintf("%s:%s", archive, name)
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var archiveFiles = []struct{ name, body string }{
+	{"bin/tool", "\x7fELF tool"},
+	{"README", "readme"},
+	{"../escape", "MZ escape"},
+}
+
+func writeZip(t *testing.T, file string) {
+	writeZipFiles(t, file, archiveFiles)
+}
+
+func writeZipFiles(t *testing.T, file string, files []struct{ name, body string }) {
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, af := range files {
+		w, err := zw.Create(af.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(af.body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+}
+
+func writeTgz(t *testing.T, file string) {
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	tw.WriteHeader(&tar.Header{Name: "bin/", Typeflag: tar.TypeDir, Mode: 0o755})
+	for _, af := range archiveFiles {
+		tw.WriteHeader(&tar.Header{Name: af.name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(af.body))})
+		tw.Write([]byte(af.body))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	gw.Close()
+	f.Close()
+}
+
+func TestExtractArchive(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		write func(*testing.T, string)
+		kind  string
+	}{
+		{"release.zip", writeZip, archiveZip},
+		{"release.tgz", writeTgz, archiveTgz},
+		// Archives without a known extension are told by their contents.
+		{"release-zip", writeZip, archiveZip},
+		{"release-tgz", writeTgz, archiveTgz},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			file := filepath.Join(tmp, tc.name)
+			tc.write(t, file)
+			if got := archiveKind(file); got != tc.kind {
+				t.Fatalf("archiveKind = %q; want %q", got, tc.kind)
+			}
+			dir := filepath.Join(tmp, "out")
+			names, err := extractArchive(file, tc.kind, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Only executables are extracted.
+			want := []string{"bin/tool", "escape"}
+			if !reflect.DeepEqual(names, want) {
+				t.Errorf("got files %v; want %v", names, want)
+			}
+			if b, err := os.ReadFile(filepath.Join(dir, "escape")); err != nil || string(b) != "MZ escape" {
+				t.Errorf("../escape was not extracted into the directory: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "README")); err == nil {
+				t.Error("README was extracted")
+			}
+			// None of the files are Go binaries.
+			if bins := goBinaries(dir, names); len(bins) != 0 {
+				t.Errorf("got Go binaries %v; want none", bins)
+			}
+		})
+	}
+	if got := archiveKind("archive_test.go"); got != archiveNone {
+		t.Errorf("archiveKind of a Go file = %q; want none", got)
+	}
+}
+
+func TestExtractArchiveLimits(t *testing.T) {
+	tmp := t.TempDir()
+	dup := filepath.Join(tmp, "dup.zip")
+	writeZipFiles(t, dup, []struct{ name, body string }{{"bin/tool", "\x7fELF one"}, {"bin/./tool", "\x7fELF two"}})
+	if _, err := extractArchive(dup, archiveZip, filepath.Join(tmp, "dup")); err == nil || !strings.Contains(err.Error(), "more than one executable at bin/tool") {
+		t.Errorf("got error %v for two executables at bin/tool, want a duplicate error", err)
+	}
+
+	defer func(file, total int64) { maxArchiveFileSize, maxArchiveTotalSize = file, total }(maxArchiveFileSize, maxArchiveTotalSize)
+	maxArchiveFileSize, maxArchiveTotalSize = 8, 10
+	big := filepath.Join(tmp, "big.zip")
+	writeZipFiles(t, big, []struct{ name, body string }{{"a", "\x7fELF 12345"}})
+	if _, err := extractArchive(big, archiveZip, filepath.Join(tmp, "big")); err == nil || !strings.Contains(err.Error(), "a is larger than 8 bytes") {
+		t.Errorf("got error %v for a file over the limit, want a size error", err)
+	}
+	many := filepath.Join(tmp, "many.zip")
+	writeZipFiles(t, many, []struct{ name, body string }{{"a", "\x7fELF 1"}, {"b", "\x7fELF 2"}})
+	if _, err := extractArchive(many, archiveZip, filepath.Join(tmp, "many")); err == nil || !strings.Contains(err.Error(), "larger than 10 bytes together") {
+		t.Errorf("got error %v for files over the total limit, want a size error", err)
+	}
+}
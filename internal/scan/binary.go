@@ -11,7 +11,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 	"unicode"
 
 	"golang.org/x/vuln/internal/client"
@@ -21,23 +23,93 @@ import (
 
 // runBinary detects presence of vulnerable symbols in an executable.
 func runBinary(ctx context.Context, handler govulncheck.Handler, cfg *config, client *client.Client) error {
-	var exe *os.File
-	exe, err := os.Open(cfg.patterns[0])
-	if err != nil {
+	if len(cfg.tags) > 0 {
+		// Binaries are analyzed with the build configuration they were
+		// built with, so the tags are only recorded in the config.
+		p := &govulncheck.Progress{Message: fmt.Sprintf(binaryTagsMessage, strings.Join(cfg.tags, ","))}
+		if err := handler.Progress(p); err != nil {
+			return err
+		}
+	}
+	if cfg.archive != archiveNone {
+		return runArchive(ctx, handler, cfg, client)
+	}
+	if cfg.plugin {
+		return runPlugin(ctx, handler, cfg, client)
+	}
+	p := &govulncheck.Progress{Message: binaryProgressMessage}
+	if err := handler.Progress(p); err != nil {
 		return err
 	}
-	defer exe.Close()
+	return scanBinary(ctx, handler, cfg, client, cfg.patterns[0])
+}
 
-	p := &govulncheck.Progress{Message: binaryProgressMessage}
+// isPlugin reports whether file is a Go plugin, from its extension.
+func isPlugin(file string) bool {
+	return strings.HasSuffix(strings.ToLower(file), ".so")
+}
+
+// runPlugin scans a Go plugin, marking its findings as coming from it.
+// Plugins are read like executables, except that their build
+// information is approximated, as they are built without any.
+func runPlugin(ctx context.Context, handler govulncheck.Handler, cfg *config, client *client.Client) error {
+	plugin := cfg.patterns[0]
+	p := &govulncheck.Progress{Message: pluginProgressMessage}
 	if err := handler.Progress(p); err != nil {
 		return err
 	}
+	h := &targetHandler{Handler: handler, tag: func(f *govulncheck.Finding) { f.Binary = plugin }, seen: map[string]bool{}}
+	return scanBinary(ctx, h, cfg, client, plugin)
+}
+
+// runArchive extracts the Go binaries of an archive to a temporary
+// directory and scans each of them in turn.
+func runArchive(ctx context.Context, handler govulncheck.Handler, cfg *config, client *client.Client) error {
+	archive := cfg.patterns[0]
+	dir, err := os.MkdirTemp("", "govulncheck-archive-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	names, err := extractArchive(archive, cfg.archive, dir)
+	if err != nil {
+		return fmt.Errorf("govulncheck: extracting %s: %v", archive, err)
+	}
+	bins := goBinaries(dir, names)
+	if len(bins) == 0 {
+		return fmt.Errorf("govulncheck: archive %s contains no Go binaries", archive)
+	}
+	seen := map[string]bool{}
+	for i, name := range bins {
+		binary := archiveBinaryName(archive, name)
+		p := &govulncheck.Progress{Message: fmt.Sprintf(archiveProgressMessage, binary), Done: i + 1, Total: len(bins)}
+		if err := handler.Progress(p); err != nil {
+			return err
+		}
+		h := &targetHandler{Handler: handler, tag: func(f *govulncheck.Finding) { f.Binary = binary }, seen: seen}
+		if err := scanBinary(ctx, h, cfg, client, filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanBinary scans the binary at file and hands the results to handler.
+func scanBinary(ctx context.Context, handler govulncheck.Handler, cfg *config, client *client.Client, file string) error {
+	exe, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer exe.Close()
+	start := time.Now()
 	vr, err := vulncheck.Binary(ctx, exe, &cfg.Config, client)
 	if err != nil {
 		return fmt.Errorf("govulncheck: %v", err)
 	}
 	callstacks := binaryCallstacks(vr)
-	return emitResult(handler, vr, callstacks)
+	cfg.logTiming("fetching vulnerabilities", vr.FetchTime)
+	cfg.logTiming("matching vulnerabilities", time.Since(start)-vr.FetchTime)
+	return emitResult(handler, cfg, vr, callstacks)
 }
 
 func binaryCallstacks(vr *vulncheck.Result) map[*vulncheck.Vuln]vulncheck.CallStack {
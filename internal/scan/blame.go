@@ -0,0 +1,95 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// blameHandler wraps a handler and drops the findings whose trace does
+// not pass through a module, for the -blame flag, along with the OSV
+// entries that only they refer to. Any frame of the trace counts, not
+// only the vulnerable symbol, so that a vulnerability of a dependency of
+// the module is kept when the module calls it. Entries are held back
+// until a kept finding refers to them, as calledOnlyHandler does.
+//
+// Before flushing the wrapped handler, it gives the number of
+// vulnerabilities kept to summary, if any, for its summary.
+type blameHandler struct {
+	govulncheck.Handler
+	module  string
+	summary blameSummarizer
+	pending map[string]*osv.Entry
+	all     map[string]bool // IDs of the vulnerabilities with findings
+	kept    map[string]bool // IDs of those with findings through module
+}
+
+// blameSummarizer is implemented by the handlers that report the counts
+// of -blame in their summary.
+type blameSummarizer interface {
+	BlameSummary(blame *govulncheck.Blame)
+}
+
+// newBlameHandler returns a handler that only hands on the findings
+// through module, and reports the counts to summary, which may be nil.
+func newBlameHandler(h govulncheck.Handler, module string, summary blameSummarizer) *blameHandler {
+	return &blameHandler{
+		Handler: h,
+		module:  module,
+		summary: summary,
+		pending: map[string]*osv.Entry{},
+		all:     map[string]bool{},
+		kept:    map[string]bool{},
+	}
+}
+
+// OSV holds entry back until a kept finding refers to it.
+func (h *blameHandler) OSV(entry *osv.Entry) error {
+	if !h.kept[entry.ID] {
+		h.pending[entry.ID] = entry
+	}
+	return nil
+}
+
+// Finding hands finding on if its trace passes through the module,
+// preceded by its OSV entry the first time.
+func (h *blameHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	h.all[finding.OSV] = true
+	if !passesThrough(finding, h.module) {
+		return nil
+	}
+	h.kept[finding.OSV] = true
+	if entry, ok := h.pending[finding.OSV]; ok {
+		delete(h.pending, finding.OSV)
+		if err := h.Handler.OSV(entry); err != nil {
+			return err
+		}
+	}
+	return h.Handler.Finding(finding)
+}
+
+// Flush reports the number of vulnerabilities kept and flushes the
+// wrapped handler.
+func (h *blameHandler) Flush() error {
+	if h.summary != nil {
+		h.summary.BlameSummary(&govulncheck.Blame{Module: h.module, Kept: len(h.kept), Total: len(h.all)})
+	}
+	return Flush(h.Handler)
+}
+
+// passesThrough reports whether a frame of the trace of finding is in
+// module.
+func passesThrough(finding *govulncheck.Finding, module string) bool {
+	for _, frame := range finding.Trace {
+		if frame.Module == module {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/test"
+)
+
+func TestBlameHandler(t *testing.T) {
+	mock := test.NewMockHandler()
+	summary := &mockBlameSummarizer{}
+	h := newBlameHandler(mock, "golang.org/b", summary)
+	trace := func(mods ...string) []*govulncheck.Frame {
+		var frames []*govulncheck.Frame
+		for _, mod := range mods {
+			frames = append(frames, &govulncheck.Frame{Module: mod, Package: mod, Function: "F"})
+		}
+		return frames
+	}
+	for _, id := range []string{"GO-0000-0001", "GO-0000-0002", "GO-0000-0003"} {
+		if err := h.OSV(&osv.Entry{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []*govulncheck.Finding{
+		// Through golang.org/b, which calls the vulnerable golang.org/a.
+		{OSV: "GO-0000-0001", Trace: trace("golang.org/a", "golang.org/b", "golang.org/main")},
+		{OSV: "GO-0000-0001", Trace: trace("golang.org/a", "golang.org/main")},
+		// In golang.org/b itself.
+		{OSV: "GO-0000-0002", Trace: trace("golang.org/b")},
+		{OSV: "GO-0000-0003", Trace: trace("golang.org/a", "golang.org/main")},
+	} {
+		if err := h.Finding(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range mock.FindingMessages {
+		got = append(got, f.OSV)
+	}
+	if len(got) != 2 || got[0] != "GO-0000-0001" || got[1] != "GO-0000-0002" || len(mock.FindingMessages[0].Trace) != 3 {
+		t.Errorf("got findings %v; want the ones through golang.org/b", mock.FindingMessages)
+	}
+	var osvs []string
+	for _, e := range mock.OSVMessages {
+		osvs = append(osvs, e.ID)
+	}
+	if len(osvs) != 2 || osvs[0] != "GO-0000-0001" || osvs[1] != "GO-0000-0002" {
+		t.Errorf("got OSV entries %v; want those of the kept findings", osvs)
+	}
+	want := govulncheck.Blame{Module: "golang.org/b", Kept: 2, Total: 3}
+	if summary.blame == nil || *summary.blame != want {
+		t.Errorf("got blame summary %+v; want %+v", summary.blame, want)
+	}
+}
+
+type mockBlameSummarizer struct {
+	blame *govulncheck.Blame
+}
+
+func (m *mockBlameSummarizer) BlameSummary(blame *govulncheck.Blame) {
+	m.blame = blame
+}
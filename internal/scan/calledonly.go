@@ -0,0 +1,55 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// calledOnlyHandler wraps a handler and drops the informational findings,
+// along with the OSV entries that only they refer to. Entries are held
+// back until a called finding refers to them, and are then handed on
+// right before it, so they still come ahead of their findings.
+type calledOnlyHandler struct {
+	govulncheck.Handler
+	pending map[string]*osv.Entry
+	sent    map[string]bool
+}
+
+func newCalledOnlyHandler(h govulncheck.Handler) *calledOnlyHandler {
+	return &calledOnlyHandler{Handler: h, pending: map[string]*osv.Entry{}, sent: map[string]bool{}}
+}
+
+// OSV holds entry back until a called finding refers to it.
+func (h *calledOnlyHandler) OSV(entry *osv.Entry) error {
+	if !h.sent[entry.ID] {
+		h.pending[entry.ID] = entry
+	}
+	return nil
+}
+
+// Finding hands finding on if it is called, preceded by its OSV entry
+// the first time.
+func (h *calledOnlyHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	if finding.Trace[0].Function == "" {
+		return nil
+	}
+	if entry, ok := h.pending[finding.OSV]; ok {
+		delete(h.pending, finding.OSV)
+		h.sent[finding.OSV] = true
+		if err := h.Handler.OSV(entry); err != nil {
+			return err
+		}
+	}
+	return h.Handler.Finding(finding)
+}
+
+func (h *calledOnlyHandler) Flush() error {
+	return Flush(h.Handler)
+}
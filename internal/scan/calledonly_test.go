@@ -0,0 +1,38 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/test"
+)
+
+func TestCalledOnlyHandler(t *testing.T) {
+	mock := test.NewMockHandler()
+	h := newCalledOnlyHandler(mock)
+	findings := testFindings()
+	// An informational finding of the called GO-0000-0001, and another
+	// call of it.
+	findings = append(findings,
+		&govulncheck.Finding{OSV: "GO-0000-0001", Trace: []*govulncheck.Frame{{Module: "golang.org/vmod", Version: "v1.0.0", Package: "golang.org/vmod"}}},
+		&govulncheck.Finding{OSV: "GO-0000-0001", Trace: []*govulncheck.Frame{{Module: "golang.org/vmod", Version: "v1.0.0", Package: "golang.org/vmod", Function: "Other"}}},
+	)
+	if err := runHandler(t, h, testEntries(), findings); err != nil {
+		t.Fatal(err)
+	}
+	if len(mock.OSVMessages) != 1 || mock.OSVMessages[0].ID != "GO-0000-0001" {
+		t.Errorf("got OSV messages %v; want only GO-0000-0001", mock.OSVMessages)
+	}
+	if len(mock.FindingMessages) != 2 {
+		t.Fatalf("got %d findings; want the 2 called ones", len(mock.FindingMessages))
+	}
+	for _, f := range mock.FindingMessages {
+		if f.Trace[0].Function == "" {
+			t.Errorf("got informational finding %+v", f)
+		}
+	}
+}
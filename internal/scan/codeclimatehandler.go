@@ -0,0 +1,184 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// codeClimateIssue is an issue of the Code Climate report format, as read
+// by GitLab code quality reports. See
+// https://github.com/codeclimate/platform/blob/master/spec/analyzers/SPEC.md#issues.
+type codeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+	Location    codeClimateLocation `json:"location"`
+}
+
+type codeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines codeClimateLines `json:"lines"`
+}
+
+type codeClimateLines struct {
+	Begin int `json:"begin"`
+}
+
+// codeClimateHandler writes a Code Climate report with an issue for each
+// call stack of a called vulnerability.
+type codeClimateHandler struct {
+	w        io.Writer
+	root     string // directory the paths of the issues are relative to
+	missing  string // how positions in files that no longer exist are handled
+	osvs     []*osv.Entry
+	findings []*findingSummary
+}
+
+// newCodeClimateHandler returns a handler that writes a Code Climate
+// report to w, with paths relative to dir. Issues at positions in files
+// that no longer exist are handled as missing says: they are located at
+// the vulnerable module instead, and with missingFlag their description
+// says that the file was not found.
+func newCodeClimateHandler(w io.Writer, dir, missing string) *codeClimateHandler {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		root = dir
+	}
+	return &codeClimateHandler{w: w, root: root, missing: missing}
+}
+
+func (h *codeClimateHandler) Config(config *govulncheck.Config) error {
+	return nil
+}
+
+func (h *codeClimateHandler) Progress(progress *govulncheck.Progress) error {
+	return nil
+}
+
+// OSV gathers osv entries to be reported.
+func (h *codeClimateHandler) OSV(entry *osv.Entry) error {
+	h.osvs = append(h.osvs, entry)
+	return nil
+}
+
+// Finding gathers the called vulnerability findings to be reported.
+func (h *codeClimateHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	if finding.Trace[0].Function != "" {
+		summary := newFindingSummary(finding)
+		summary.Compact = compactTrace(finding, symbolQualified, h.missing)
+		h.findings = append(h.findings, summary)
+	}
+	return nil
+}
+
+// Flush writes the report, in the order of the text output. The report
+// is an empty array if nothing is called.
+func (h *codeClimateHandler) Flush() error {
+	fixupFindings(h.osvs, h.findings)
+	issues := []*codeClimateIssue{}
+	seen := map[string]bool{}
+	for _, findings := range groupByVuln(h.findings) {
+		for _, f := range findings {
+			issue := h.issue(f)
+			if seen[issue.Fingerprint] {
+				continue
+			}
+			seen[issue.Fingerprint] = true
+			issues = append(issues, issue)
+		}
+	}
+	b, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = h.w.Write(append(b, '\n'))
+	return err
+}
+
+// issue returns the issue of the call stack of f, located where the
+// scanned code makes the call, or at the vulnerable module if the
+// stack has no positions, as in binaries.
+func (h *codeClimateHandler) issue(f *findingSummary) *codeClimateIssue {
+	description := f.OSV.Summary
+	if description == "" {
+		description = f.OSV.Details
+	}
+	description = f.OSV.ID + ": " + description
+	if f.Compact != "" {
+		description += " (" + f.Compact + ")"
+	}
+	location := codeClimateLocation{Path: f.Trace[0].Module, Lines: codeClimateLines{Begin: 1}}
+	if p := f.Trace[topFrame(f.Trace)].Position; p != nil && p.Filename != "" && p.Line > 0 && !fileMissing(p, h.missing) {
+		location = codeClimateLocation{Path: h.relPath(p.Filename), Lines: codeClimateLines{Begin: p.Line}}
+	}
+	return &codeClimateIssue{
+		Type:        "issue",
+		CheckName:   "govulncheck/" + f.OSV.ID,
+		Description: description,
+		Categories:  []string{"Security"},
+		Severity:    codeClimateSeverity(f.OSV),
+		Fingerprint: codeClimateFingerprint(f.Finding),
+		Location:    location,
+	}
+}
+
+// relPath returns filename relative to the root of h, with forward
+// slashes, or filename itself if it is not under the root.
+func (h *codeClimateHandler) relPath(filename string) string {
+	if rel, err := filepath.Rel(h.root, filename); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(filename)
+}
+
+// codeClimateSeverity maps the severity of entry in the Go vulnerability
+// database to a Code Climate severity. A called vulnerability of unknown
+// severity is major.
+func codeClimateSeverity(entry *osv.Entry) string {
+	if entry.DatabaseSpecific == nil {
+		return "major"
+	}
+	switch strings.ToUpper(entry.DatabaseSpecific.Severity) {
+	case "CRITICAL":
+		return "critical"
+	case "HIGH":
+		return "major"
+	case "MODERATE", "MEDIUM":
+		return "minor"
+	case "LOW":
+		return "info"
+	default:
+		return "major"
+	}
+}
+
+// codeClimateFingerprint returns a fingerprint of the vulnerability and
+// the symbols of the call stack of f. Positions and versions are left out,
+// so that the fingerprint stays the same when unrelated code moves or
+// the module is upgraded to another vulnerable version.
+func codeClimateFingerprint(f *govulncheck.Finding) string {
+	hash := sha256.New()
+	fmt.Fprintln(hash, f.OSV)
+	for _, frame := range f.Trace {
+		fmt.Fprintln(hash, frame.Module, frame.Package, frame.Receiver, frame.Function)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
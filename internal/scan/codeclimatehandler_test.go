@@ -0,0 +1,108 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestCodeClimateHandler(t *testing.T) {
+	root := t.TempDir()
+	run := func(line int) []*codeClimateIssue {
+		var buf strings.Builder
+		h := newCodeClimateHandler(&buf, root, missingKeep)
+		entries := testEntries()
+		entries[0].DatabaseSpecific.Severity = "HIGH"
+		findings := testFindings()
+		findings[0].Trace[1].Position = &govulncheck.Position{Filename: filepath.Join(root, "cmd", "main.go"), Line: line}
+		// A called finding without a position. The informational
+		// finding of the shared scan is not reported.
+		findings = append(findings, &govulncheck.Finding{OSV: "GO-0000-0002", Trace: []*govulncheck.Frame{{Module: "golang.org/vmod", Package: "golang.org/vmod", Function: "Leak"}}})
+		if err := runHandler(t, h, entries, findings); err != nil {
+			t.Fatal(err)
+		}
+		var issues []*codeClimateIssue
+		if err := json.Unmarshal([]byte(buf.String()), &issues); err != nil {
+			t.Fatal(err)
+		}
+		return issues
+	}
+	issues := run(12)
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues; want 2", len(issues))
+	}
+	byID := map[string]*codeClimateIssue{}
+	for _, issue := range issues {
+		byID[issue.CheckName] = issue
+	}
+	called := byID["govulncheck/GO-0000-0001"]
+	if called == nil {
+		t.Fatalf("no issue for GO-0000-0001 in %v", issues)
+	}
+	if called.Severity != "major" || called.Location.Path != "cmd/main.go" || called.Location.Lines.Begin != 12 {
+		t.Errorf("got issue %+v; want a major issue at cmd/main.go:12", called)
+	}
+	if !strings.HasPrefix(called.Description, "GO-0000-0001: Crash in parser") {
+		t.Errorf("got description %q", called.Description)
+	}
+	noPos := byID["govulncheck/GO-0000-0002"]
+	if noPos == nil || noPos.Location.Path != "golang.org/vmod" || noPos.Severity != "major" {
+		t.Errorf("got issue %+v; want a major issue located at golang.org/vmod", noPos)
+	}
+	// Moving the call keeps the fingerprint.
+	for _, issue := range run(20) {
+		if want := byID[issue.CheckName].Fingerprint; issue.Fingerprint != want {
+			t.Errorf("%s: fingerprint changed from %s to %s", issue.CheckName, want, issue.Fingerprint)
+		}
+	}
+}
+
+func TestCodeClimateMissingFiles(t *testing.T) {
+	root := t.TempDir()
+	for _, missing := range []string{missingOmit, missingFlag} {
+		var buf strings.Builder
+		h := newCodeClimateHandler(&buf, root, missing)
+		findings := testFindings()
+		findings[0].Trace[1].Position = &govulncheck.Position{Filename: filepath.Join(root, "gen.go"), Line: 12}
+		if err := runHandler(t, h, testEntries(), findings); err != nil {
+			t.Fatal(err)
+		}
+		var issues []*codeClimateIssue
+		if err := json.Unmarshal([]byte(buf.String()), &issues); err != nil {
+			t.Fatal(err)
+		}
+		if len(issues) != 1 || issues[0].Location.Path != "golang.org/vmod" {
+			t.Fatalf("%s: got issues %+v; want one located at golang.org/vmod", missing, issues)
+		}
+		flagged := strings.Contains(issues[0].Description, "gen.go:12 (file not found)")
+		if want := missing == missingFlag; flagged != want {
+			t.Errorf("%s: got description %q; flagged %v, want %v", missing, issues[0].Description, flagged, want)
+		}
+	}
+}
+
+func TestCodeClimateSeverity(t *testing.T) {
+	for _, test := range []struct {
+		severity string
+		want     string
+	}{
+		{"CRITICAL", "critical"},
+		{"HIGH", "major"},
+		{"MODERATE", "minor"},
+		{"LOW", "info"},
+		{"", "major"},
+	} {
+		entry := &osv.Entry{DatabaseSpecific: &osv.DatabaseSpecific{Severity: test.severity}}
+		if got := codeClimateSeverity(entry); got != test.want {
+			t.Errorf("codeClimateSeverity(%q) = %q; want %q", test.severity, got, test.want)
+		}
+	}
+}
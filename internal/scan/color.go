@@ -24,6 +24,10 @@ const (
 	fgCyan    = colorEscape + "36" + colorEnd
 	fgWhite   = colorEscape + "37" + colorEnd
 
+	// fgOrange uses the 256 color palette, as there is no orange among
+	// the basic colors.
+	fgOrange = colorEscape + "38;5;208" + colorEnd
+
 	bgBlack   = colorEscape + "40" + colorEnd
 	bgRed     = colorEscape + "41" + colorEnd
 	bgGreen   = colorEscape + "42" + colorEnd
@@ -0,0 +1,71 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+var cweRegexp = regexp.MustCompile(`^CWE-[0-9]+$`)
+
+// cwes returns the IDs of the CWE weaknesses of entry, from its
+// database-specific data and from those of its aliases that are CWE IDs,
+// without duplicates and by number.
+func cwes(entry *osv.Entry) []string {
+	seen := map[string]bool{}
+	var ids []string
+	add := func(id string) {
+		id = strings.ToUpper(strings.TrimSpace(id))
+		if cweRegexp.MatchString(id) && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if entry.DatabaseSpecific != nil {
+		for _, id := range entry.DatabaseSpecific.CWEIDs {
+			add(id)
+		}
+	}
+	for _, alias := range entry.Aliases {
+		add(alias)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		n, _ := strconv.Atoi(strings.TrimPrefix(ids[i], "CWE-"))
+		m, _ := strconv.Atoi(strings.TrimPrefix(ids[j], "CWE-"))
+		return n < m
+	})
+	return ids
+}
+
+// cweHandler wraps a handler and sets the CWE IDs of each OSV entry to
+// all those found by cwes, so that JSON output has them in one place.
+type cweHandler struct {
+	govulncheck.Handler
+}
+
+func (h *cweHandler) OSV(entry *osv.Entry) error {
+	if ids := cwes(entry); len(ids) > 0 {
+		// Change a copy, as the entry may be shared with the scan.
+		e := *entry
+		ds := osv.DatabaseSpecific{}
+		if entry.DatabaseSpecific != nil {
+			ds = *entry.DatabaseSpecific
+		}
+		ds.CWEIDs = ids
+		e.DatabaseSpecific = &ds
+		entry = &e
+	}
+	return h.Handler.OSV(entry)
+}
+
+func (h *cweHandler) Flush() error {
+	return Flush(h.Handler)
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/test"
+)
+
+func TestCWEs(t *testing.T) {
+	entry := &osv.Entry{
+		ID:               "GO-0000-0001",
+		Aliases:          []string{"CVE-0000-0001", "CWE-89", "cwe-100"},
+		DatabaseSpecific: &osv.DatabaseSpecific{CWEIDs: []string{"CWE-89", "CWE-79", "not a CWE"}},
+	}
+	want := []string{"CWE-79", "CWE-89", "CWE-100"}
+	if got := cwes(entry); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+	if got := cwes(&osv.Entry{ID: "GO-0000-0002"}); got != nil {
+		t.Errorf("got %v for an entry without CWE data; want none", got)
+	}
+}
+
+func TestCWEHandler(t *testing.T) {
+	mock := test.NewMockHandler()
+	h := &cweHandler{Handler: mock}
+	entry := &osv.Entry{ID: "GO-0000-0001", Aliases: []string{"CWE-79"}}
+	other := &osv.Entry{ID: "GO-0000-0002"}
+	for _, e := range []*osv.Entry{entry, other} {
+		if err := h.OSV(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := mock.OSVMessages[0].DatabaseSpecific; got == nil || !reflect.DeepEqual(got.CWEIDs, []string{"CWE-79"}) {
+		t.Errorf("got database-specific data %+v; want CWE IDs [CWE-79]", got)
+	}
+	if entry.DatabaseSpecific != nil {
+		t.Errorf("the original entry was changed")
+	}
+	if mock.OSVMessages[1] != other {
+		t.Errorf("an entry without CWE data was changed")
+	}
+}
+
+func TestShowCWE(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		entry *osv.Entry
+		want  string
+	}{
+		{"present", &osv.Entry{ID: "GO-0000-0001", DatabaseSpecific: &osv.DatabaseSpecific{CWEIDs: []string{"CWE-89", "CWE-79"}}}, "  CWE: CWE-79, CWE-89\n"},
+		{"absent", &osv.Entry{ID: "GO-0000-0001", DatabaseSpecific: &osv.DatabaseSpecific{}}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf strings.Builder
+			h := NewTextHandler(&buf)
+			h.Show([]string{showCWE})
+			h.OSV(tc.entry)
+			h.Finding(&govulncheck.Finding{OSV: "GO-0000-0001", Trace: []*govulncheck.Frame{{Module: "golang.org/a", Package: "golang.org/a", Function: "V"}}})
+			h.Flush()
+			if tc.want == "" {
+				if strings.Contains(buf.String(), "CWE:") {
+					t.Errorf("output has a CWE line:\n%s", buf.String())
+				}
+			} else if !strings.Contains(buf.String(), tc.want) {
+				t.Errorf("output does not contain %q:\n%s", tc.want, buf.String())
+			}
+		})
+	}
+}
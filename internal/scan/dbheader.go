@@ -0,0 +1,88 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// parseDBHeaders parses the Key:Value values of -db-header into the
+// headers of database requests. Errors name the offending header but
+// never show its value, which may be a secret.
+func parseDBHeaders(values []string) (http.Header, error) {
+	header := http.Header{}
+	for _, kv := range values {
+		key, value, ok := strings.Cut(kv, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("the -db-header flag must be of the form Key:Value")
+		}
+		if !validHeaderName(key) {
+			return nil, fmt.Errorf("%q is not a valid -db-header name", key)
+		}
+		value = strings.TrimSpace(value)
+		if strings.ContainsAny(value, "\r\n\x00") {
+			return nil, fmt.Errorf("the value of -db-header %s contains a line break or NUL", key)
+		}
+		header.Add(key, value)
+	}
+	return header, nil
+}
+
+// validHeaderName reports whether name is an HTTP header field name,
+// that is, a token of RFC 7230.
+func validHeaderName(name string) bool {
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
+
+// secretHeaderWords are the parts of header names that mark their values
+// as secrets.
+var secretHeaderWords = []string{"auth", "token", "secret", "key", "password", "passwd", "cookie", "session", "credential", "signature"}
+
+// looksSecret reports whether the header key: value looks like it holds
+// a secret, from its name or from a credential scheme in its value.
+func looksSecret(key, value string) bool {
+	k := strings.ToLower(key)
+	for _, w := range secretHeaderWords {
+		if strings.Contains(k, w) {
+			return true
+		}
+	}
+	scheme, _, _ := strings.Cut(strings.ToLower(value), " ")
+	return scheme == "bearer" || scheme == "basic" || scheme == "digest"
+}
+
+// logDBHeaders writes the headers of database requests to cfg.timings,
+// with -verbose. The values of headers that look like secrets are
+// replaced with [redacted].
+func logDBHeaders(cfg *config) {
+	if cfg.timings == nil {
+		return
+	}
+	keys := make([]string, 0, len(cfg.headers))
+	for key := range cfg.headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range cfg.headers[key] {
+			if looksSecret(key, value) {
+				value = "[redacted]"
+			}
+			fmt.Fprintf(cfg.timings, "govulncheck: sending header %s: %s with database requests\n", key, value)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseDBHeaders(t *testing.T) {
+	got, err := parseDBHeaders([]string{"X-Tenant-ID: acme", "x-route:eu:west", "X-Tenant-ID:beta"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := http.Header{"X-Tenant-Id": {"acme", "beta"}, "X-Route": {"eu:west"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	for _, value := range []string{"s3cret", ":s3cret", "Bad Name:s3cret", "X-Token:s3c\r\nret"} {
+		_, err := parseDBHeaders([]string{value})
+		if err == nil {
+			t.Errorf("parseDBHeaders(%q): got no error", value)
+			continue
+		}
+		// The value of the header must not show in the error.
+		if strings.Contains(err.Error(), "s3c") {
+			t.Errorf("parseDBHeaders(%q): error %q shows the value", value, err)
+		}
+	}
+}
+
+func TestLogDBHeaders(t *testing.T) {
+	var buf strings.Builder
+	cfg := &config{timings: &buf, headers: http.Header{
+		"X-Tenant-Id":   {"acme"},
+		"X-Api-Key":     {"k3y"},
+		"Authorization": {"Bearer t0ken"},
+		"X-Forward":     {"Basic dXNlcjpwYXNz"},
+	}}
+	logDBHeaders(cfg)
+	want := `govulncheck: sending header Authorization: [redacted] with database requests
+govulncheck: sending header X-Api-Key: [redacted] with database requests
+govulncheck: sending header X-Forward: [redacted] with database requests
+govulncheck: sending header X-Tenant-Id: acme with database requests
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
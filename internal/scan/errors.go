@@ -6,6 +6,7 @@ package scan
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -25,6 +26,20 @@ var (
 	// govulncheck, and print the usage message with exit status 2.
 	errUsage = &exitCodeError{message: "invalid usage", code: 2}
 
+	// errNothingToScan indicates that no package patterns were given, or
+	// read, and the -allow-empty flag was set. It exits successfully.
+	errNothingToScan = &exitCodeError{message: "no package patterns", code: 0}
+
+	// errNoPatterns indicates that reading package patterns from a file
+	// or from standard input produced none.
+	errNoPatterns = errors.New("no package patterns provided")
+
+	// errNoPackages indicates that the package patterns matched no
+	// packages, and the -require-packages flag was set.
+	errNoPackages = errors.New(`the package patterns matched no packages to scan, and -require-packages was given
+
+Check the patterns, and the directory govulncheck is run in.`)
+
 	// errGoVersionMismatch is used to indicate that there is a mismatch between
 	// the Go version used to build govulncheck and the one currently on PATH.
 	errGoVersionMismatch = errors.New(`Loading packages failed, possibly due to a mismatch between the Go version
@@ -51,10 +66,117 @@ For details, run govulncheck -h.`)
 type exitCodeError struct {
 	message string
 	code    int
+	err     error // cause of the error, if any
 }
 
 func (e *exitCodeError) Error() string { return e.message }
 func (e *exitCodeError) ExitCode() int { return e.code }
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+// usageError returns a usage error, like errUsage, caused by err.
+func usageError(err error) error {
+	return &exitCodeError{message: errUsage.message, code: errUsage.code, err: err}
+}
+
+// joinErrors returns an error that wraps the non-nil errors of errs, or
+// nil if there are none, like errors.Join, which is not available in all
+// the Go versions that govulncheck builds with.
+func joinErrors(errs ...error) error {
+	var e joinError
+	for _, err := range errs {
+		if err != nil {
+			e = append(e, err)
+		}
+	}
+	switch len(e) {
+	case 0:
+		return nil
+	case 1:
+		return e[0]
+	}
+	return e
+}
+
+type joinError []error
+
+func (e joinError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e joinError) Unwrap() []error { return e }
+
+// Kinds of ConfigError, which tell which check of the configuration
+// failed. Use errors.Is to test for them.
+var (
+	// ErrUnsupportedMode indicates that the -mode flag names no mode.
+	ErrUnsupportedMode = errors.New("unsupported mode")
+
+	// ErrUnsupportedFormat indicates that the -format flag names no
+	// output format.
+	ErrUnsupportedFormat = errors.New("unsupported format")
+
+	// ErrInvalidFlagValue indicates that the value of a flag is not one
+	// it accepts.
+	ErrInvalidFlagValue = errors.New("invalid flag value")
+
+	// ErrUnreadableFile indicates that a file named by a flag cannot be
+	// read or parsed.
+	ErrUnreadableFile = errors.New("unreadable file")
+
+	// ErrUnsupportedForFormat indicates that a flag was given that does
+	// not apply to the output format.
+	ErrUnsupportedForFormat = errors.New("flag not supported for the output format")
+
+	// ErrUnsupportedInMode indicates that a flag was given that does not
+	// apply to the mode, or that a flag the mode needs was not given.
+	ErrUnsupportedInMode = errors.New("flag not supported in the mode")
+
+	// ErrBinaryModeTest indicates that the -test flag was given in
+	// binary mode. It is also an ErrUnsupportedInMode.
+	ErrBinaryModeTest = fmt.Errorf("%w: -test in binary mode", ErrUnsupportedInMode)
+
+	// ErrIncompatibleFlags indicates that flags, or a flag and the
+	// patterns, were given that cannot be used together.
+	ErrIncompatibleFlags = errors.New("incompatible flags")
+
+	// ErrInvalidPatterns indicates that the patterns cannot be scanned
+	// in the mode, for instance because there are too many of them.
+	ErrInvalidPatterns = errors.New("invalid patterns")
+)
+
+// A ConfigError reports a configuration of govulncheck that cannot be
+// run, found by checking the flags and patterns before anything is
+// scanned. It is the cause of the usage error that is returned then.
+type ConfigError struct {
+	// Kind is the check that failed, such as ErrUnsupportedMode.
+	Kind error
+
+	// Flag is the name of the flag at fault, without a dash, or "" if
+	// the patterns are.
+	Flag string
+
+	// Err describes the failure, as printed to the user. It wraps the
+	// cause of the failure, if any, such as the error of reading a file.
+	Err error
+}
+
+func (e *ConfigError) Error() string { return e.Err.Error() }
+
+// Is reports whether target is the kind of e, so that errors.Is finds
+// both the kind and, through Unwrap, the cause.
+func (e *ConfigError) Is(target error) bool { return errors.Is(e.Kind, target) }
+
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// configErrorf returns a ConfigError of the given kind for flag, whose
+// message is formatted as by fmt.Errorf.
+func configErrorf(kind error, flag, format string, args ...any) error {
+	return &ConfigError{Kind: kind, Flag: flag, Err: fmt.Errorf(format, args...)}
+}
 
 // isGoVersionMismatchError checks if err is due to mismatch between
 // the Go version used to build govulncheck and the one currently
@@ -0,0 +1,86 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"io"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/vuln/internal"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// fixHandler writes a script of the commands that upgrade every module
+// with a finding to a version where all of its fixable findings are
+// fixed. Modules without any fixed version are skipped. For the
+// standard library and the go command, the script recommends a Go
+// version instead.
+type fixHandler struct {
+	w        io.Writer
+	findings []*findingSummary
+}
+
+// newFixHandler returns a handler that writes upgrade commands to w.
+func newFixHandler(w io.Writer) *fixHandler {
+	return &fixHandler{w: w}
+}
+
+func (h *fixHandler) Config(config *govulncheck.Config) error {
+	return nil
+}
+
+func (h *fixHandler) Progress(progress *govulncheck.Progress) error {
+	return nil
+}
+
+func (h *fixHandler) OSV(entry *osv.Entry) error {
+	return nil
+}
+
+// Finding gathers vulnerability findings to be fixed.
+func (h *fixHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	h.findings = append(h.findings, newFindingSummary(finding))
+	return nil
+}
+
+// Flush writes one command per module, in module path order, with the
+// Go recommendation last.
+func (h *fixHandler) Flush() error {
+	goFix := ""
+	for _, module := range groupByModule(h.findings) {
+		mod := module[0].Trace[0].Module
+		fixed := ""
+		for _, f := range module {
+			if f.FixedVersion != "" && semver.Compare(f.FixedVersion, fixed) > 0 {
+				fixed = f.FixedVersion
+			}
+		}
+		switch {
+		case fixed == "":
+			continue
+		case mod == internal.GoStdModulePath || mod == internal.GoCmdModulePath:
+			if semver.Compare(fixed, goFix) > 0 {
+				goFix = fixed
+			}
+		default:
+			if _, err := fmt.Fprintf(h.w, "go get %s@%s\n", mod, fixed); err != nil {
+				return err
+			}
+		}
+	}
+	if goFix != "" {
+		// Go itself cannot be upgraded with go get, so this is
+		// only a comment in the script.
+		if _, err := fmt.Fprintf(h.w, "# upgrade Go to %s or later\n", semverToGoTag(goFix)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+func TestFixHandler(t *testing.T) {
+	frame := func(mod string) []*govulncheck.Frame {
+		return []*govulncheck.Frame{{Module: mod, Package: mod, Function: "F"}}
+	}
+	findings := []*govulncheck.Finding{
+		{OSV: "GO-0000-0001", FixedVersion: "v1.2.0", Trace: frame("golang.org/b")},
+		{OSV: "GO-0000-0002", FixedVersion: "v1.10.0", Trace: frame("golang.org/b")},
+		{OSV: "GO-0000-0003", FixedVersion: "v0.3.0", Trace: frame("golang.org/a")},
+		{OSV: "GO-0000-0004", Trace: frame("golang.org/nofix")},
+		{OSV: "GO-0000-0005", FixedVersion: "v1.20.5", Trace: frame("stdlib")},
+		{OSV: "GO-0000-0006", FixedVersion: "v1.21.1", Trace: frame("toolchain")},
+	}
+	var buf strings.Builder
+	h := newFixHandler(&buf)
+	for _, f := range findings {
+		if err := h.Finding(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := `go get golang.org/a@v0.3.0
+go get golang.org/b@v1.10.0
+# upgrade Go to go1.21.1 or later
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -5,28 +5,101 @@
 package scan
 
 import (
+	"bufio"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"golang.org/x/mod/module"
 	"golang.org/x/tools/go/buildutil"
 	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/web"
 )
 
 type config struct {
 	govulncheck.Config
-	patterns []string
-	mode     string
-	db       string
-	json     bool
-	dir      string
-	tags     []string
-	test     bool
-	show     []string
-	env      []string
+	patterns     []string
+	mode         string
+	db           string
+	json         bool
+	format       string
+	dir          string
+	tags         []string
+	test         bool
+	testOnly     bool
+	show         []string
+	env          []string
+	pkgFile      string
+	strict       bool
+	platform     string
+	retries      int
+	dbRate       float64
+	dbHeaders    []string    // -db-header values, as Key:Value
+	headers      http.Header // parsed from dbHeaders
+	dbSchema     int
+	maxDBAge     time.Duration
+	minStacks    int
+	indent       string
+	compactWidth int
+	width        int
+	redact       bool
+	redacted     []string
+	colorBy      string
+	group        string
+	allowEmpty   bool
+	verbose      bool
+	noFooter     bool
+	machineText  bool
+	stripANSI    bool
+	metrics      string
+	pushgateway  string
+	pushJob      string
+	pushInstance string
+	syslog       bool
+	syslogFac    string
+	syslogTag    string
+	sortBy       string
+	top          int
+	maxFindings  int
+	symbolFormat string
+	calledOnly   bool
+	blame        string
+	splitFixable bool
+	noTraces     bool
+	errorMods    []string
+	verify       bool
+	indexOnly    bool
+	checkOnly    bool
+	marker       string
+	missing      string
+	lang         string
+	ignoreFile   string
+	ignored      *suppressions // read from ignoreFile
+	exclude      []string
+	excluded     *suppressions // from exclude
+	severityFile string
+	overrides    map[string]string // read from severityFile
+	plan         bool
+	applyFixes   bool
+	dryRun       bool
+	changed      []string
+	modfiles     []string
+	directOnly   bool
+	requirePkgs  bool
+	pid          int
+	archive      string          // kind of archive of binaries to scan, if any
+	plugin       bool            // whether the binary to scan is a Go plugin
+	timings      io.Writer       // where -verbose timings are written, if non-nil
+	set          map[string]bool // the flags given on the command line
 }
 
 const (
@@ -34,20 +107,99 @@ const (
 	modeSource  = "source"
 	modeConvert = "convert" // only intended for use by gopls
 	modeQuery   = "query"   // only intended for use by gopls
+	modeTrend   = "trend"
+	modeMerge   = "merge"
 )
 
-func parseFlags(cfg *config, stderr io.Writer, args []string) error {
+const (
+	formatText        = "text"
+	formatJSON        = "json"
+	formatOSV         = "osv"
+	formatFix         = "fix"
+	formatSPDX        = "spdx-vuln"
+	formatTeamCity    = "teamcity"
+	formatNDJSON      = "ndjson-findings"
+	formatCodeClimate = "codeclimate"
+	formatOSVScanner  = "osv-scanner"
+	formatRSS         = "rss"
+)
+
+func parseFlags(cfg *config, stdin io.Reader, stderr io.Writer, args []string) error {
 	var tagsFlag buildutil.TagsFlag
-	var showFlag showFlag
+	var redactFlag showFlag
+	var changedFlag showFlag
+	var modfileFlag showFlag
+	var excludeFlag showFlag
+	var errorModsFlag showFlag
+	var showFlag showOptionsFlag
 	flags := flag.NewFlagSet("", flag.ContinueOnError)
 	flags.SetOutput(stderr)
-	flags.BoolVar(&cfg.json, "json", false, "output JSON")
+	flags.BoolVar(&cfg.json, "json", false, "output JSON (same as -format=json)")
+	flags.StringVar(&cfg.format, "format", "", "set the output format, one of text, json, ndjson-findings, osv, fix, spdx-vuln, teamcity, codeclimate, osv-scanner or rss (default \"text\")")
+	flags.BoolVar(&cfg.strict, "strict", false, "fail on data-quality issues in the vulnerability database")
+	flags.BoolVar(&cfg.verbose, "verbose", false, "log the time taken by each phase of the analysis to standard error")
+	flags.BoolVar(&cfg.stripANSI, "strip-ansi", false, "remove all terminal escape sequences from text output, even with -show=color")
+	flags.BoolVar(&cfg.noFooter, "no-footer-on-clean", false, "omit the closing feedback message from text output when no vulnerabilities are called")
+	flags.BoolVar(&cfg.machineText, "machine-text", false, "write text output as lines with stable VULN:, MODULE:, TRACE: and SUMMARY: prefixes, for grep and awk")
+	flags.BoolVar(&cfg.allowEmpty, "allow-empty", false, "exit successfully, without scanning, when no package patterns are given")
+	flags.StringVar(&cfg.colorBy, "color-by", colorByStatus, "color OSV IDs by `status` (called or informational) or by severity, when colors are shown")
+	flags.StringVar(&cfg.symbolFormat, "symbol-format", "", "name symbols in traces in `format`: short (function only), qualified (by package name) or full (by package path)")
+	flags.StringVar(&cfg.sortBy, "sort", sortID, "list called vulnerabilities by `id` or by the number of distinct call stacks reaching them (stacks)")
+	flags.IntVar(&cfg.top, "top", 0, "list only the `n` most severe called vulnerabilities in detail, and count the others (default all)")
+	flags.IntVar(&cfg.maxFindings, "max-findings", 0, "fail only if more than `n` vulnerabilities are called (default 0)")
+	flags.BoolVar(&cfg.splitFixable, "split-fixable", false, "list called vulnerabilities in text output in two sections, those with a fix and those without")
+	flags.BoolVar(&cfg.noTraces, "no-traces", false, "leave the example traces of called vulnerabilities out of text output")
+	flags.Var(&errorModsFlag, "error-modules", "fail on every vulnerability of the modules in the comma-separated `list`, even if it is not called; may be repeated")
+	flags.StringVar(&cfg.marker, "trace-marker", markerNumbered, "start example traces with `marker`: their number (numbered), a dash (dashes) or nothing (none)")
+	flags.StringVar(&cfg.missing, "missing-files", missingKeep, "handle trace positions in files that no longer exist by `policy`: keep, omit or flag them")
+	flags.StringVar(&cfg.lang, "lang", defaultLang, "print the labels and headings of text output in `language`")
+	flags.StringVar(&cfg.group, "group", groupVuln, "group text output by `vuln`, by module or by severity; only informational findings are grouped by module, and only called ones by severity")
+	flags.IntVar(&cfg.width, "width", 0, "wrap text output to `n` characters (default $COLUMNS, the terminal width, or 80)")
+	flags.IntVar(&cfg.compactWidth, "compact-width", 0, "truncate compact traces in text output to `n` characters, from the middle (default no limit)")
+	flags.StringVar(&cfg.indent, "indent", "", "indent text output by `unit` per level: a number of spaces, \"tab\", or a literal prefix (default 2 spaces)")
+	flags.BoolVar(&cfg.redact, "redact", false, "replace the home directory and the -redact-prefix paths in the output with placeholders")
+	flags.Var(&redactFlag, "redact-prefix", "comma-separated `list` of path and module prefixes to redact, implies -redact")
 	flags.BoolVar(&cfg.test, "test", false, "analyze test files (only valid for source mode)")
+	flags.BoolVar(&cfg.testOnly, "test-only", false, "analyze test files only, and calls from them (only valid for source mode)")
 	flags.StringVar(&cfg.dir, "C", "", "change to `dir` before running govulncheck")
-	flags.StringVar(&cfg.db, "db", "https://vuln.go.dev", "vulnerability database `url`")
-	flags.StringVar(&cfg.mode, "mode", modeSource, "supports source or binary")
+	flags.StringVar(&cfg.db, "db", "https://vuln.go.dev", "vulnerability database `url`, or a directory holding a copy of the database")
+	flags.IntVar(&cfg.retries, "db-retries", 2, "retry failed vulnerability database requests up to `n` times")
+	flags.Float64Var(&cfg.dbRate, "db-rate", 0, "make at most `n` vulnerability database requests per second (default unlimited)")
+	flags.Func("db-header", "add the `Key:Value` header to each vulnerability database request; may be repeated", func(s string) error {
+		cfg.dbHeaders = append(cfg.dbHeaders, s)
+		return nil
+	})
+	flags.IntVar(&cfg.dbSchema, "db-schema", 0, "fail unless the vulnerability database follows schema version `n` (default is to warn about unsupported versions)")
+	flags.DurationVar(&cfg.maxDBAge, "max-db-age", 0, "warn, or fail with -strict, if the vulnerability database was last modified longer than `duration` ago (default no check)")
+	flags.StringVar(&cfg.ignoreFile, "ignore-file", "", "leave out the findings of the vulnerabilities and modules listed in `file`, one \"ignore: ID-or-module\" per line")
+	flags.Var(&excludeFlag, "exclude", "leave out the findings of the vulnerabilities in the comma-separated `list` of OSV IDs or aliases; may be repeated")
+	flags.StringVar(&cfg.severityFile, "severity-override", "", "replace the severity of the vulnerabilities listed in `file`, one \"ID: severity\" per line")
+	flags.BoolVar(&cfg.calledOnly, "called-only", false, "leave informational findings, and the OSV entries only they refer to, out of the output")
+	flags.StringVar(&cfg.blame, "blame", "", "only report the findings whose traces pass through `module`, and count the vulnerabilities kept")
+	flags.BoolVar(&cfg.plan, "plan", false, "print the module upgrades that clear the called vulnerabilities instead of the findings")
+	flags.BoolVar(&cfg.applyFixes, "apply-fixes", false, "make the upgrades of -plan in the go.mod file of the module of the -C directory, run go mod tidy, and print the changes")
+	flags.BoolVar(&cfg.dryRun, "dry-run", false, "with -apply-fixes, print the go.mod changes without making them")
+	flags.StringVar(&cfg.metrics, "metrics", "", "also write counts of the findings to `file` in the Prometheus text format")
+	flags.StringVar(&cfg.pushgateway, "pushgateway", "", "also push counts of the findings to the Prometheus Pushgateway at `url`")
+	flags.StringVar(&cfg.pushJob, "pushgateway-job", "govulncheck", "job `label` of the metrics pushed with -pushgateway")
+	flags.StringVar(&cfg.pushInstance, "pushgateway-instance", "", "instance `label` of the metrics pushed with -pushgateway (default none)")
+	flags.BoolVar(&cfg.syslog, "syslog", false, "also log a line for each called vulnerability to the local syslog daemon")
+	flags.StringVar(&cfg.syslogFac, "syslog-facility", "user", "syslog `facility` of the lines logged with -syslog")
+	flags.StringVar(&cfg.syslogTag, "syslog-tag", "govulncheck", "syslog `tag` of the lines logged with -syslog")
+	flags.IntVar(&cfg.minStacks, "min-stacks", 0, "report called vulnerabilities with fewer than `n` distinct call stacks as informational")
+	flags.BoolVar(&cfg.checkOnly, "check-only", false, "print nothing and only set the exit code; errors are still printed to stderr")
+	flags.BoolVar(&cfg.indexOnly, "db-index-only", false, "only check the modules required by go.mod against the database index, for potential vulnerabilities")
+	flags.BoolVar(&cfg.verify, "verify", false, "in convert mode, check that the JSON input is written back unchanged instead of converting it")
+	flags.StringVar(&cfg.mode, "mode", modeSource, "supports source, binary, trend or merge")
+	flags.IntVar(&cfg.pid, "pid", 0, "in binary mode, scan the executable of the running process with ID `n` instead of a binary file")
+	flags.StringVar(&cfg.platform, "platform", "", "analyze source for the `goos/goarch` platform (default is the host platform)")
+	flags.BoolVar(&cfg.directOnly, "direct-only", false, "report called vulnerabilities of indirect dependencies as informational")
+	flags.BoolVar(&cfg.requirePkgs, "require-packages", false, "fail if the package patterns match no packages to scan")
+	flags.Var(&modfileFlag, "modfile", "comma-separated `list` of go.mod files; scan the package patterns in the module of each")
+	flags.Var(&changedFlag, "changed", "comma-separated `list` of changed files; only scan the packages affected by them")
+	flags.StringVar(&cfg.pkgFile, "pkg-file", "", "read newline-delimited package patterns from `file`, or from standard input if file is -")
 	flags.Var(&tagsFlag, "tags", "comma-separated `list` of build tags")
-	flags.Var(&showFlag, "show", "enable display of additional information specified by the comma separated `list`\nThe only supported value is 'traces'")
+	flags.Var(&showFlag, "show", "enable display of additional information specified by the comma separated `list`\nSupported values are 'traces', 'considered', 'import-stacks', 'symbols', 'raw-osv', 'fix-command', 'depth', 'signatures', 'reachability-summary', 'effort', 'provenance', 'version-delta', 'cwe', 'ranges', 'cgo-notes', 'why' and 'module-severity'")
 	scanLevel := flags.String("scan", "symbol", "set the scanning level desired, one of module, package or symbol")
 	flags.Usage = func() {
 		fmt.Fprint(flags.Output(), `Govulncheck reports known vulnerabilities in dependencies.
@@ -68,86 +220,681 @@ Usage:
 		return err
 	}
 	cfg.patterns = flags.Args()
-	if cfg.mode != modeConvert && len(cfg.patterns) == 0 {
+	cfg.set = map[string]bool{}
+	flags.Visit(func(f *flag.Flag) { cfg.set[f.Name] = true })
+	if cfg.mode != modeConvert && len(cfg.patterns) == 0 && cfg.pkgFile == "" && cfg.pid == 0 && !cfg.indexOnly {
+		if cfg.allowEmpty {
+			fmt.Fprintln(flags.Output(), noPatternsMessage)
+			return errNothingToScan
+		}
 		flags.Usage()
-		return errUsage
+		return usageError(&ConfigError{Kind: ErrInvalidPatterns, Err: errNoPatterns})
 	}
 	cfg.tags = tagsFlag
-	cfg.show = showFlag
+	cfg.show = showFlag.showFlag
+	cfg.redacted = redactFlag
+	cfg.changed = changedFlag
+	cfg.modfiles = modfileFlag
+	cfg.exclude = excludeFlag
+	cfg.errorMods = errorModsFlag
+	if len(cfg.redacted) > 0 {
+		cfg.redact = true
+	}
 	cfg.ScanLevel = govulncheck.ScanLevel(*scanLevel)
+	if cfg.format == "" {
+		cfg.format = formatText
+		if cfg.json {
+			cfg.format = formatJSON
+		}
+	}
 	if err := validateConfig(cfg); err != nil {
 		fmt.Fprintln(flags.Output(), err)
-		return errUsage
+		return usageError(err)
+	}
+	headers, err := parseDBHeaders(cfg.dbHeaders)
+	if err != nil {
+		err = &ConfigError{Kind: ErrInvalidFlagValue, Flag: "db-header", Err: err}
+		fmt.Fprintln(flags.Output(), err)
+		return usageError(err)
+	}
+	cfg.headers = headers
+	if cfg.mode == modeSource && !cfg.indexOnly {
+		if err := readPatterns(cfg, stdin); err != nil {
+			if errors.Is(err, errNoPatterns) && cfg.allowEmpty {
+				fmt.Fprintln(flags.Output(), noPatternsMessage)
+				return errNothingToScan
+			}
+			fmt.Fprintln(flags.Output(), err)
+			return usageError(err)
+		}
 	}
 	return nil
 }
 
+var supportedFormats = map[string]bool{
+	formatText:        true,
+	formatJSON:        true,
+	formatOSV:         true,
+	formatFix:         true,
+	formatSPDX:        true,
+	formatTeamCity:    true,
+	formatNDJSON:      true,
+	formatCodeClimate: true,
+	formatOSVScanner:  true,
+	formatRSS:         true,
+}
+
 var supportedModes = map[string]bool{
 	modeSource:  true,
 	modeBinary:  true,
 	modeConvert: true,
 	modeQuery:   true,
+	modeTrend:   true,
+	modeMerge:   true,
+}
+
+// flagSupport records the modes and output formats that support a flag.
+type flagSupport struct {
+	flag    string
+	modes   []string // nil if all modes support it
+	formats []string // nil if all formats support it
+	// set reports whether cfg sets the flag.
+	set func(cfg *config) bool
+}
+
+// check returns an error if cfg sets the flag in a mode or for a format
+// that does not support it.
+func (f flagSupport) check(cfg *config) error {
+	if !f.set(cfg) {
+		return nil
+	}
+	if f.modes != nil && !contains(f.modes, cfg.mode) {
+		if onlyModeFlags[f.flag] {
+			return configErrorf(ErrUnsupportedInMode, f.flag, "the -%s flag is only supported in %s mode", f.flag, f.modes[0])
+		}
+		return configErrorf(ErrUnsupportedInMode, f.flag, "the -%s flag is not supported in %s mode", f.flag, cfg.mode)
+	}
+	if f.formats != nil && !contains(f.formats, cfg.format) {
+		return configErrorf(ErrUnsupportedForFormat, f.flag, "the -%s flag is not supported for %s output", f.flag, strings.ToUpper(cfg.format))
+	}
+	return nil
+}
+
+var (
+	// findingModes are the modes that report findings. Trend mode only
+	// reports which vulnerabilities were called when.
+	findingModes = []string{modeSource, modeBinary, modeConvert, modeMerge, modeQuery}
+	// scanModes are the modes that analyze code.
+	scanModes = []string{modeSource, modeBinary}
+
+	sourceMode = []string{modeSource}
+	textFormat = []string{formatText}
+)
+
+// onlyModeFlags are the flags of a single mode whose errors name that
+// mode, rather than the mode they were used in.
+var onlyModeFlags = map[string]bool{
+	"pid":           true,
+	"verify":        true,
+	"test-only":     true,
+	"db-index-only": true,
+	"apply-fixes":   true,
+}
+
+// flagSupports are the flags that only some modes or output formats
+// support. Where a new flag is supported is an entry of this table, and
+// what it cannot be combined with are entries of flagConflicts or
+// exclusiveModes.
+var flagSupports = []flagSupport{
+	{"pkg-file", sourceMode, nil, func(cfg *config) bool { return cfg.pkgFile != "" }},
+	{"changed", sourceMode, nil, func(cfg *config) bool { return len(cfg.changed) > 0 }},
+	{"modfile", sourceMode, nil, func(cfg *config) bool { return len(cfg.modfiles) > 0 }},
+	{"direct-only", sourceMode, nil, func(cfg *config) bool { return cfg.directOnly }},
+	{"require-packages", sourceMode, nil, func(cfg *config) bool { return cfg.requirePkgs }},
+	{"platform", sourceMode, nil, func(cfg *config) bool { return cfg.platform != "" }},
+	{"test", sourceMode, nil, func(cfg *config) bool { return cfg.test }},
+	{"test-only", sourceMode, nil, func(cfg *config) bool { return cfg.testOnly }},
+	{"db-index-only", sourceMode, textFormat, func(cfg *config) bool { return cfg.indexOnly }},
+	{"apply-fixes", sourceMode, textFormat, func(cfg *config) bool { return cfg.applyFixes }},
+	{"pid", []string{modeBinary}, nil, func(cfg *config) bool { return cfg.pid != 0 }},
+	{"verify", []string{modeConvert}, nil, func(cfg *config) bool { return cfg.verify }},
+	{"tags", scanModes, nil, func(cfg *config) bool { return len(cfg.tags) > 0 }},
+	{"plan", scanModes, textFormat, func(cfg *config) bool { return cfg.plan }},
+	{"C", []string{modeSource, modeBinary, modeQuery}, nil, func(cfg *config) bool { return cfg.dir != "" }},
+	{"strict", []string{modeSource, modeBinary, modeQuery}, nil, func(cfg *config) bool { return cfg.strict }},
+	{"max-db-age", []string{modeSource, modeBinary, modeQuery}, nil, func(cfg *config) bool { return cfg.maxDBAge > 0 }},
+	{"metrics", []string{modeSource, modeBinary, modeMerge}, nil, func(cfg *config) bool { return cfg.metrics != "" }},
+	{"pushgateway", []string{modeSource, modeBinary, modeMerge}, nil, func(cfg *config) bool { return cfg.pushgateway != "" }},
+	{"syslog", []string{modeSource, modeBinary, modeMerge}, nil, func(cfg *config) bool { return cfg.syslog }},
+	{"check-only", []string{modeSource, modeBinary, modeMerge}, textFormat, func(cfg *config) bool { return cfg.checkOnly }},
+
+	// The flags that select and present findings.
+	{"show", findingModes, nil, func(cfg *config) bool { return len(cfg.show) > 0 }},
+	{"called-only", findingModes, nil, func(cfg *config) bool { return cfg.calledOnly }},
+	{"ignore-file", findingModes, nil, func(cfg *config) bool { return cfg.ignoreFile != "" }},
+	{"exclude", findingModes, nil, func(cfg *config) bool { return len(cfg.exclude) > 0 }},
+	{"severity-override", findingModes, nil, func(cfg *config) bool { return cfg.severityFile != "" }},
+	{"blame", findingModes, nil, func(cfg *config) bool { return cfg.blame != "" }},
+	{"min-stacks", findingModes, nil, func(cfg *config) bool { return cfg.minStacks != 0 }},
+	{"redact", findingModes, nil, func(cfg *config) bool { return cfg.redact }},
+	{"missing-files", findingModes, []string{formatText, formatCodeClimate}, func(cfg *config) bool { return cfg.missing != missingKeep }},
+	{"group", findingModes, textFormat, func(cfg *config) bool { return cfg.group != groupVuln }},
+	{"sort", findingModes, textFormat, func(cfg *config) bool { return cfg.sortBy != sortID }},
+	{"top", findingModes, textFormat, func(cfg *config) bool { return cfg.top != 0 }},
+	{"max-findings", findingModes, textFormat, func(cfg *config) bool { return cfg.maxFindings != 0 }},
+	{"error-modules", findingModes, textFormat, func(cfg *config) bool { return len(cfg.errorMods) > 0 }},
+	{"split-fixable", findingModes, textFormat, func(cfg *config) bool { return cfg.splitFixable }},
+	{"no-traces", findingModes, textFormat, func(cfg *config) bool { return cfg.noTraces }},
+	{"trace-marker", findingModes, textFormat, func(cfg *config) bool { return cfg.marker != markerNumbered }},
+	{"symbol-format", findingModes, textFormat, func(cfg *config) bool { return cfg.symbolFormat != "" }},
+	{"machine-text", findingModes, textFormat, func(cfg *config) bool { return cfg.machineText }},
+	{"color-by", findingModes, textFormat, func(cfg *config) bool { return cfg.colorBy != colorByStatus }},
+	{"lang", findingModes, textFormat, func(cfg *config) bool { return cfg.lang != defaultLang }},
+	{"width", findingModes, textFormat, func(cfg *config) bool { return cfg.width != 0 }},
+	{"compact-width", findingModes, textFormat, func(cfg *config) bool { return cfg.compactWidth != 0 }},
+	{"indent", findingModes, textFormat, func(cfg *config) bool { return cfg.indent != "" }},
+	{"strip-ansi", findingModes, textFormat, func(cfg *config) bool { return cfg.stripANSI }},
+	{"no-footer-on-clean", findingModes, textFormat, func(cfg *config) bool { return cfg.noFooter }},
+}
+
+// modeFormats are the output formats of the modes that do not support
+// all of them.
+var modeFormats = map[string][]string{
+	modeConvert: {formatText},
+	modeTrend:   {formatText, formatJSON},
+	modeMerge:   {formatText, formatJSON},
+	modeQuery:   {formatJSON},
+}
+
+// modeFormatError returns the error for an output format that mode does
+// not support, one of formats.
+func modeFormatError(mode string, formats []string) error {
+	switch {
+	case len(formats) > 1:
+		return configErrorf(ErrUnsupportedInMode, "format", "the -format flag must be %s in %s mode", strings.Join(formats, " or "), mode)
+	case formats[0] == formatJSON:
+		return configErrorf(ErrUnsupportedInMode, "json", "the -json flag must be set in %s mode", mode)
+	default:
+		return configErrorf(ErrUnsupportedInMode, "format", "the -format flag is not supported in %s mode", mode)
+	}
+}
+
+// A flagConflict is a combination of flags that validateConfig rejects,
+// because one of them would be silently ignored or would contradict the
+// other.
+type flagConflict struct {
+	flag   string // the rejected flag, without its dash
+	with   string // what it cannot be used with, as on the command line
+	reason string // why, if the combination looks sensible otherwise
+	// conflicts reports whether cfg has the combination.
+	conflicts func(cfg *config) bool
+}
+
+func (c flagConflict) error() error {
+	msg := "the -" + c.flag + " flag cannot be used with " + c.with
+	if c.reason != "" {
+		msg += ", " + c.reason
+	}
+	return configErrorf(ErrIncompatibleFlags, c.flag, "%s", msg)
+}
+
+// flagConflicts are the combinations of flags that validateConfig
+// rejects, once each flag is known to be valid for the mode and format.
+var flagConflicts = []flagConflict{
+	{flag: "split-fixable", with: "-group=severity", conflicts: func(cfg *config) bool {
+		return cfg.splitFixable && cfg.group == groupSeverity
+	}},
+	{flag: "no-traces", with: "-show=traces", conflicts: func(cfg *config) bool {
+		return cfg.noTraces && cfg.showing("traces")
+	}},
+	{flag: "show", with: "-scan=package or -scan=module", reason: "as cgo-notes needs the call graph of -scan=symbol", conflicts: func(cfg *config) bool {
+		return cfg.showing(showCgoNotes) && !cfg.ScanLevel.WantSymbols()
+	}},
+	{flag: "show", with: "-mode=binary", reason: "as cgo-notes needs the call graph of source mode", conflicts: func(cfg *config) bool {
+		return cfg.showing(showCgoNotes) && cfg.mode == modeBinary
+	}},
+	{flag: "show", with: "-mode=query", reason: "as provenance is set on findings, which query mode does not report", conflicts: func(cfg *config) bool {
+		return cfg.showing(showProvenance) && cfg.mode == modeQuery
+	}},
+	{flag: "error-modules", with: "-called-only", reason: "which leaves out the informational findings it fails on", conflicts: func(cfg *config) bool {
+		return len(cfg.errorMods) > 0 && cfg.calledOnly
+	}},
+	{flag: "missing-files", with: "-redact", reason: "which rewrites the paths of the files it checks", conflicts: func(cfg *config) bool {
+		return cfg.missing != missingKeep && cfg.redact
+	}},
+	{flag: "test-only", with: "-test", reason: "which also analyzes production code", conflicts: func(cfg *config) bool {
+		return cfg.testOnly && cfg.test
+	}},
+	{flag: "top", with: "-sort=stacks", reason: "as -top lists the most severe vulnerabilities first", conflicts: func(cfg *config) bool {
+		return cfg.top != 0 && cfg.sortBy == sortStacks
+	}},
+	{flag: "strict", with: "-db-index-only", reason: "which fetches no OSV entries to check", conflicts: func(cfg *config) bool {
+		return cfg.strict && cfg.indexOnly && cfg.maxDBAge == 0
+	}},
+
+	// -apply-fixes carries out the plan, in a single go.mod file.
+	{flag: "apply-fixes", with: "-plan", conflicts: func(cfg *config) bool {
+		return cfg.applyFixes && cfg.plan
+	}},
+	{flag: "apply-fixes", with: "-check-only", reason: "which prints nothing", conflicts: func(cfg *config) bool {
+		return cfg.applyFixes && cfg.checkOnly
+	}},
+	{flag: "apply-fixes", with: "-modfile", conflicts: func(cfg *config) bool {
+		return cfg.applyFixes && len(cfg.modfiles) > 0
+	}},
+}
+
+// An exclusiveMode is a flag that changes what govulncheck reports so
+// much that the flags of rejects no longer apply.
+type exclusiveMode struct {
+	flag    string   // without its dash
+	rejects []string // the flags it cannot be used with, without their dash
+}
+
+// exclusiveModes are checked against the flags given on the command line,
+// after flagConflicts.
+var exclusiveModes = []exclusiveMode{
+	// -db-index-only lists the IDs of the index for each required
+	// module, without any findings or OSV entries to select, check or
+	// report.
+	{"db-index-only", []string{
+		"test-only", "ignore-file", "exclude", "severity-override", "blame", "error-modules", "max-findings",
+		"show", "machine-text", "plan", "apply-fixes", "metrics", "pushgateway", "syslog",
+	}},
+	// -plan replaces the findings with upgrades, so none of the flags
+	// that shape the findings apply.
+	{"plan", []string{
+		"check-only", "show", "group", "sort", "top", "split-fixable", "no-traces", "machine-text",
+	}},
+	// -apply-fixes prints the changes it made instead of the findings.
+	{"apply-fixes", []string{
+		"machine-text", "show", "group", "sort", "top", "split-fixable", "no-traces", "max-findings", "error-modules",
+	}},
+	// -machine-text has a fixed layout, in English, and fails whenever
+	// a vulnerability is called.
+	{"machine-text", []string{
+		"show", "group", "sort", "top", "split-fixable", "no-traces", "trace-marker", "symbol-format", "missing-files",
+		"color-by", "lang", "width", "compact-width", "indent", "strip-ansi", "no-footer-on-clean", "max-findings", "error-modules",
+	}},
 }
 
 func validateConfig(cfg *config) error {
 	if _, ok := supportedModes[cfg.mode]; !ok {
-		return fmt.Errorf("%q is not a valid mode", cfg.mode)
+		return configErrorf(ErrUnsupportedMode, "mode", "%q is not a valid mode", cfg.mode)
+	}
+	if _, ok := supportedFormats[cfg.format]; !ok {
+		return configErrorf(ErrUnsupportedFormat, "format", "%q is not a valid format", cfg.format)
+	}
+	if cfg.ignoreFile != "" {
+		ignored, err := readIgnoreFile(cfg.ignoreFile)
+		if err != nil {
+			return configErrorf(ErrUnreadableFile, "ignore-file", "reading the -ignore-file: %w", err)
+		}
+		cfg.ignored = ignored
+	}
+	if cfg.severityFile != "" {
+		overrides, err := readSeverityOverrides(cfg.severityFile)
+		if err != nil {
+			return configErrorf(ErrUnreadableFile, "severity-override", "reading the -severity-override file: %w", err)
+		}
+		cfg.overrides = overrides
+	}
+	if len(cfg.exclude) > 0 {
+		cfg.excluded = &suppressions{}
+		for _, id := range cfg.exclude {
+			if !isVulnID(id) {
+				return configErrorf(ErrInvalidFlagValue, "exclude", "the -exclude flag takes OSV IDs or aliases, such as GO-2021-0113, and %q is not one", id)
+			}
+			cfg.excluded.add(id)
+		}
+	}
+	if cfg.blame != "" {
+		if err := module.CheckImportPath(cfg.blame); err != nil {
+			return configErrorf(ErrInvalidFlagValue, "blame", "the -blame flag takes a module path, and %q is not one", cfg.blame)
+		}
+	}
+	if cfg.pushgateway != "" {
+		if u, err := url.Parse(cfg.pushgateway); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return configErrorf(ErrInvalidFlagValue, "pushgateway", "the -pushgateway flag must be an http or https URL, and %q is not", cfg.pushgateway)
+		}
+		if cfg.pushJob == "" {
+			return configErrorf(ErrInvalidFlagValue, "pushgateway-job", "the -pushgateway-job flag must not be empty")
+		}
+	}
+	if cfg.syslog {
+		if _, ok := syslogFacilities[cfg.syslogFac]; !ok {
+			return configErrorf(ErrInvalidFlagValue, "syslog-facility", "the -syslog-facility flag must be one of %s, and %q is not", facilityNames(), cfg.syslogFac)
+		}
+	}
+	if cfg.dir != "" {
+		if fi, err := os.Stat(cfg.dir); err != nil || !fi.IsDir() {
+			return configErrorf(ErrInvalidFlagValue, "C", "the -C flag must name a directory, and %q is not a directory", cfg.dir)
+		}
+	}
+	if cfg.json && cfg.format != formatJSON {
+		return configErrorf(ErrIncompatibleFlags, "json", "the -json flag cannot be used with -format=%s", cfg.format)
+	}
+	if n, err := strconv.Atoi(cfg.indent); err == nil && n < 0 {
+		return configErrorf(ErrInvalidFlagValue, "indent", "the -indent flag must not be a negative number")
+	}
+	if cfg.colorBy != colorByStatus && cfg.colorBy != colorBySeverity {
+		return configErrorf(ErrInvalidFlagValue, "color-by", "%q is not a valid -color-by value, must be status or severity", cfg.colorBy)
+	}
+	if cfg.group != groupVuln && cfg.group != groupModule && cfg.group != groupSeverity {
+		return configErrorf(ErrInvalidFlagValue, "group", "%q is not a valid -group value, must be vuln, module or severity", cfg.group)
+	}
+	if cfg.sortBy != sortID && cfg.sortBy != sortStacks {
+		return configErrorf(ErrInvalidFlagValue, "sort", "%q is not a valid -sort value, must be id or stacks", cfg.sortBy)
+	}
+	switch cfg.symbolFormat {
+	case "", symbolShort, symbolQualified, symbolFull:
+	default:
+		return configErrorf(ErrInvalidFlagValue, "symbol-format", "%q is not a valid -symbol-format value, must be short, qualified or full", cfg.symbolFormat)
+	}
+	if cfg.top < 0 {
+		return configErrorf(ErrInvalidFlagValue, "top", "the -top flag must not be negative")
+	}
+	if cfg.maxFindings < 0 {
+		return configErrorf(ErrInvalidFlagValue, "max-findings", "the -max-findings flag must not be negative")
+	}
+	if _, ok := catalogs[cfg.lang]; !ok {
+		return configErrorf(ErrInvalidFlagValue, "lang", "%q is not a supported -lang value, must be one of: %s", cfg.lang, languages())
+	}
+	switch cfg.marker {
+	case markerNumbered, markerDashes, markerNone:
+	default:
+		return configErrorf(ErrInvalidFlagValue, "trace-marker", "%q is not a valid -trace-marker value, must be numbered, dashes or none", cfg.marker)
+	}
+	switch cfg.missing {
+	case missingKeep, missingOmit, missingFlag:
+	default:
+		return configErrorf(ErrInvalidFlagValue, "missing-files", "%q is not a valid -missing-files value, must be keep, omit or flag", cfg.missing)
+	}
+	if cfg.dryRun && !cfg.applyFixes {
+		return configErrorf(ErrIncompatibleFlags, "dry-run", "the -dry-run flag is only supported with -apply-fixes")
+	}
+	if cfg.width < 0 {
+		return configErrorf(ErrInvalidFlagValue, "width", "the -width flag must not be negative")
+	}
+	if cfg.compactWidth < 0 {
+		return configErrorf(ErrInvalidFlagValue, "compact-width", "the -compact-width flag must not be negative")
+	}
+	if err := resolveLocalDB(cfg); err != nil {
+		return &ConfigError{Kind: ErrInvalidFlagValue, Flag: "db", Err: err}
+	}
+	if cfg.retries < 0 {
+		return configErrorf(ErrInvalidFlagValue, "db-retries", "the -db-retries flag must not be negative")
+	}
+	if cfg.dbRate < 0 {
+		return configErrorf(ErrInvalidFlagValue, "db-rate", "the -db-rate flag must not be negative")
+	}
+	if cfg.minStacks < 0 {
+		return configErrorf(ErrInvalidFlagValue, "min-stacks", "the -min-stacks flag must not be negative")
+	}
+	if cfg.dbSchema < 0 {
+		return configErrorf(ErrInvalidFlagValue, "db-schema", "the -db-schema flag must not be negative")
+	}
+	if cfg.maxDBAge < 0 {
+		return configErrorf(ErrInvalidFlagValue, "max-db-age", "the -max-db-age flag must not be negative")
+	}
+	if cfg.pid < 0 {
+		return configErrorf(ErrInvalidFlagValue, "pid", "the -pid flag must not be negative")
+	}
+	if formats := modeFormats[cfg.mode]; formats != nil && !contains(formats, cfg.format) {
+		return modeFormatError(cfg.mode, formats)
+	}
+	if cfg.test && cfg.mode == modeBinary {
+		// Binaries have no test code, which is worth its own error kind.
+		return configErrorf(ErrBinaryModeTest, "test", "the -test flag is not supported in binary mode")
+	}
+	for _, f := range flagSupports {
+		if err := f.check(cfg); err != nil {
+			return err
+		}
+	}
+	for _, c := range flagConflicts {
+		if c.conflicts(cfg) {
+			return c.error()
+		}
+	}
+	for _, e := range exclusiveModes {
+		if !cfg.set[e.flag] {
+			continue
+		}
+		for _, f := range e.rejects {
+			if cfg.set[f] {
+				return flagConflict{flag: f, with: "-" + e.flag}.error()
+			}
+		}
 	}
 	switch cfg.mode {
 	case modeSource:
-		if len(cfg.patterns) == 1 && isFile(cfg.patterns[0]) {
-			return fmt.Errorf("%q is a file.\n\n%v", cfg.patterns[0], errNoBinaryFlag)
+		// The "-" pattern stands for patterns read from standard input.
+		if len(cfg.patterns) == 1 && cfg.patterns[0] != stdinPatterns && isFile(cfg.patterns[0]) {
+			return configErrorf(ErrInvalidPatterns, "", "%q is a file.\n\n%v", cfg.patterns[0], errNoBinaryFlag)
 		}
-	case modeBinary:
-		if cfg.test {
-			return fmt.Errorf("the -test flag is not supported in binary mode")
+		for _, p := range cfg.patterns {
+			if !strings.Contains(p, "@") {
+				continue
+			}
+			if !isRemoteModule(cfg.patterns) || cfg.pkgFile != "" {
+				return configErrorf(ErrInvalidPatterns, "", "a module@version pattern must be the only pattern")
+			}
+			if cfg.dir != "" {
+				return configErrorf(ErrIncompatibleFlags, "C", "the -C flag is not supported with a module@version pattern")
+			}
+			if len(cfg.changed) > 0 {
+				return configErrorf(ErrIncompatibleFlags, "changed", "the -changed flag is not supported with a module@version pattern")
+			}
+			if len(cfg.modfiles) > 0 {
+				return configErrorf(ErrIncompatibleFlags, "modfile", "the -modfile flag is not supported with a module@version pattern")
+			}
+			if cfg.applyFixes {
+				return configErrorf(ErrIncompatibleFlags, "apply-fixes", "the -apply-fixes flag is not supported with a module@version pattern")
+			}
+			if _, _, err := parseModuleQuery(p); err != nil {
+				return &ConfigError{Kind: ErrInvalidPatterns, Err: err}
+			}
+		}
+		if cfg.pkgFile != "" && cfg.pkgFile != stdinPatterns && !isFile(cfg.pkgFile) {
+			return configErrorf(ErrInvalidFlagValue, "pkg-file", "%q is not a file", cfg.pkgFile)
+		}
+		for _, m := range cfg.modfiles {
+			if filepath.Base(m) != "go.mod" || !isFile(filepath.Join(cfg.dir, m)) {
+				return configErrorf(ErrInvalidFlagValue, "modfile", "the -modfile flag must list go.mod files, and %q is not one", m)
+			}
 		}
-		if len(cfg.tags) > 0 {
-			return fmt.Errorf("the -tags flag is not supported in binary mode")
+		if cfg.platform != "" {
+			goos, goarch, ok := strings.Cut(cfg.platform, "/")
+			if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+				return configErrorf(ErrInvalidFlagValue, "platform", "%q is not a valid platform, must be of the form goos/goarch", cfg.platform)
+			}
+		}
+	case modeBinary:
+		if cfg.pid != 0 {
+			if len(cfg.patterns) > 0 {
+				return configErrorf(ErrIncompatibleFlags, "pid", "the -pid flag cannot be used with a binary to scan")
+			}
+			exe, err := processExecutable(cfg.pid)
+			if err != nil {
+				return &ConfigError{Kind: ErrInvalidFlagValue, Flag: "pid", Err: err}
+			}
+			cfg.patterns = []string{exe}
 		}
 		if len(cfg.patterns) != 1 {
-			return fmt.Errorf("only 1 binary can be analyzed at a time")
+			return configErrorf(ErrInvalidPatterns, "", "only 1 binary can be analyzed at a time")
 		}
 		if !isFile(cfg.patterns[0]) {
-			return fmt.Errorf("%q is not a file", cfg.patterns[0])
+			return configErrorf(ErrInvalidPatterns, "", "%q is not a file", cfg.patterns[0])
 		}
+		cfg.archive = archiveKind(cfg.patterns[0])
+		cfg.plugin = isPlugin(cfg.patterns[0])
 	case modeConvert:
-		if len(cfg.patterns) != 0 {
-			return fmt.Errorf("patterns are not accepted in convert mode")
+		if len(cfg.patterns) > 1 {
+			return configErrorf(ErrInvalidPatterns, "", "only 1 file can be converted at a time")
 		}
-		if cfg.dir != "" {
-			return fmt.Errorf("the -C flag is not supported in convert mode")
+		if len(cfg.patterns) == 1 && !isFile(cfg.patterns[0]) {
+			return configErrorf(ErrInvalidPatterns, "", "%q is not a file", cfg.patterns[0])
 		}
-		if cfg.test {
-			return fmt.Errorf("the -test flag is not supported in convert mode")
-		}
-		if len(cfg.tags) > 0 {
-			return fmt.Errorf("the -tags flag is not supported in convert mode")
+	case modeTrend, modeMerge:
+		for _, p := range cfg.patterns {
+			if !isFile(p) {
+				return configErrorf(ErrInvalidPatterns, "", "%q is not a file", p)
+			}
 		}
 	case modeQuery:
-		if cfg.test {
-			return fmt.Errorf("the -test flag is not supported in query mode")
-		}
-		if len(cfg.tags) > 0 {
-			return fmt.Errorf("the -tags flag is not supported in query mode")
-		}
-		if !cfg.json {
-			return fmt.Errorf("the -json flag must be set in query mode")
-		}
 		for _, pattern := range cfg.patterns {
 			// Parse the input here so that we can catch errors before
 			// outputting the Config.
 			if _, _, err := parseModuleQuery(pattern); err != nil {
-				return err
+				return &ConfigError{Kind: ErrInvalidPatterns, Err: err}
+			}
+		}
+	}
+	if cfg.format == formatJSON && onlyShowing(cfg.show, jsonShow...) {
+		return nil
+	}
+	if cfg.format != formatText && len(cfg.show) > 0 {
+		return configErrorf(ErrUnsupportedForFormat, "show", "the -show flag is not supported for %s output", strings.ToUpper(cfg.format))
+	}
+	return nil
+}
+
+// resolveLocalDB replaces a -db value that is a directory path rather
+// than a URL with the file URL of the directory, so that it is read by the
+// local client. A relative path is resolved against the -C directory.
+func resolveLocalDB(cfg *config) error {
+	if strings.Contains(cfg.db, "://") {
+		return nil
+	}
+	dir := cfg.db
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(cfg.dir, dir)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return fmt.Errorf("the -db flag must be a URL or a directory, and %q is not a directory", cfg.db)
+	}
+	u, err := web.URLFromFilePath(dir)
+	if err != nil {
+		return err
+	}
+	cfg.db = u.String()
+	return nil
+}
+
+// indentUnit returns the unit of indentation described by the -indent
+// value s: a number of spaces, "tab" for a tab, or the literal string.
+func indentUnit(s string) string {
+	if n, err := strconv.Atoi(s); err == nil {
+		return strings.Repeat(" ", n)
+	}
+	if s == "tab" {
+		return "\t"
+	}
+	return s
+}
+
+// stdinPatterns is the pattern, or -pkg-file value, that
+// requests reading package patterns from standard input.
+const stdinPatterns = "-"
+
+// readPatterns expands the package patterns of cfg. A "-" pattern is
+// replaced by the patterns read from stdin and the patterns listed in
+// cfg.pkgFile, if any, are appended. Patterns are newline-delimited and
+// blank lines are ignored.
+func readPatterns(cfg *config, stdin io.Reader) error {
+	var patterns []string
+	readStdin := false
+	read := func(r io.Reader) error {
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			if p := strings.TrimSpace(s.Text()); p != "" {
+				patterns = append(patterns, p)
 			}
 		}
+		return s.Err()
+	}
+	fromStdin := func() error {
+		if readStdin {
+			return nil
+		}
+		readStdin = true
+		if err := read(stdin); err != nil {
+			return configErrorf(ErrUnreadableFile, "", "reading patterns from standard input: %w", err)
+		}
+		return nil
 	}
-	if cfg.json && len(cfg.show) > 0 {
-		return fmt.Errorf("the -show flag is not supported for JSON output")
+	for _, p := range cfg.patterns {
+		if p != stdinPatterns {
+			patterns = append(patterns, p)
+			continue
+		}
+		if err := fromStdin(); err != nil {
+			return err
+		}
 	}
+	switch cfg.pkgFile {
+	case "":
+	case stdinPatterns:
+		if err := fromStdin(); err != nil {
+			return err
+		}
+	default:
+		f, err := os.Open(cfg.pkgFile)
+		if err != nil {
+			return &ConfigError{Kind: ErrUnreadableFile, Flag: "pkg-file", Err: err}
+		}
+		defer f.Close()
+		if err := read(f); err != nil {
+			return configErrorf(ErrUnreadableFile, "pkg-file", "reading patterns from %s: %w", cfg.pkgFile, err)
+		}
+	}
+	if len(patterns) == 0 {
+		return &ConfigError{Kind: ErrInvalidPatterns, Err: errNoPatterns}
+	}
+	cfg.patterns = patterns
 	return nil
 }
 
+// overlappingPatterns returns a warning for each package pattern that is
+// given more than once, or that is matched by a wildcard pattern given with
+// it, so that it adds nothing to the scan.
+func overlappingPatterns(patterns []string) []string {
+	var warnings []string
+	seen := map[string]bool{}
+	for _, p := range patterns {
+		if seen[p] {
+			warnings = append(warnings, fmt.Sprintf("pattern %q is given more than once", p))
+			continue
+		}
+		seen[p] = true
+		for _, q := range patterns {
+			if q != p && strings.Contains(q, "...") && matchPattern(q, p) {
+				warnings = append(warnings, fmt.Sprintf("pattern %q is already matched by %q", p, q))
+				break
+			}
+		}
+	}
+	return warnings
+}
+
+// matchPattern reports whether the wildcard package pattern matches s,
+// following the rules of the go command: "..." matches any string, and
+// a trailing "/..." also matches the empty string.
+func matchPattern(pattern, s string) bool {
+	re := regexp.QuoteMeta(pattern)
+	re = strings.ReplaceAll(re, `\.\.\.`, `.*`)
+	if strings.HasSuffix(re, `/.*`) {
+		re = strings.TrimSuffix(re, `/.*`) + `(/.*)?`
+	}
+	matched, _ := regexp.MatchString("^"+re+"$", s)
+	return matched
+}
+
 func isFile(path string) bool {
 	s, err := os.Stat(path)
 	if err != nil {
@@ -170,6 +917,58 @@ func fileExists(path string) bool {
 	return true
 }
 
+// jsonShow are the -show options supported for JSON output.
+var jsonShow = []string{
+	// JSON output always has the full OSV entries, with their affected
+	// ranges, so these two are allowed, and do nothing.
+	showRawOSV,
+	showRanges,
+	// Adds the depth of traces to findings.
+	showDepth,
+	// Adds the signatures of vulnerable symbols to findings.
+	showSignatures,
+	// Adds the reachability counts to the summary.
+	showReachability,
+	// Adds the provenance of their OSV entries to findings.
+	showProvenance,
+	// Adds the CWE IDs to OSV entries.
+	showCWE,
+	// Adds the boundaries of the analysis to findings.
+	showCgoNotes,
+	// Adds the import paths to findings.
+	showWhy,
+}
+
+// onlyShowing reports whether show only has options of allowed.
+func onlyShowing(show []string, allowed ...string) bool {
+	for _, s := range show {
+		if !contains(allowed, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// contains reports whether list has s.
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// showing reports whether option was requested with -show.
+func (c *config) showing(option string) bool {
+	for _, s := range c.show {
+		if s == option {
+			return true
+		}
+	}
+	return false
+}
+
 type showFlag []string
 
 func (v *showFlag) Set(s string) error {
@@ -179,3 +978,22 @@ func (v *showFlag) Set(s string) error {
 
 func (f *showFlag) Get() interface{} { return *f }
 func (f *showFlag) String() string   { return "<options>" }
+
+// showOptions are the options of -show.
+var showOptions = []string{
+	"traces", "color", showConsidered, showImportStacks, showSymbols, showRawOSV, showFixCommand,
+	showDepth, showSignatures, showReachability, showEffort, showProvenance, showVersionDelta,
+	showCWE, showRanges, showCgoNotes, showWhy, showModSeverity,
+}
+
+// showOptionsFlag is the -show flag, a showFlag of showOptions.
+type showOptionsFlag struct{ showFlag }
+
+func (v *showOptionsFlag) Set(s string) error {
+	for _, opt := range strings.Split(s, ",") {
+		if !contains(showOptions, opt) {
+			return fmt.Errorf("unknown option %q", opt)
+		}
+	}
+	return v.showFlag.Set(s)
+}
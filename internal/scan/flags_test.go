@@ -0,0 +1,143 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOverlappingPatterns(t *testing.T) {
+	for _, tc := range []struct {
+		patterns []string
+		want     []string
+	}{
+		{[]string{"./..."}, nil},
+		{[]string{"./a", "./b/..."}, nil},
+		{[]string{"./...", "./foo"}, []string{`pattern "./foo" is already matched by "./..."`}},
+		{[]string{"./foo/...", "./foo"}, []string{`pattern "./foo" is already matched by "./foo/..."`}},
+		{[]string{".", "./..."}, []string{`pattern "." is already matched by "./..."`}},
+		{[]string{"./a/...", "./..."}, []string{`pattern "./a/..." is already matched by "./..."`}},
+		{[]string{"./foo", "./foobar/..."}, nil},
+		{[]string{"net/.../http", "net/x/http"}, []string{`pattern "net/x/http" is already matched by "net/.../http"`}},
+		{[]string{"./a", "./a"}, []string{`pattern "./a" is given more than once`}},
+	} {
+		if got := overlappingPatterns(tc.patterns); !cmp.Equal(got, tc.want) {
+			t.Errorf("overlappingPatterns(%q) = %q; want %q", tc.patterns, got, tc.want)
+		}
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	for _, test := range []struct {
+		args []string
+		kind error
+		flag string
+		msg  string
+	}{
+		{[]string{"-mode=foo", "."}, ErrUnsupportedMode, "mode", `"foo" is not a valid mode`},
+		{[]string{"-mode=binary", "-test", "prog"}, ErrBinaryModeTest, "test", "the -test flag is not supported in binary mode"},
+		{[]string{"-format=json", "-top=3", "."}, ErrUnsupportedForFormat, "top", "the -top flag is not supported for JSON output"},
+		{[]string{"-top=-1", "."}, ErrInvalidFlagValue, "top", "the -top flag must not be negative"},
+		{[]string{"-test-only", "-test", "."}, ErrIncompatibleFlags, "test-only", "the -test-only flag cannot be used with -test, which also analyzes production code"},
+		{[]string{"-plan", "-group=module", "."}, ErrIncompatibleFlags, "group", "the -group flag cannot be used with -plan"},
+		{[]string{"-machine-text", "-sort=stacks", "."}, ErrIncompatibleFlags, "sort", "the -sort flag cannot be used with -machine-text"},
+		{[]string{"-machine-text", "-lang=en", "."}, ErrIncompatibleFlags, "lang", "the -lang flag cannot be used with -machine-text"},
+		{[]string{"-top=3", "-sort=stacks", "."}, ErrIncompatibleFlags, "top", "the -top flag cannot be used with -sort=stacks, as -top lists the most severe vulnerabilities first"},
+		{[]string{"-mode=convert", "-test"}, ErrUnsupportedInMode, "test", "the -test flag is not supported in convert mode"},
+		{[]string{"-pkg-file=-"}, ErrInvalidPatterns, "", "no package patterns provided"},
+		{[]string{"-mode=binary", "-test-only", "prog"}, ErrUnsupportedInMode, "test-only", "the -test-only flag is only supported in source mode"},
+		{[]string{"-mode=convert", "a.json", "b.json"}, ErrInvalidPatterns, "", "only 1 file can be converted at a time"},
+		{[]string{"-mode=trend", "-group=module", "a.json"}, ErrUnsupportedInMode, "group", "the -group flag is not supported in trend mode"},
+		{[]string{"-mode=binary", "-modfile=go.mod", "prog"}, ErrUnsupportedInMode, "modfile", "the -modfile flag is not supported in binary mode"},
+		{[]string{"-mode=merge", "-format=osv", "a.json"}, ErrUnsupportedInMode, "format", "the -format flag must be text or json in merge mode"},
+	} {
+		t.Run(strings.Join(test.args, " "), func(t *testing.T) {
+			var stderr strings.Builder
+			err := parseFlags(&config{}, strings.NewReader(""), &stderr, test.args)
+			var cerr *ConfigError
+			if !errors.As(err, &cerr) {
+				t.Fatalf("got error %v, want a ConfigError", err)
+			}
+			if !errors.Is(err, test.kind) || cerr.Flag != test.flag {
+				t.Errorf("got kind %v and flag %q, want %v and %q", cerr.Kind, cerr.Flag, test.kind, test.flag)
+			}
+			if err.Error() != errUsage.Error() {
+				t.Errorf("got error %q, want %q", err, errUsage)
+			}
+			if got := strings.TrimSpace(stderr.String()); got != test.msg {
+				t.Errorf("printed %q, want %q", got, test.msg)
+			}
+		})
+	}
+	if !errors.Is(ErrBinaryModeTest, ErrUnsupportedInMode) {
+		t.Error("ErrBinaryModeTest is not an ErrUnsupportedInMode")
+	}
+	// Missing patterns are a config error too.
+	if err := parseFlags(&config{}, strings.NewReader(""), io.Discard, nil); !errors.Is(err, ErrInvalidPatterns) || err.Error() != errUsage.Error() {
+		t.Errorf("got error %v without patterns, want an invalid patterns usage error", err)
+	}
+	// An unknown -show option is rejected by the flag itself.
+	if err := parseFlags(&config{}, strings.NewReader(""), io.Discard, []string{"-show=tracez", "."}); err == nil || !strings.Contains(err.Error(), `unknown option "tracez"`) {
+		t.Errorf("got error %v for -show=tracez, want an unknown option error", err)
+	}
+	// The cause of an unreadable file is kept.
+	err := parseFlags(&config{}, strings.NewReader(""), io.Discard, []string{"-ignore-file=" + filepath.Join(t.TempDir(), "missing"), "."})
+	if !errors.Is(err, ErrUnreadableFile) || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got error %v for a missing -ignore-file, want an unreadable file error caused by fs.ErrNotExist", err)
+	}
+}
+
+func TestFlagSupports(t *testing.T) {
+	for _, f := range flagSupports {
+		if f.set(defaultConfig()) {
+			t.Errorf("-%s is set in the default flags", f.flag)
+		}
+		for _, m := range f.modes {
+			if _, ok := supportedModes[m]; !ok {
+				t.Errorf("-%s is supported in mode %q, which does not exist", f.flag, m)
+			}
+		}
+	}
+}
+
+func TestFlagConflicts(t *testing.T) {
+	// Each entry is named after a flag and rejects it with an error for
+	// that flag.
+	for _, c := range flagConflicts {
+		var cerr *ConfigError
+		if err := c.error(); !errors.As(err, &cerr) || cerr.Flag != c.flag || !errors.Is(err, ErrIncompatibleFlags) {
+			t.Errorf("error of the conflict of -%s with %s = %v, want an incompatible flags error for -%s", c.flag, c.with, err, c.flag)
+		}
+		if c.conflicts(defaultConfig()) {
+			t.Errorf("the conflict of -%s with %s applies to the default flags", c.flag, c.with)
+		}
+	}
+}
+
+func TestExclusiveModes(t *testing.T) {
+	var usage strings.Builder
+	parseFlags(&config{}, strings.NewReader(""), &usage, []string{"-h"})
+	for _, e := range exclusiveModes {
+		for _, f := range append([]string{e.flag}, e.rejects...) {
+			if !regexp.MustCompile(`(?m)^  -` + regexp.QuoteMeta(f) + `(\s|$)`).MatchString(usage.String()) {
+				t.Errorf("-%s is not a flag", f)
+			}
+		}
+	}
+}
+
+// defaultConfig returns the config of the default flags, for the tables
+// of validateConfig.
+func defaultConfig() *config {
+	return &config{group: groupVuln, sortBy: sortID, marker: markerNumbered, missing: missingKeep, colorBy: colorByStatus, lang: defaultLang}
+}
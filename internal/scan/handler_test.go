@@ -0,0 +1,60 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// The tests of the handlers share a scan of two vulnerabilities of
+// golang.org/vmod@v1.0.0: GO-0000-0001, which main.main calls through
+// vmod.Vuln, and GO-0000-0002, which is only imported and fixed in
+// v1.0.1. A test changes or adds to them what it checks.
+
+// testEntries returns the OSV entries of the shared scan.
+func testEntries() []*osv.Entry {
+	var entries []*osv.Entry
+	for _, id := range []string{"GO-0000-0001", "GO-0000-0002"} {
+		entries = append(entries, &osv.Entry{
+			ID:               id,
+			Summary:          "Crash in parser",
+			DatabaseSpecific: &osv.DatabaseSpecific{URL: "https://pkg.go.dev/vuln/" + id},
+		})
+	}
+	return entries
+}
+
+// testFindings returns the findings of the shared scan.
+func testFindings() []*govulncheck.Finding {
+	return []*govulncheck.Finding{
+		{OSV: "GO-0000-0001", Trace: []*govulncheck.Frame{
+			{Module: "golang.org/vmod", Version: "v1.0.0", Package: "golang.org/vmod", Function: "Vuln"},
+			{Module: "golang.org/main", Package: "golang.org/main", Function: "main"},
+		}},
+		{OSV: "GO-0000-0002", FixedVersion: "v1.0.1", Trace: []*govulncheck.Frame{
+			{Module: "golang.org/vmod", Version: "v1.0.0", Package: "golang.org/vmod"},
+		}},
+	}
+}
+
+// runHandler passes entries and findings to h, in that order, and returns
+// the error of flushing it.
+func runHandler(t *testing.T, h govulncheck.Handler, entries []*osv.Entry, findings []*govulncheck.Finding) error {
+	t.Helper()
+	for _, e := range entries {
+		if err := h.OSV(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range findings {
+		if err := h.Finding(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return Flush(h)
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+// hookHandler wraps a handler and calls hooks for every finding
+// the wrapped handler accepts.
+type hookHandler struct {
+	govulncheck.Handler
+	hooks []func(*govulncheck.Finding)
+}
+
+// Finding passes finding to the wrapped handler and then to the hooks.
+func (h *hookHandler) Finding(finding *govulncheck.Finding) error {
+	if err := h.Handler.Finding(finding); err != nil {
+		return err
+	}
+	for _, hook := range h.hooks {
+		hook(finding)
+	}
+	return nil
+}
+
+func (h *hookHandler) Flush() error {
+	return Flush(h.Handler)
+}
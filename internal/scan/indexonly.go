@@ -0,0 +1,90 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/vuln/internal/client"
+)
+
+// runIndexOnly checks the modules required by the go.mod file of the -C
+// directory against the modules index of the vulnerability database, and
+// writes the potential matches to w. Packages are not loaded, versions are
+// not resolved, and OSV entries are not fetched, so the matches are not
+// confirmed. It returns errVulnerabilitiesFound if there are any.
+func runIndexOnly(ctx context.Context, w io.Writer, cfg *config, c *client.Client) error {
+	reqs, err := requiredModules(filepath.Join(cfg.dir, "go.mod"))
+	if err != nil {
+		return err
+	}
+	ids, err := c.IDsByModules(ctx, reqs)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Checked %d %s required by go.mod against the vulnerability database index.\n\n",
+		len(reqs), choose(len(reqs) == 1, "module", "modules"))
+	vulns, modules := 0, 0
+	for i, req := range reqs {
+		if len(ids[i]) == 0 {
+			continue
+		}
+		if modules == 0 {
+			b.WriteString("Potential vulnerabilities (not confirmed):\n")
+		}
+		modules++
+		vulns += len(ids[i])
+		fmt.Fprintf(&b, "  %s@%s: %s\n", req.Path, req.Version, strings.Join(ids[i], ", "))
+	}
+	if modules == 0 {
+		b.WriteString("No potential vulnerabilities found.\n")
+	} else {
+		fmt.Fprintf(&b, "\nFound %d potential %s in %d %s.\nRun govulncheck without -db-index-only to confirm %s.\n",
+			vulns, choose(vulns == 1, "vulnerability", "vulnerabilities"),
+			modules, choose(modules == 1, "module", "modules"), choose(vulns == 1, "it", "them"))
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+	if modules > 0 {
+		return errVulnerabilitiesFound
+	}
+	return nil
+}
+
+// requiredModules returns the modules that the go.mod file requires, at
+// their required versions, after its replacements. Modules replaced by a
+// directory are left out, as the database cannot know about them.
+func requiredModules(file string) ([]*client.ModuleRequest, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	f, err := modfile.Parse(file, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	var reqs []*client.ModuleRequest
+	for _, r := range f.Require {
+		req := &client.ModuleRequest{Path: r.Mod.Path, Version: r.Mod.Version}
+		for _, rep := range f.Replace {
+			if rep.Old.Path == req.Path && (rep.Old.Version == "" || rep.Old.Version == req.Version) {
+				req = &client.ModuleRequest{Path: rep.New.Path, Version: rep.New.Version}
+			}
+		}
+		if req.Version == "" {
+			continue
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
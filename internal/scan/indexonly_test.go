@@ -0,0 +1,46 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/vuln/internal/client"
+)
+
+func TestRequiredModules(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "go.mod")
+	gomod := `module example.com/m
+
+go 1.18
+
+require (
+	golang.org/x/text v0.3.0
+	golang.org/x/net v0.1.0
+	example.com/local v1.0.0
+)
+
+replace golang.org/x/net => golang.org/x/net v0.7.0
+
+replace example.com/local => ../local
+`
+	if err := os.WriteFile(file, []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := requiredModules(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*client.ModuleRequest{
+		{Path: "golang.org/x/text", Version: "v0.3.0"},
+		{Path: "golang.org/x/net", Version: "v0.7.0"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("requiredModules() mismatch (-want +got):\n%s", diff)
+	}
+}
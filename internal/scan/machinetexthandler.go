@@ -0,0 +1,150 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// Prefixes of the lines written by machineTextHandler. They are part of
+// its output format, and must not change.
+const (
+	machineVuln    = "VULN:"
+	machineModule  = "MODULE:"
+	machineTrace   = "TRACE:"
+	machineSummary = "SUMMARY:"
+)
+
+// machineTextHandler writes findings as lines that start with a fixed
+// prefix, for the -machine-text flag. Fields are separated by spaces,
+// and the only field that can contain spaces comes last:
+//
+//	VULN: <id> <called|informational> <summary, or else details>
+//	MODULE: <id> <module> <found version> <fixed version, or ->
+//	TRACE: <id> <compact trace>
+//	SUMMARY: called=<n> informational=<n>
+//
+// Called vulnerabilities come first, followed by informational ones,
+// each in the order of the text output.
+type machineTextHandler struct {
+	w        io.Writer
+	osvs     []*osv.Entry
+	findings []*findingSummary
+	err      error
+}
+
+// newMachineTextHandler returns a handler that writes prefixed lines to w.
+func newMachineTextHandler(w io.Writer) *machineTextHandler {
+	return &machineTextHandler{w: w}
+}
+
+func (h *machineTextHandler) Config(config *govulncheck.Config) error {
+	return nil
+}
+
+func (h *machineTextHandler) Progress(progress *govulncheck.Progress) error {
+	return nil
+}
+
+// OSV gathers osv entries to be reported.
+func (h *machineTextHandler) OSV(entry *osv.Entry) error {
+	h.osvs = append(h.osvs, entry)
+	return nil
+}
+
+// Finding gathers vulnerability findings to be reported.
+func (h *machineTextHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	summary := newFindingSummary(finding)
+	summary.Compact = compactTrace(finding, symbolQualified, missingKeep)
+	h.findings = append(h.findings, summary)
+	return nil
+}
+
+// Flush writes the lines. As with the text output, it returns
+// errVulnerabilitiesFound if a vulnerability is called.
+func (h *machineTextHandler) Flush() error {
+	fixupFindings(h.osvs, h.findings)
+	byVuln := groupByVuln(h.findings)
+	called, informational := 0, 0
+	for _, findings := range byVuln {
+		if isCalled(findings) {
+			called++
+			h.vuln(findings, "called")
+		}
+	}
+	for _, findings := range byVuln {
+		if !isCalled(findings) {
+			informational++
+			h.vuln(findings, "informational")
+		}
+	}
+	h.line(machineSummary, fmt.Sprintf("called=%d", called), fmt.Sprintf("informational=%d", informational))
+	if h.err != nil {
+		return h.err
+	}
+	if called > 0 {
+		return errVulnerabilitiesFound
+	}
+	return nil
+}
+
+// vuln writes the lines of the vulnerability of findings.
+func (h *machineTextHandler) vuln(findings []*findingSummary, status string) {
+	entry := findings[0].OSV
+	description := entry.Summary
+	if description == "" {
+		description = entry.Details
+	}
+	h.line(machineVuln, entry.ID, status, description)
+	for _, module := range groupByModule(findings) {
+		frame := module[0].Trace[0]
+		fixed := moduleVersionString(frame.Module, module[0].FixedVersion)
+		if fixed == "" {
+			fixed = "-"
+		}
+		found := moduleVersionString(frame.Module, frame.Version)
+		if found == "" {
+			found = "-"
+		}
+		h.line(machineModule, entry.ID, frame.Module, found, fixed)
+	}
+	if status != "called" {
+		return
+	}
+	seen := map[string]bool{}
+	for _, f := range findings {
+		if f.Compact != "" && !seen[f.Compact] {
+			seen[f.Compact] = true
+			h.line(machineTrace, entry.ID, f.Compact)
+		}
+	}
+}
+
+// line writes a line of the prefix and fields. Empty fields are left
+// out, and new lines in fields are replaced by spaces, so that a line
+// never spans several.
+func (h *machineTextHandler) line(prefix string, fields ...string) {
+	if h.err != nil {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(prefix)
+	for _, f := range fields {
+		if f = strings.Join(strings.Fields(f), " "); f != "" {
+			b.WriteString(" ")
+			b.WriteString(f)
+		}
+	}
+	b.WriteString("\n")
+	_, h.err = io.WriteString(h.w, b.String())
+}
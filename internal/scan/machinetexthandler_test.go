@@ -0,0 +1,45 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMachineTextHandler(t *testing.T) {
+	var buf strings.Builder
+	h := newMachineTextHandler(&buf)
+	entries := testEntries()
+	entries[0].Summary = "Crash in\nparser"
+	entries[1].Summary, entries[1].Details = "", "Details of the second"
+	findings := testFindings()
+	// The informational finding comes first, but is listed last.
+	findings[0], findings[1] = findings[1], findings[0]
+	if err := runHandler(t, h, entries, findings); err != errVulnerabilitiesFound {
+		t.Errorf("Flush() = %v, want %v", err, errVulnerabilitiesFound)
+	}
+	want := `VULN: GO-0000-0001 called Crash in parser
+MODULE: GO-0000-0001 golang.org/vmod v1.0.0 -
+TRACE: GO-0000-0001 main.main calls vmod.Vuln
+VULN: GO-0000-0002 informational Details of the second
+MODULE: GO-0000-0002 golang.org/vmod v1.0.0 v1.0.1
+SUMMARY: called=1 informational=1
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMachineTextHandlerNoneCalled(t *testing.T) {
+	var buf strings.Builder
+	h := newMachineTextHandler(&buf)
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "SUMMARY: called=0 informational=0\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
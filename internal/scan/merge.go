@@ -0,0 +1,65 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+// runMerge replays the JSON reports named by the patterns of cfg into
+// handler, as one report, and flushes it. Each OSV entry is handed on
+// once, each finding is tagged with the report it comes from, and only
+// the configuration of the first report is kept, so that the summary
+// totals the findings of all the reports.
+func runMerge(handler govulncheck.Handler, cfg *config) error {
+	seen := map[string]bool{}
+	configured := false
+	for _, name := range cfg.patterns {
+		name := name
+		h := &mergeHandler{
+			targetHandler: targetHandler{Handler: handler, tag: func(f *govulncheck.Finding) { f.Source = name }, seen: seen},
+			configured:    &configured,
+		}
+		if err := mergeReport(name, h); err != nil {
+			return err
+		}
+	}
+	return Flush(handler)
+}
+
+func mergeReport(name string, h govulncheck.Handler) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := govulncheck.HandleJSON(f, h); err != nil {
+		return fmt.Errorf("govulncheck: reading %s: %v", name, err)
+	}
+	return nil
+}
+
+// mergeHandler is the targetHandler of one report of a merge. It also
+// drops the progress messages of the report, and its configuration
+// unless it is the first one.
+type mergeHandler struct {
+	targetHandler
+	configured *bool // shared by the handlers of all reports
+}
+
+func (h *mergeHandler) Config(config *govulncheck.Config) error {
+	if *h.configured {
+		return nil
+	}
+	*h.configured = true
+	return h.Handler.Config(config)
+}
+
+func (h *mergeHandler) Progress(*govulncheck.Progress) error {
+	return nil
+}
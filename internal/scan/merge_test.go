@@ -0,0 +1,80 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/test"
+)
+
+func TestMerge(t *testing.T) {
+	dir := t.TempDir()
+	// writeReport writes a report of one service, with the OSV entry and
+	// a called finding for each of the called IDs.
+	writeReport := func(name string, called ...string) string {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		h := govulncheck.NewJSONHandler(f)
+		if err := h.Config(&govulncheck.Config{ScannerName: name}); err != nil {
+			t.Fatal(err)
+		}
+		h.Progress(&govulncheck.Progress{Message: "Scanning " + name})
+		for _, id := range called {
+			h.OSV(&osv.Entry{ID: id, DatabaseSpecific: &osv.DatabaseSpecific{}})
+			h.Finding(&govulncheck.Finding{OSV: id, Trace: []*govulncheck.Frame{{Module: "m", Package: "m/p", Function: "F"}}})
+		}
+		return f.Name()
+	}
+	a := writeReport("a.json", "GO-0000-0001")
+	b := writeReport("b.json", "GO-0000-0001", "GO-0000-0002")
+	cfg := &config{patterns: []string{a, b}}
+
+	mock := test.NewMockHandler()
+	if err := runMerge(mock, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(mock.ConfigMessages) != 1 || mock.ConfigMessages[0].ScannerName != "a.json" {
+		t.Errorf("got configs %v; want only that of a.json", mock.ConfigMessages)
+	}
+	if len(mock.ProgressMessages) != 0 {
+		t.Errorf("got %d progress messages; want none", len(mock.ProgressMessages))
+	}
+	if len(mock.OSVMessages) != 2 {
+		t.Errorf("got %d OSV entries; want 2", len(mock.OSVMessages))
+	}
+	var sources []string
+	for _, f := range mock.FindingMessages {
+		sources = append(sources, f.OSV+" "+filepath.Base(f.Source))
+	}
+	if got, want := strings.Join(sources, ", "), "GO-0000-0001 a.json, GO-0000-0001 b.json, GO-0000-0002 b.json"; got != want {
+		t.Errorf("got findings %s; want %s", got, want)
+	}
+
+	var buf strings.Builder
+	if err := runMerge(NewTextHandler(&buf), cfg); err != errVulnerabilitiesFound {
+		t.Errorf("got error %v; want %v", err, errVulnerabilitiesFound)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"in " + a + ": p.F",
+		"in " + b + ": p.F",
+		"Your code is affected by 2 vulnerabilities from 1 module.",
+		"  " + a + ": 1 vulnerability\n",
+		"  " + b + ": 2 vulnerabilities\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+}
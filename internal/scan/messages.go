@@ -0,0 +1,341 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// message identifies a label or heading of text output that is looked
+// up in the catalog of the -lang language.
+type message int
+
+const (
+	msgVulnerability message = iota
+	msgMoreInfo
+	msgModule
+	msgStandardLibrary
+	msgFoundIn
+	msgFixedIn
+	msgNotAvailable
+	msgPlatforms
+	msgReachedThrough
+	msgReason
+	msgImportChain
+	msgInformationalVulns
+	msgOSVEntry
+	msgExampleTraces
+	msgInformationalSection
+	msgSymbolsSection
+	msgConsideredSection
+	msgFixableSection
+	msgNoFixSection
+	msgCriticalSection
+	msgHighSection
+	msgMediumSection
+	msgLowSection
+	msgUnclassifiedSection
+	msgSeverityOverridden
+	msgErrorModule
+	msgDatabaseEntry
+	msgCWE
+	msgTestCode
+	msgAffected
+	msgBoundaries
+	msgWhy
+	msgHighestSeverity
+	msgNoneCalled
+	msgAllVersions
+	msgPatchUpgrade
+	msgMinorUpgrade
+	msgMajorUpgrade
+	msgNewImportPath
+	msgNoVulnerabilities
+	msgFeedback
+	msgFindingReported
+	msgNoFinding
+	msgGoStandardLibrary
+
+	// The messages below are formats, printed with msgf. A count has a
+	// message for one, and another for any other number.
+	msgMissingOSVOne
+	msgMissingOSVMany
+	msgReachability
+	msgHiddenOne
+	msgHiddenMany
+	msgErrorModulesOne
+	msgErrorModulesMany
+	msgBlameOne
+	msgBlameMany
+	msgRangeBefore
+	msgRangeFrom
+	msgRangeFromBefore
+	msgWithinBudgetOne
+	msgWithinBudgetMany
+	msgOverBudgetOne
+	msgOverBudgetMany
+	msgFoundOne
+	msgFoundMany
+	msgInformationalOne
+	msgInformationalMany
+	msgCheckedOne
+	msgCheckedMany
+	msgCallsOne
+	msgCallsMany
+	msgAffectedByOne
+	msgAffectedByMany
+	msgModulesOne
+	msgModulesMany
+	msgVulnerabilitiesOne
+	msgVulnerabilitiesMany
+	msgAnd
+	msgIn
+	msgForFunction
+	msgUsing
+	msgUsingWith
+	msgDataFrom
+	msgDataModified
+	msgDataModifiedAge
+	msgModified
+	msgSHA256
+	msgReleasesBehindOne
+	msgReleasesBehindMany
+	msgFixCommand
+	msgDepth
+
+	// numMessages is the number of messages, which the English catalog
+	// must all have.
+	numMessages
+)
+
+// defaultLang is the default value of -lang.
+const defaultLang = "en"
+
+// catalogs holds the text of each message, by language. Every catalog
+// must have all messages, which TestCatalogs checks; a message missing
+// all the same is printed in English. Translations are added by adding
+// a catalog.
+var catalogs = map[string]map[message]string{
+	"en": {
+		msgVulnerability:        "Vulnerability",
+		msgMoreInfo:             "More info:",
+		msgModule:               "Module:",
+		msgStandardLibrary:      "Standard library",
+		msgFoundIn:              "Found in:",
+		msgFixedIn:              "Fixed in:",
+		msgNotAvailable:         "N/A",
+		msgPlatforms:            "Platforms:",
+		msgReachedThrough:       "Reached through:",
+		msgReason:               "Reason:",
+		msgImportChain:          "Import chain:",
+		msgInformationalVulns:   "Informational vulnerabilities:",
+		msgOSVEntry:             "OSV entry:",
+		msgExampleTraces:        "Example traces found:",
+		msgInformationalSection: "Informational",
+		msgSymbolsSection:       "Symbols",
+		msgConsideredSection:    "Considered",
+		msgFixableSection:       "Fixable",
+		msgNoFixSection:         "No fix available",
+		msgCriticalSection:      "Critical",
+		msgHighSection:          "High",
+		msgMediumSection:        "Medium",
+		msgLowSection:           "Low",
+		msgUnclassifiedSection:  "Unclassified",
+		msgSeverityOverridden:   "severity overridden",
+		msgErrorModule:          "error by -error-modules",
+		msgDatabaseEntry:        "Database entry:",
+		msgCWE:                  "CWE:",
+		msgTestCode:             "test code",
+		msgAffected:             "Affected:",
+		msgBoundaries:           "Not followed past:",
+		msgWhy:                  "Import path:",
+		msgHighestSeverity:      "Highest severity:",
+		msgNoneCalled:           "No called vulnerabilities found.",
+		msgAllVersions:          "all versions",
+		msgPatchUpgrade:         "patch upgrade",
+		msgMinorUpgrade:         "minor upgrade",
+		msgMajorUpgrade:         "major upgrade",
+		msgNewImportPath:        "major upgrade, new import path",
+		msgNoVulnerabilities:    "No vulnerabilities found.",
+		msgFeedback:             "Share feedback at https://go.dev/s/govulncheck-feedback.",
+		msgFindingReported:      "finding reported",
+		msgNoFinding:            "no finding",
+		msgGoStandardLibrary:    "the Go standard library",
+
+		msgMissingOSVOne:    "Warning: skipped %d finding of %s, whose OSV entries were not reported.",
+		msgMissingOSVMany:   "Warning: skipped %d findings of %s, whose OSV entries were not reported.",
+		msgReachability:     "Reachability: %d called, %d imported but not called, %d required but not imported.",
+		msgHiddenOne:        "And %d more called vulnerability, not listed with -top=%d.",
+		msgHiddenMany:       "And %d more called vulnerabilities, not listed with -top=%d.",
+		msgErrorModulesOne:  "Failing on %d informational vulnerability in modules given to -error-modules.",
+		msgErrorModulesMany: "Failing on %d informational vulnerabilities in modules given to -error-modules.",
+		msgBlameOne:         "Kept %d of %d vulnerability, those with findings through %s.",
+		msgBlameMany:        "Kept %d of %d vulnerabilities, those with findings through %s.",
+		msgRangeBefore:      "before %s",
+		msgRangeFrom:        "from %s",
+		msgRangeFromBefore:  "from %s before %s",
+		msgWithinBudgetOne:  "Within the -max-findings budget of %d called vulnerability.",
+		msgWithinBudgetMany: "Within the -max-findings budget of %d called vulnerabilities.",
+		msgOverBudgetOne:    "Failing: over the -max-findings budget of %d called vulnerability.",
+		msgOverBudgetMany:   "Failing: over the -max-findings budget of %d called vulnerabilities.",
+		msgFoundOne:         "Found %d vulnerability (%d called, %d informational).",
+		msgFoundMany:        "Found %d vulnerabilities (%d called, %d informational).",
+		msgInformationalOne: "Found %d vulnerability in packages that you import, but there are no call\n" +
+			"stacks leading to the use of this vulnerability. You may not need to\n" +
+			"take any action. See https://pkg.go.dev/golang.org/x/vuln/cmd/govulncheck\n" +
+			"for details.",
+		msgInformationalMany: "Found %d vulnerabilities in packages that you import, but there are no call\n" +
+			"stacks leading to the use of these vulnerabilities. You may not need to\n" +
+			"take any action. See https://pkg.go.dev/golang.org/x/vuln/cmd/govulncheck\n" +
+			"for details.",
+		msgCheckedOne:          "Checked %d vulnerability against your dependencies.",
+		msgCheckedMany:         "Checked %d vulnerabilities against your dependencies.",
+		msgCallsOne:            "Your code calls %d vulnerable symbol.",
+		msgCallsMany:           "Your code calls %d vulnerable symbols.",
+		msgAffectedByOne:       "Your code is affected by %d vulnerability from %s.",
+		msgAffectedByMany:      "Your code is affected by %d vulnerabilities from %s.",
+		msgModulesOne:          "%d module",
+		msgModulesMany:         "%d modules",
+		msgVulnerabilitiesOne:  "%d vulnerability",
+		msgVulnerabilitiesMany: "%d vulnerabilities",
+		msgAnd:                 "%s and %s",
+		msgIn:                  "in %s:",
+		msgForFunction:         "for function %s",
+		msgUsing:               "Using %s.",
+		msgUsingWith:           "Using %s with %s.",
+		msgDataFrom:            "vulnerability data from %s",
+		msgDataModified:        "vulnerability data from %s (last modified %s)",
+		msgDataModifiedAge:     "vulnerability data from %s (last modified %s, %s old)",
+		msgModified:            "modified %s",
+		msgSHA256:              "sha256 %s",
+		msgReleasesBehindOne:   "%d release behind",
+		msgReleasesBehindMany:  "%d releases behind",
+		msgFixCommand:          "run: go get %s@%s",
+		msgDepth:               "depth %d",
+	},
+}
+
+// languages returns the languages with a catalog, sorted.
+func languages() string {
+	var langs []string
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return strings.Join(langs, ", ")
+}
+
+// msg returns the text of m in the language of h.
+func (h *TextHandler) msg(m message) string {
+	if s, ok := catalogs[h.lang][m]; ok {
+		return s
+	}
+	return catalogs[defaultLang][m]
+}
+
+// msgf returns the text of the format m in the language of h, formatted
+// with args.
+func (h *TextHandler) msgf(m message, args ...any) string {
+	return fmt.Sprintf(h.msg(m), args...)
+}
+
+// styled is an argument of stylef that is printed in its own style.
+type styled struct {
+	style style
+	value any
+}
+
+// phrase is an argument of stylef that is itself a format, printed the
+// same way as the format it is an argument of.
+type phrase struct {
+	m    message
+	args []any
+}
+
+// stylef prints the format m in the language of h in style, formatted
+// with args. Unlike msgf, it keeps the style of each styled argument, so
+// that the numbers of a sentence, say, stand out.
+func (h *TextHandler) stylef(s style, m message, args ...any) {
+	format := h.msg(m)
+	next := 0
+	for {
+		i := strings.IndexByte(format, '%')
+		if i < 0 {
+			break
+		}
+		h.styleText(s, format[:i])
+		format = format[i+1:]
+		if strings.HasPrefix(format, "%") {
+			h.styleText(s, "%")
+			format = format[1:]
+			continue
+		}
+		// A translation may reorder the arguments with explicit indexes.
+		if strings.HasPrefix(format, "[") {
+			if j := strings.IndexByte(format, ']'); j > 0 {
+				if n, err := strconv.Atoi(format[1:j]); err == nil {
+					next = n - 1
+				}
+				format = format[j+1:]
+			}
+		}
+		j := strings.IndexFunc(format, unicode.IsLetter)
+		if j < 0 {
+			h.styleText(s, "%"+format)
+			return
+		}
+		verb := "%" + format[:j+1]
+		format = format[j+1:]
+		if next < 0 || next >= len(args) {
+			h.styleText(s, fmt.Sprintf(verb))
+			next++
+			continue
+		}
+		switch a := args[next].(type) {
+		case phrase:
+			h.stylef(s, a.m, a.args...)
+		case styled:
+			h.styleText(a.style, fmt.Sprintf(verb, a.value))
+		case []styled:
+			for _, v := range a {
+				h.styleText(v.style, fmt.Sprintf(verb, v.value))
+			}
+		default:
+			h.styleText(s, fmt.Sprintf(verb, a))
+		}
+		next++
+	}
+	h.styleText(s, format)
+}
+
+// styleText prints text in style, if there is any text. Text in the
+// default style is printed as is.
+func (h *TextHandler) styleText(s style, text string) {
+	switch {
+	case text == "":
+	case s == defaultStyle:
+		h.print(text)
+	default:
+		h.style(s, text)
+	}
+}
+
+// plural returns the message one for a count n of 1, and many for any
+// other count.
+func plural(n int, one, many message) message {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// section returns the heading of the section named by m.
+func (h *TextHandler) section(m message) string {
+	return "=== " + h.msg(m) + " ===\n"
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestCatalogs(t *testing.T) {
+	if _, ok := catalogs[defaultLang]; !ok {
+		t.Fatalf("no %s catalog", defaultLang)
+	}
+	for lang, catalog := range catalogs {
+		for m := msgVulnerability; m < numMessages; m++ {
+			if catalog[m] == "" {
+				t.Errorf("message %d has no %s text", m, lang)
+			}
+		}
+	}
+}
+
+func TestStylef(t *testing.T) {
+	var buf strings.Builder
+	h := NewTextHandler(&buf)
+	h.showColor = true
+	h.stylef(summaryStyle, msgAffectedByMany, styled{valueStyle, 2}, phrase{msgModulesOne, []any{styled{valueStyle, 1}}})
+	want := colorBold + "Your code is affected by " + colorReset +
+		colorBold + fgCyan + "2" + colorReset +
+		colorBold + " vulnerabilities from " + colorReset +
+		colorBold + fgCyan + "1" + colorReset +
+		colorBold + " module" + colorReset +
+		colorBold + "." + colorReset
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestLang(t *testing.T) {
+	catalogs["test"] = map[message]string{msgFoundIn: "Trouvé dans :"}
+	defer delete(catalogs, "test")
+
+	var buf strings.Builder
+	h := NewTextHandler(&buf)
+	h.Lang("test")
+	h.OSV(&osv.Entry{ID: "GO-0000-0001", DatabaseSpecific: &osv.DatabaseSpecific{}})
+	h.Finding(&govulncheck.Finding{
+		OSV:   "GO-0000-0001",
+		Trace: []*govulncheck.Frame{{Module: "golang.org/a", Version: "v1.0.0", Package: "golang.org/a", Function: "V"}},
+	})
+	h.Flush()
+	got := buf.String()
+	// Messages missing from the catalog are in English.
+	for _, want := range []string{"Trouvé dans : golang.org/a@v1.0.0", "Fixed in: N/A"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+}
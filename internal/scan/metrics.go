@@ -0,0 +1,179 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// metricsHandler wraps a handler and, once the wrapped handler has been
+// flushed, writes counts of the findings passed through it to a file in
+// the Prometheus text exposition format, for the node exporter textfile
+// collector, or pushes them to a Pushgateway, or both.
+type metricsHandler struct {
+	govulncheck.Handler
+	path     string       // file to write, if not empty
+	push     *pushgateway // Pushgateway to push to, if not nil
+	config   *govulncheck.Config
+	osvs     []*osv.Entry
+	findings []*findingSummary
+}
+
+// pushgateway is where a Pushgateway groups pushed metrics: its URL,
+// and the job and instance labels of the grouping key.
+type pushgateway struct {
+	url      string
+	job      string
+	instance string
+}
+
+// newMetricsHandler returns a handler that passes everything on to h and
+// writes metrics to path, if not empty, and pushes them to push, if not
+// nil, when flushed.
+func newMetricsHandler(h govulncheck.Handler, path string, push *pushgateway) *metricsHandler {
+	return &metricsHandler{Handler: h, path: path, push: push}
+}
+
+// Config records the scanner version and the time of the scan, for the
+// labels and the timestamp of the metrics.
+func (h *metricsHandler) Config(config *govulncheck.Config) error {
+	h.config = config
+	return h.Handler.Config(config)
+}
+
+func (h *metricsHandler) OSV(entry *osv.Entry) error {
+	h.osvs = append(h.osvs, entry)
+	return h.Handler.OSV(entry)
+}
+
+func (h *metricsHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	h.findings = append(h.findings, newFindingSummary(finding))
+	return h.Handler.Finding(finding)
+}
+
+// Flush flushes the wrapped handler and then writes and pushes the
+// metrics, even if vulnerabilities were found. A failure to write them
+// does not keep them from being pushed, and the errors of all three are
+// returned.
+func (h *metricsHandler) Flush() error {
+	err := Flush(h.Handler)
+	metrics := h.metrics()
+	var werr, perr error
+	if h.path != "" {
+		if e := h.write(metrics); e != nil {
+			werr = fmt.Errorf("writing metrics: %w", e)
+		}
+	}
+	if h.push != nil {
+		if e := h.push.push(metrics); e != nil {
+			perr = fmt.Errorf("pushing metrics: %w", e)
+		}
+	}
+	return joinErrors(werr, perr, err)
+}
+
+// push replaces the metrics of the grouping key of p with metrics.
+func (p *pushgateway) push(metrics string) error {
+	u := strings.TrimRight(p.url, "/") + "/metrics/" + groupingLabel("job", p.job)
+	if p.instance != "" {
+		u += "/" + groupingLabel("instance", p.instance)
+	}
+	req, err := http.NewRequest(http.MethodPut, u, strings.NewReader(metrics))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s %s", u, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// write writes metrics to a temporary file next to h.path and then
+// renames it, so that the collector never reads a partial file.
+func (h *metricsHandler) write(metrics string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(h.path), filepath.Base(h.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(metrics)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), h.path)
+}
+
+// metrics returns the contents of the metrics file. Explicit timestamps
+// are not supported by the textfile collector, so the time of the scan is
+// a gauge of its own.
+func (h *metricsHandler) metrics() string {
+	fixupFindings(h.osvs, h.findings)
+	c := counters(h.findings)
+	modules := c.ModulesCalled
+	if c.StdlibCalled {
+		modules++
+	}
+	informational := 0
+	for _, vuln := range groupByVuln(h.findings) {
+		if !isCalled(vuln) {
+			informational++
+		}
+	}
+	labels := ""
+	if h.config != nil && h.config.ScannerVersion != "" {
+		labels = fmt.Sprintf(`{scanner_version="%s"}`, promEscape(h.config.ScannerVersion))
+	}
+	var b strings.Builder
+	gauge := func(name, help string, value int64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s%s %d\n", name, help, name, name, labels, value)
+	}
+	gauge("govulncheck_called_vulnerabilities", "Number of vulnerabilities called by the scanned code.", int64(c.VulnerabilitiesCalled))
+	gauge("govulncheck_informational", "Number of vulnerabilities imported or required but not called.", int64(informational))
+	gauge("govulncheck_modules_affected", "Number of modules, including the standard library, with called vulnerabilities.", int64(modules))
+	if h.config != nil && h.config.ScanTime != nil {
+		gauge("govulncheck_scan_timestamp_seconds", "Time of the scan, in seconds since the Unix epoch.", h.config.ScanTime.Unix())
+	}
+	return b.String()
+}
+
+// groupingLabel returns the URL path elements of a label of the grouping
+// key. Values with a slash are base64 encoded, as the Pushgateway
+// requires.
+func groupingLabel(name, value string) string {
+	if strings.Contains(value, "/") {
+		return name + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+	return name + "/" + url.PathEscape(value)
+}
+
+// promEscape escapes s for use as a label value.
+func promEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,108 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/test"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "govulncheck.prom")
+	mock := test.NewMockHandler()
+	h := newMetricsHandler(mock, path, nil)
+	scanTime := time.Unix(1685620800, 0)
+	if err := h.Config(&govulncheck.Config{ScannerVersion: "v1.0.0", ScanTime: &scanTime}); err != nil {
+		t.Fatal(err)
+	}
+	// A called vulnerability of the standard library.
+	entries := append(testEntries(), &osv.Entry{ID: "GO-0000-0003"})
+	findings := append(testFindings(), &govulncheck.Finding{OSV: "GO-0000-0003", Trace: []*govulncheck.Frame{{Module: "stdlib", Package: "net/http", Function: "Get"}}})
+	if err := runHandler(t, h, entries, findings); err != nil {
+		t.Fatal(err)
+	}
+	if len(mock.FindingMessages) != 3 {
+		t.Errorf("got %d findings passed through; want 3", len(mock.FindingMessages))
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP govulncheck_called_vulnerabilities Number of vulnerabilities called by the scanned code.
+# TYPE govulncheck_called_vulnerabilities gauge
+govulncheck_called_vulnerabilities{scanner_version="v1.0.0"} 2
+# HELP govulncheck_informational Number of vulnerabilities imported or required but not called.
+# TYPE govulncheck_informational gauge
+govulncheck_informational{scanner_version="v1.0.0"} 1
+# HELP govulncheck_modules_affected Number of modules, including the standard library, with called vulnerabilities.
+# TYPE govulncheck_modules_affected gauge
+govulncheck_modules_affected{scanner_version="v1.0.0"} 2
+# HELP govulncheck_scan_timestamp_seconds Time of the scan, in seconds since the Unix epoch.
+# TYPE govulncheck_scan_timestamp_seconds gauge
+govulncheck_scan_timestamp_seconds{scanner_version="v1.0.0"} 1685620800
+`
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if tmps, _ := filepath.Glob(path + ".*.tmp"); len(tmps) > 0 {
+		t.Errorf("temporary files left behind: %v", tmps)
+	}
+}
+
+func TestMetricsHandlerPush(t *testing.T) {
+	var gotPath, gotMethod, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.EscapedPath()
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+	}))
+	defer srv.Close()
+
+	push := &pushgateway{url: srv.URL + "/", job: "ci", instance: "runner/1"}
+	h := newMetricsHandler(test.NewMockHandler(), "", push)
+	if err := runHandler(t, h, testEntries(), testFindings()); err != nil {
+		t.Fatal(err)
+	}
+	if want := "/metrics/job/ci/instance@base64/cnVubmVyLzE"; gotMethod != http.MethodPut || gotPath != want {
+		t.Errorf("got %s %s; want PUT %s", gotMethod, gotPath, want)
+	}
+	if !strings.Contains(gotBody, "govulncheck_called_vulnerabilities 1\n") {
+		t.Errorf("pushed metrics lack the called count:\n%s", gotBody)
+	}
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad metrics", http.StatusBadRequest)
+	})
+	if err := newMetricsHandler(test.NewMockHandler(), "", push).Flush(); err == nil || !strings.Contains(err.Error(), "bad metrics") {
+		t.Errorf("got error %v; want the Pushgateway's", err)
+	}
+
+	// A failure to write the file does not keep the metrics from being
+	// pushed, nor hide the error of the wrapped handler.
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.EscapedPath()
+	})
+	gotMethod = ""
+	missing := filepath.Join(t.TempDir(), "missing", "govulncheck.prom")
+	h = newMetricsHandler(NewTextHandler(io.Discard), missing, push)
+	err := runHandler(t, h, testEntries(), testFindings())
+	if err == nil || !strings.Contains(err.Error(), "writing metrics") || !errors.Is(err, errVulnerabilitiesFound) {
+		t.Errorf("got error %v; want the write error and errVulnerabilitiesFound", err)
+	}
+	if gotMethod != http.MethodPut {
+		t.Error("the metrics were not pushed after the write failed")
+	}
+}
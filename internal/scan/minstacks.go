@@ -0,0 +1,90 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+// reasonMinStacks is the reason reported for called vulnerabilities
+// demoted by -min-stacks.
+const reasonMinStacks = "%d distinct call stacks found, fewer than the %d required by -min-stacks"
+
+// minStacksHandler wraps a handler and demotes to informational the
+// called vulnerabilities reached by fewer than min distinct call stacks.
+// As that is only known once all findings are in, findings are held
+// back until the handler is flushed.
+type minStacksHandler struct {
+	govulncheck.Handler
+	min      int
+	findings []*govulncheck.Finding
+}
+
+// Finding holds finding back until the handler is flushed.
+func (h *minStacksHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	h.findings = append(h.findings, finding)
+	return nil
+}
+
+// Flush hands the findings on, in the order they were received, and
+// then flushes the wrapped handler. The findings of a demoted
+// vulnerability are replaced by a single informational finding.
+func (h *minStacksHandler) Flush() error {
+	stacks := map[string]map[string]bool{}
+	for _, f := range h.findings {
+		if f.Trace[0].Function == "" {
+			continue
+		}
+		if stacks[f.OSV] == nil {
+			stacks[f.OSV] = map[string]bool{}
+		}
+		stacks[f.OSV][traceKey(f.Trace)] = true
+	}
+	demoted := map[string]bool{}
+	for _, f := range h.findings {
+		n, called := len(stacks[f.OSV]), f.Trace[0].Function != ""
+		if !called || n >= h.min {
+			if err := h.Handler.Finding(f); err != nil {
+				return err
+			}
+			continue
+		}
+		if demoted[f.OSV] {
+			continue
+		}
+		demoted[f.OSV] = true
+		frame := f.Trace[0]
+		if err := h.Handler.Finding(&govulncheck.Finding{
+			OSV:          f.OSV,
+			FixedVersion: f.FixedVersion,
+			Trace:        []*govulncheck.Frame{{Module: frame.Module, Version: frame.Version, Package: frame.Package}},
+			Reason:       fmt.Sprintf(reasonMinStacks, n, h.min),
+			ScanLevel:    f.ScanLevel,
+			Why:          f.Why,
+		}); err != nil {
+			return err
+		}
+	}
+	return Flush(h.Handler)
+}
+
+// traceKey returns a string that identifies the call stack of trace.
+func traceKey(trace []*govulncheck.Frame) string {
+	var b strings.Builder
+	for _, f := range trace {
+		fmt.Fprintf(&b, "%s.%s.%s", f.Package, f.Receiver, f.Function)
+		if f.Position != nil {
+			fmt.Fprintf(&b, ":%s:%d:%d", f.Position.Filename, f.Position.Line, f.Position.Column)
+		}
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/test"
+)
+
+func TestMinStacksHandler(t *testing.T) {
+	mock := test.NewMockHandler()
+	h := &minStacksHandler{Handler: mock, min: 2}
+	trace := func(fns ...string) []*govulncheck.Frame {
+		var frames []*govulncheck.Frame
+		for _, fn := range fns {
+			frames = append(frames, &govulncheck.Frame{Module: "golang.org/a", Version: "v1.0.0", Package: "golang.org/a/p", Function: fn})
+		}
+		return frames
+	}
+	for _, f := range []*govulncheck.Finding{
+		{OSV: "GO-0000-0001", FixedVersion: "v1.0.1", Trace: trace("V", "A")},
+		{OSV: "GO-0000-0001", FixedVersion: "v1.0.1", Trace: trace("V", "B")},
+		{OSV: "GO-0000-0002", FixedVersion: "v1.0.2", Trace: trace("W", "A")},
+		// The same stack reported twice counts once.
+		{OSV: "GO-0000-0002", FixedVersion: "v1.0.2", Trace: trace("W", "A")},
+		{OSV: "GO-0000-0003", Trace: trace("")},
+	} {
+		if err := h.Finding(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(mock.FindingMessages) != 0 {
+		t.Fatalf("got %d findings before Flush; want none", len(mock.FindingMessages))
+	}
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range mock.FindingMessages {
+		got = append(got, f.OSV)
+	}
+	want := []string{"GO-0000-0001", "GO-0000-0001", "GO-0000-0002", "GO-0000-0003"}
+	if len(got) != len(want) {
+		t.Fatalf("got findings for %v; want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got findings for %v; want %v", got, want)
+		}
+	}
+	demoted := mock.FindingMessages[2]
+	if demoted.Trace[0].Function != "" || demoted.FixedVersion != "v1.0.2" {
+		t.Errorf("got demoted finding %+v; want informational with fixed version v1.0.2", demoted)
+	}
+	wantReason := "1 distinct call stacks found, fewer than the 2 required by -min-stacks"
+	if demoted.Reason != wantReason {
+		t.Errorf("got reason %q; want %q", demoted.Reason, wantReason)
+	}
+}
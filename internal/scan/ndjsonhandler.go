@@ -0,0 +1,45 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"encoding/json"
+	"io"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// ndjsonHandler writes only the findings, as newline-delimited JSON with
+// one finding per line. Each finding has the same schema as in the JSON
+// message stream.
+type ndjsonHandler struct {
+	enc *json.Encoder
+}
+
+// newNDJSONHandler returns a handler that writes findings to w.
+func newNDJSONHandler(w io.Writer) *ndjsonHandler {
+	return &ndjsonHandler{enc: json.NewEncoder(w)}
+}
+
+func (h *ndjsonHandler) Config(config *govulncheck.Config) error {
+	return nil
+}
+
+func (h *ndjsonHandler) Progress(progress *govulncheck.Progress) error {
+	return nil
+}
+
+func (h *ndjsonHandler) OSV(entry *osv.Entry) error {
+	return nil
+}
+
+// Finding writes finding on a line of its own.
+func (h *ndjsonHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	return h.enc.Encode(finding)
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestNDJSONHandler(t *testing.T) {
+	var buf strings.Builder
+	h := newNDJSONHandler(&buf)
+	if err := h.Config(&govulncheck.Config{ScannerName: "govulncheck"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Progress(&govulncheck.Progress{Message: "Scanning..."}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.OSV(&osv.Entry{ID: "GO-0000-0001"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []*govulncheck.Finding{
+		{OSV: "GO-0000-0001", FixedVersion: "v1.0.1", Trace: []*govulncheck.Frame{{Module: "golang.org/a", Package: "golang.org/a", Function: "F"}}},
+		{OSV: "GO-0000-0002", Trace: []*govulncheck.Frame{{Module: "golang.org/b"}}, Reason: "no call stack found"},
+	} {
+		if err := h.Finding(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := `{"osv":"GO-0000-0001","fixed_version":"v1.0.1","trace":[{"module":"golang.org/a","package":"golang.org/a","function":"F"}]}
+{"osv":"GO-0000-0002","trace":[{"module":"golang.org/b"}],"reason":"no call stack found"}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// osvHandler writes the OSV entries that have at least one finding as a
+// JSON array, for consumption by OSV-native tooling. Each entry is
+// annotated with the module versions, packages and symbols it was matched
+// against.
+type osvHandler struct {
+	w       io.Writer
+	entries []*osv.Entry
+	matches map[string][]osvMatch
+}
+
+// osvEntry is an OSV entry whose affected packages carry the matches
+// found by govulncheck.
+type osvEntry struct {
+	*osv.Entry
+	Affected []osvAffected `json:"affected"`
+}
+
+// osvAffected is an OSV affected package with a database_specific field
+// recording where it was matched.
+type osvAffected struct {
+	osv.Affected
+	DatabaseSpecific *osvAffectedSpecific `json:"database_specific,omitempty"`
+}
+
+type osvAffectedSpecific struct {
+	Matches []osvMatch `json:"govulncheck_matches"`
+}
+
+// osvMatch describes a module version, and if known the package and
+// symbol, that an OSV entry was matched against.
+type osvMatch struct {
+	Module  string `json:"module"`
+	Version string `json:"version,omitempty"`
+	Package string `json:"package,omitempty"`
+	Symbol  string `json:"symbol,omitempty"`
+}
+
+// newOSVHandler returns a handler that writes the matched OSV entries to w.
+func newOSVHandler(w io.Writer) *osvHandler {
+	return &osvHandler{w: w, matches: map[string][]osvMatch{}}
+}
+
+func (h *osvHandler) Config(config *govulncheck.Config) error {
+	return nil
+}
+
+func (h *osvHandler) Progress(progress *govulncheck.Progress) error {
+	return nil
+}
+
+// OSV gathers osv entries to be written.
+func (h *osvHandler) OSV(entry *osv.Entry) error {
+	h.entries = append(h.entries, entry)
+	return nil
+}
+
+// Finding records the match described by finding. Findings that match
+// an entry at the same place are only recorded once.
+func (h *osvHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	frame := finding.Trace[0]
+	m := osvMatch{
+		Module:  frame.Module,
+		Version: frame.Version,
+		Package: frame.Package,
+		Symbol:  symbol(&govulncheck.Frame{Function: frame.Function, Receiver: frame.Receiver}, symbolFull),
+	}
+	for _, prev := range h.matches[finding.OSV] {
+		if prev == m {
+			return nil
+		}
+	}
+	h.matches[finding.OSV] = append(h.matches[finding.OSV], m)
+	return nil
+}
+
+// Flush writes the entries that have been matched, sorted by ID, each
+// entry at most once. Entries are not received in a deterministic order.
+func (h *osvHandler) Flush() error {
+	out := []*osvEntry{}
+	written := map[string]bool{}
+	for _, e := range h.entries {
+		matches := h.matches[e.ID]
+		if len(matches) == 0 || written[e.ID] {
+			continue
+		}
+		written[e.ID] = true
+		out = append(out, newOSVEntry(e, matches))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = h.w.Write(append(b, '\n'))
+	return err
+}
+
+// newOSVEntry attaches each match to the affected package of e for
+// the matched module. Matches against a module that e does not list,
+// as can happen for replaced modules, are attached to the first
+// affected package.
+func newOSVEntry(e *osv.Entry, matches []osvMatch) *osvEntry {
+	out := &osvEntry{Entry: e}
+	byModule := map[string][]osvMatch{}
+	for _, m := range matches {
+		i := 0
+		for j, a := range e.Affected {
+			if a.Module.Path == m.Module {
+				i = j
+				break
+			}
+		}
+		if len(e.Affected) > 0 {
+			path := e.Affected[i].Module.Path
+			byModule[path] = append(byModule[path], m)
+		}
+	}
+	for _, a := range e.Affected {
+		oa := osvAffected{Affected: a}
+		if ms := byModule[a.Module.Path]; len(ms) > 0 {
+			oa.DatabaseSpecific = &osvAffectedSpecific{Matches: ms}
+			// Only attach the matches once, even if the module is
+			// listed in several affected packages.
+			delete(byModule, a.Module.Path)
+		}
+		out.Affected = append(out.Affected, oa)
+	}
+	return out
+}
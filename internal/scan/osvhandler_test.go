@@ -0,0 +1,72 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestOSVHandler(t *testing.T) {
+	entries := []*osv.Entry{
+		{ID: "GO-0000-0001", Affected: []osv.Affected{{Module: osv.Module{Path: "golang.org/vmod"}}}},
+		{ID: "GO-0000-0002", Affected: []osv.Affected{{Module: osv.Module{Path: "stdlib"}}}},
+	}
+	called := &govulncheck.Finding{
+		OSV: "GO-0000-0001",
+		Trace: []*govulncheck.Frame{
+			{Module: "golang.org/vmod", Version: "v0.0.1", Package: "golang.org/vmod", Function: "Vuln"},
+			{Module: "golang.org/main", Package: "golang.org/main", Function: "main"},
+		},
+	}
+	var buf bytes.Buffer
+	h := newOSVHandler(&buf)
+	for _, e := range entries {
+		if err := h.OSV(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The same match is only recorded once, and the entry without
+	// findings is left out.
+	for i := 0; i < 2; i++ {
+		if err := h.Finding(called); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]any{{
+		"id":      "GO-0000-0001",
+		"details": "",
+		"affected": []any{map[string]any{
+			"package":            map[string]any{"name": "golang.org/vmod", "ecosystem": ""},
+			"ecosystem_specific": map[string]any{},
+			"database_specific": map[string]any{
+				"govulncheck_matches": []any{map[string]any{
+					"module":  "golang.org/vmod",
+					"version": "v0.0.1",
+					"package": "golang.org/vmod",
+					"symbol":  "Vuln",
+				}},
+			},
+		}},
+		"modified":  "0001-01-01T00:00:00Z",
+		"published": "0001-01-01T00:00:00Z",
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
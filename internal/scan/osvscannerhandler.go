@@ -0,0 +1,138 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// The types below mirror the parts of the JSON results of osv-scanner
+// (https://github.com/google/osv-scanner) that govulncheck has data for.
+
+type osvScannerResults struct {
+	Results []*osvScannerResult `json:"results"`
+}
+
+type osvScannerResult struct {
+	Source   osvScannerSource          `json:"source"`
+	Packages []*osvScannerPackageVulns `json:"packages"`
+}
+
+type osvScannerSource struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+type osvScannerPackageVulns struct {
+	Package         osvScannerPackage  `json:"package"`
+	Vulnerabilities []*osv.Entry       `json:"vulnerabilities"`
+	Groups          []*osvScannerGroup `json:"groups"`
+}
+
+type osvScannerPackage struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type osvScannerGroup struct {
+	IDs                  []string                      `json:"ids"`
+	Aliases              []string                      `json:"aliases"`
+	ExperimentalAnalysis map[string]osvScannerAnalysis `json:"experimentalAnalysis"`
+	MaxSeverity          string                        `json:"max_severity"`
+}
+
+type osvScannerAnalysis struct {
+	Called bool `json:"called"`
+}
+
+// osvScannerHandler writes the findings as the results of osv-scanner,
+// with a package for each module that has a finding. Whether a
+// vulnerability is called is kept in the experimental call analysis of
+// its group, but the call stacks are dropped.
+type osvScannerHandler struct {
+	w        io.Writer
+	source   osvScannerSource
+	osvs     []*osv.Entry
+	findings []*findingSummary
+}
+
+// newOSVScannerHandler returns a handler that writes osv-scanner results
+// to w, for the scanned go.mod file or binary at path.
+func newOSVScannerHandler(w io.Writer, path string) *osvScannerHandler {
+	return &osvScannerHandler{w: w, source: osvScannerSource{Path: path, Type: "lockfile"}}
+}
+
+func (h *osvScannerHandler) Config(config *govulncheck.Config) error {
+	return nil
+}
+
+func (h *osvScannerHandler) Progress(progress *govulncheck.Progress) error {
+	return nil
+}
+
+// OSV gathers osv entries to be written.
+func (h *osvScannerHandler) OSV(entry *osv.Entry) error {
+	h.osvs = append(h.osvs, entry)
+	return nil
+}
+
+// Finding gathers vulnerability findings to be written.
+func (h *osvScannerHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	h.findings = append(h.findings, newFindingSummary(finding))
+	return nil
+}
+
+// Flush writes the results, with the packages in module path order. The
+// results have no packages if nothing was found.
+func (h *osvScannerHandler) Flush() error {
+	fixupFindings(h.osvs, h.findings)
+	result := &osvScannerResult{Source: h.source, Packages: []*osvScannerPackageVulns{}}
+	for _, module := range groupByModule(h.findings) {
+		frame := module[0].Trace[0]
+		pkg := &osvScannerPackageVulns{
+			Package: osvScannerPackage{
+				Name:      frame.Module,
+				Version:   strings.TrimPrefix(frame.Version, "v"),
+				Ecosystem: "Go",
+			},
+		}
+		for _, vuln := range groupByVuln(module) {
+			entry := vuln[0].OSV
+			pkg.Vulnerabilities = append(pkg.Vulnerabilities, entry)
+			pkg.Groups = append(pkg.Groups, &osvScannerGroup{
+				IDs:                  []string{entry.ID},
+				Aliases:              append([]string{entry.ID}, entry.Aliases...),
+				ExperimentalAnalysis: map[string]osvScannerAnalysis{entry.ID: {Called: isCalled(vuln)}},
+			})
+		}
+		result.Packages = append(result.Packages, pkg)
+	}
+	b, err := json.MarshalIndent(&osvScannerResults{Results: []*osvScannerResult{result}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = h.w.Write(append(b, '\n'))
+	return err
+}
+
+// scannedPath returns the path of what cfg scans, as the source of
+// osv-scanner results: the binary in binary mode, and the go.mod file of
+// the -C directory otherwise.
+func scannedPath(cfg *config) string {
+	if cfg.mode == modeBinary && len(cfg.patterns) > 0 {
+		return cfg.patterns[0]
+	}
+	return filepath.Join(cfg.dir, "go.mod")
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestOSVScannerHandler(t *testing.T) {
+	var buf strings.Builder
+	h := newOSVScannerHandler(&buf, "go.mod")
+	entries := append(testEntries(), &osv.Entry{ID: "GO-0000-0003"})
+	entries[1].Aliases = []string{"CVE-0000-0002"}
+	findings := append(testFindings(),
+		// The called GO-0000-0001 is also imported.
+		&govulncheck.Finding{OSV: "GO-0000-0001", Trace: []*govulncheck.Frame{{Module: "golang.org/vmod", Version: "v1.0.0"}}},
+		&govulncheck.Finding{OSV: "GO-0000-0003", Trace: []*govulncheck.Frame{{Module: "stdlib", Version: "v1.20.0", Package: "net/http"}}},
+	)
+	if err := runHandler(t, h, entries, findings); err != nil {
+		t.Fatal(err)
+	}
+	var got osvScannerResults
+	if err := json.Unmarshal([]byte(buf.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Results) != 1 {
+		t.Fatalf("got %d results; want 1", len(got.Results))
+	}
+	result := got.Results[0]
+	if want := (osvScannerSource{Path: "go.mod", Type: "lockfile"}); result.Source != want {
+		t.Errorf("got source %+v; want %+v", result.Source, want)
+	}
+	var pkgs []osvScannerPackage
+	called := map[string]bool{}
+	for _, p := range result.Packages {
+		pkgs = append(pkgs, p.Package)
+		for _, g := range p.Groups {
+			for id, a := range g.ExperimentalAnalysis {
+				called[id] = a.Called
+			}
+		}
+	}
+	wantPkgs := []osvScannerPackage{
+		{Name: "golang.org/vmod", Version: "1.0.0", Ecosystem: "Go"},
+		{Name: "stdlib", Version: "1.20.0", Ecosystem: "Go"},
+	}
+	if !reflect.DeepEqual(pkgs, wantPkgs) {
+		t.Errorf("got packages %+v; want %+v", pkgs, wantPkgs)
+	}
+	if want := map[string]bool{"GO-0000-0001": true, "GO-0000-0002": false, "GO-0000-0003": false}; !reflect.DeepEqual(called, want) {
+		t.Errorf("got call analysis %v; want %v", called, want)
+	}
+	if got, want := result.Packages[0].Groups[0].Aliases, []string{"GO-0000-0002", "CVE-0000-0002"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got aliases %v; want %v", got, want)
+	}
+}
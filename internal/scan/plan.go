@@ -0,0 +1,188 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/vuln/internal"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// planStep is a single module upgrade of a remediation plan.
+type planStep struct {
+	module  string   // module path, or "" for Go itself
+	version string   // version to upgrade to, in semver
+	cleared []string // IDs of the called vulnerabilities it clears
+}
+
+// planHandler writes a remediation plan: the module upgrades that clear
+// the called vulnerabilities, the ones that clear the most first.
+type planHandler struct {
+	w        io.Writer
+	osvs     []*osv.Entry
+	findings []*findingSummary
+}
+
+// newPlanHandler returns a handler that writes a remediation plan to w.
+func newPlanHandler(w io.Writer) *planHandler {
+	return &planHandler{w: w}
+}
+
+func (h *planHandler) Config(config *govulncheck.Config) error {
+	return nil
+}
+
+func (h *planHandler) Progress(progress *govulncheck.Progress) error {
+	return nil
+}
+
+// OSV gathers osv entries for the findings.
+func (h *planHandler) OSV(entry *osv.Entry) error {
+	h.osvs = append(h.osvs, entry)
+	return nil
+}
+
+// Finding gathers the called vulnerability findings to be planned for.
+func (h *planHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	if finding.Trace[0].Function != "" {
+		h.findings = append(h.findings, newFindingSummary(finding))
+	}
+	return nil
+}
+
+// Flush writes the plan, followed by the called vulnerabilities that
+// no upgrade clears. Like the findings it replaces, it fails with
+// errVulnerabilitiesFound if any vulnerability is called.
+func (h *planHandler) Flush() error {
+	fixupFindings(h.osvs, h.findings)
+	steps, unfixed := plan(h.findings)
+	var b strings.Builder
+	total := len(groupByVuln(h.findings))
+	if total == 0 {
+		b.WriteString("No called vulnerabilities to remediate.\n")
+	} else {
+		fmt.Fprintf(&b, "Remediation plan for %s:\n\n", choose(total == 1, "1 called vulnerability", fmt.Sprintf("%d called vulnerabilities", total)))
+	}
+	for _, s := range steps {
+		target := fmt.Sprintf("upgrade %s@%s", s.module, s.version)
+		if s.module == "" {
+			target = "upgrade Go to " + semverToGoTag(s.version)
+		}
+		fmt.Fprintf(&b, "%s clears %d %s (%s)\n", target, len(s.cleared), choose(len(s.cleared) == 1, "vuln", "vulns"), strings.Join(s.cleared, ", "))
+	}
+	if len(unfixed) > 0 {
+		if len(steps) > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "No upgrade clears %s: %s\n", choose(len(unfixed) == 1, "1 vuln", fmt.Sprintf("%d vulns", len(unfixed))), strings.Join(unfixed, ", "))
+	}
+	if _, err := io.WriteString(h.w, b.String()); err != nil {
+		return err
+	}
+	if total > 0 {
+		return errVulnerabilitiesFound
+	}
+	return nil
+}
+
+// plan returns the upgrades that clear the vulnerabilities of the
+// called findings, one per module, and the IDs of the vulnerabilities
+// without a fixed version. A vulnerability that has a fixed version in
+// one module but not in another is listed with the modules that no
+// upgrade fixes it in, as in "GO-2023-0001 in example.com/m". Each module is upgraded to the highest fixed
+// version among its vulnerabilities, which clears all of them. The
+// standard library and the go command are upgraded together, as Go.
+// The steps are sorted by the number of vulnerabilities they clear,
+// most first, and then by module path, with Go after the modules.
+func plan(findings []*findingSummary) ([]*planStep, []string) {
+	var steps []*planStep
+	var goStep *planStep
+	unfixed := map[string][]string{} // modules by ID
+	for _, module := range groupByModule(findings) {
+		mod := module[0].Trace[0].Module
+		step := &planStep{module: mod}
+		if mod == internal.GoStdModulePath || mod == internal.GoCmdModulePath {
+			if goStep == nil {
+				goStep = &planStep{}
+			}
+			step = goStep
+		}
+		for _, vuln := range groupByVuln(module) {
+			fixed := ""
+			for _, f := range vuln {
+				if semver.Compare(f.FixedVersion, fixed) > 0 {
+					fixed = f.FixedVersion
+				}
+			}
+			id := vuln[0].OSV.ID
+			if fixed == "" {
+				unfixed[id] = append(unfixed[id], mod)
+				continue
+			}
+			if semver.Compare(fixed, step.version) > 0 {
+				step.version = fixed
+			}
+			step.cleared = appendUnique(step.cleared, id)
+		}
+		if step != goStep && len(step.cleared) > 0 {
+			steps = append(steps, step)
+		}
+	}
+	if goStep != nil && len(goStep.cleared) > 0 {
+		steps = append(steps, goStep)
+	}
+	for _, s := range steps {
+		sort.Strings(s.cleared)
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		if len(steps[i].cleared) != len(steps[j].cleared) {
+			return len(steps[i].cleared) > len(steps[j].cleared)
+		}
+		if (steps[i].module == "") != (steps[j].module == "") {
+			return steps[j].module == ""
+		}
+		return steps[i].module < steps[j].module
+	})
+	cleared := map[string]bool{}
+	for _, s := range steps {
+		for _, id := range s.cleared {
+			cleared[id] = true
+		}
+	}
+	var ids []string
+	for id, mods := range unfixed {
+		if !cleared[id] {
+			ids = append(ids, id)
+			continue
+		}
+		for _, mod := range mods {
+			if mod == internal.GoStdModulePath || mod == internal.GoCmdModulePath {
+				mod = "Go"
+			}
+			ids = appendUnique(ids, id+" in "+mod)
+		}
+	}
+	sort.Strings(ids)
+	return steps, ids
+}
+
+// appendUnique appends s to list unless it is already there.
+func appendUnique(list []string, s string) []string {
+	for _, x := range list {
+		if x == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestPlanHandler(t *testing.T) {
+	var buf strings.Builder
+	h := newPlanHandler(&buf)
+	for _, id := range []string{"GO-0000-0001", "GO-0000-0002", "GO-0000-0003", "GO-0000-0004", "GO-0000-0005", "GO-0000-0006"} {
+		h.OSV(&osv.Entry{ID: id})
+	}
+	finding := func(id, mod, fixed, fn string) *govulncheck.Finding {
+		return &govulncheck.Finding{
+			OSV:          id,
+			FixedVersion: fixed,
+			Trace:        []*govulncheck.Frame{{Module: mod, Version: "v1.0.0", Package: mod, Function: fn}},
+		}
+	}
+	for _, f := range []*govulncheck.Finding{
+		finding("GO-0000-0001", "golang.org/a", "v1.0.1", "F"),
+		finding("GO-0000-0002", "stdlib", "v1.20.3", "G"),
+		finding("GO-0000-0003", "golang.org/b", "v1.2.0", "F"),
+		finding("GO-0000-0004", "golang.org/b", "v1.1.0", "F"),
+		finding("GO-0000-0005", "toolchain", "v1.20.5", "G"),
+		finding("GO-0000-0006", "golang.org/c", "", "F"),
+		// A vulnerability fixed in one module but not in another.
+		finding("GO-0000-0003", "golang.org/e", "", "F"),
+		// Informational findings are not part of the plan.
+		finding("GO-0000-0001", "golang.org/d", "v2.0.0", ""),
+	} {
+		if err := h.Finding(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Flush(); err != errVulnerabilitiesFound {
+		t.Fatalf("got error %v, want %v", err, errVulnerabilitiesFound)
+	}
+	want := `Remediation plan for 6 called vulnerabilities:
+
+upgrade golang.org/b@v1.2.0 clears 2 vulns (GO-0000-0003, GO-0000-0004)
+upgrade Go to go1.20.5 clears 2 vulns (GO-0000-0002, GO-0000-0005)
+upgrade golang.org/a@v1.0.1 clears 1 vuln (GO-0000-0001)
+
+No upgrade clears 2 vulns: GO-0000-0003 in golang.org/e, GO-0000-0006
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -19,6 +19,22 @@ import (
 
 var update = flag.Bool("update", false, "update test files with results")
 
+// textOptions are the options of the text files that set the handler
+// through something other than Show, named after the flags they stand
+// for. The other options are passed to Show.
+var textOptions = map[string]func(h *scan.TextHandler){
+	"color-by-severity": func(h *scan.TextHandler) { h.ColorBy("severity") },
+	"error-modules":     func(h *scan.TextHandler) { h.ErrorModules([]string{"golang.org/vmod"}) },
+	"group-module":      func(h *scan.TextHandler) { h.Group("module") },
+	"group-severity":    func(h *scan.TextHandler) { h.Group("severity") },
+	"max-findings-2":    func(h *scan.TextHandler) { h.MaxFindings(2) },
+	"max-findings-3":    func(h *scan.TextHandler) { h.MaxFindings(3) },
+	"no-footer":         func(h *scan.TextHandler) { h.FooterOnClean(false) },
+	"sort-stacks":       func(h *scan.TextHandler) { h.SortBy("stacks") },
+	"split-fixable":     func(h *scan.TextHandler) { h.SplitFixable(true) },
+	"top-2":             func(h *scan.TextHandler) { h.Top(2) },
+}
+
 func TestPrinting(t *testing.T) {
 	testdata := os.DirFS("testdata")
 	inputs, err := fs.Glob(testdata, "*.json")
@@ -38,7 +54,15 @@ func TestPrinting(t *testing.T) {
 				wantText, _ := fs.ReadFile(testdata, textfile)
 				got := &bytes.Buffer{}
 				handler := scan.NewTextHandler(got)
-				handler.Show(strings.Split(textname, "_")[1:])
+				var show []string
+				for _, option := range strings.Split(textname, "_")[1:] {
+					if set, ok := textOptions[option]; ok {
+						set(handler)
+					} else {
+						show = append(show, option)
+					}
+				}
+				handler.Show(show)
 				testRunHandler(t, rawJSON, handler)
 				if diff := cmp.Diff(string(wantText), got.String()); diff != "" {
 					if *update {
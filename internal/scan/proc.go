@@ -0,0 +1,36 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"strconv"
+)
+
+// processExecutable returns the path of the executable of the running
+// process pid, which can be scanned like any binary even if the file it
+// was started from was replaced or removed since. It is only supported
+// on Linux, where the kernel exposes it as /proc/pid/exe.
+func processExecutable(pid int) (string, error) {
+	if runtime.GOOS != "linux" {
+		return "", fmt.Errorf("the -pid flag is only supported on Linux")
+	}
+	path := "/proc/" + strconv.Itoa(pid) + "/exe"
+	f, err := os.Open(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return "", fmt.Errorf("there is no process with ID %d", pid)
+	case errors.Is(err, fs.ErrPermission):
+		return "", fmt.Errorf("cannot read the executable of process %d: permission denied; run govulncheck as the user of the process, or as root", pid)
+	case err != nil:
+		return "", fmt.Errorf("cannot read the executable of process %d: %v", pid, err)
+	}
+	f.Close()
+	return path, nil
+}
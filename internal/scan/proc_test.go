@@ -0,0 +1,32 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestProcessExecutable(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		if _, err := processExecutable(os.Getpid()); err == nil {
+			t.Errorf("got no error on %s; want one", runtime.GOOS)
+		}
+		return
+	}
+	exe, err := processExecutable(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isFile(exe) {
+		t.Errorf("%s is not a file", exe)
+	}
+	// Process IDs are below 2^22 on Linux.
+	if _, err := processExecutable(1 << 30); err == nil || !strings.Contains(err.Error(), "no process") {
+		t.Errorf("got error %v; want one saying there is no such process", err)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"golang.org/x/vuln/internal/client"
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+// provenanceHandler wraps a handler and sets the provenance of each
+// finding to that of its OSV entry, as recorded by the database client,
+// for -show=provenance.
+type provenanceHandler struct {
+	govulncheck.Handler
+	client *client.Client
+}
+
+// Finding sets the provenance of finding and hands it on.
+func (h *provenanceHandler) Finding(finding *govulncheck.Finding) error {
+	if p := h.client.Provenance(finding.OSV); p != nil {
+		finding.Provenance = &govulncheck.Provenance{Endpoint: p.Endpoint, SHA256: p.SHA256}
+	}
+	return h.Handler.Finding(finding)
+}
+
+// Flush flushes the wrapped handler.
+func (h *provenanceHandler) Flush() error {
+	return Flush(h.Handler)
+}
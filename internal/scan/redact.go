@@ -0,0 +1,124 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+const (
+	// homePlaceholder replaces the home directory in redacted output.
+	homePlaceholder = "~"
+
+	// redactedPlaceholder replaces the prefixes given with -redact-prefix.
+	redactedPlaceholder = "<redacted>"
+)
+
+// redactHandler wraps a handler and replaces the home directory and a
+// list of path prefixes in the messages passed through it, so that the
+// output can be shared without revealing local paths or internal module
+// names.
+type redactHandler struct {
+	govulncheck.Handler
+	prefixes []redaction
+}
+
+type redaction struct {
+	prefix, placeholder string
+}
+
+// newRedactHandler returns a handler redacting prefixes, as well as the
+// home directory of the current user, before calling h.
+func newRedactHandler(h govulncheck.Handler, prefixes []string) *redactHandler {
+	rh := &redactHandler{Handler: h}
+	for _, p := range prefixes {
+		if p = strings.TrimRight(p, `/\`); p != "" {
+			rh.prefixes = append(rh.prefixes, redaction{p, redactedPlaceholder})
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		rh.prefixes = append(rh.prefixes, redaction{strings.TrimRight(home, `/\`), homePlaceholder})
+	}
+	// Try longer prefixes first, so that the most specific one wins.
+	sort.SliceStable(rh.prefixes, func(i, j int) bool {
+		return len(rh.prefixes[i].prefix) > len(rh.prefixes[j].prefix)
+	})
+	return rh
+}
+
+// redact replaces the first matching prefix of s with its placeholder.
+// A prefix only matches whole path elements.
+func (h *redactHandler) redact(s string) string {
+	for _, r := range h.prefixes {
+		if !strings.HasPrefix(s, r.prefix) {
+			continue
+		}
+		rest := s[len(r.prefix):]
+		if rest == "" || rest[0] == '/' || rest[0] == os.PathSeparator {
+			return r.placeholder + rest
+		}
+	}
+	return s
+}
+
+// Config redacts the database location of config.
+func (h *redactHandler) Config(config *govulncheck.Config) error {
+	c := *config
+	const fileScheme = "file://"
+	if db := strings.TrimPrefix(c.DB, fileScheme); db != c.DB {
+		c.DB = fileScheme + h.redact(db)
+	} else {
+		c.DB = h.redact(c.DB)
+	}
+	return h.Handler.Config(&c)
+}
+
+// Finding redacts the module and package paths and the positions of
+// the trace of finding, as well as its import chains and boundaries.
+func (h *redactHandler) Finding(finding *govulncheck.Finding) error {
+	f := *finding
+	f.Trace = make([]*govulncheck.Frame, len(finding.Trace))
+	for i, frame := range finding.Trace {
+		fr := *frame
+		fr.Module = h.redact(fr.Module)
+		fr.Package = h.redact(fr.Package)
+		if fr.Position != nil {
+			pos := *fr.Position
+			pos.Filename = h.redact(pos.Filename)
+			fr.Position = &pos
+		}
+		f.Trace[i] = &fr
+	}
+	if len(finding.ImportChain) > 0 {
+		f.ImportChain = make([]string, len(finding.ImportChain))
+		for i, mod := range finding.ImportChain {
+			f.ImportChain[i] = h.redact(mod)
+		}
+	}
+	if len(finding.Why) > 0 {
+		f.Why = make([]string, len(finding.Why))
+		for i, pkg := range finding.Why {
+			f.Why[i] = h.redact(pkg)
+		}
+	}
+	if len(finding.Boundaries) > 0 {
+		f.Boundaries = make([]string, len(finding.Boundaries))
+		for i, b := range finding.Boundaries {
+			if kind, fn, ok := strings.Cut(b, ": "); ok {
+				b = kind + ": " + h.redact(fn)
+			}
+			f.Boundaries[i] = b
+		}
+	}
+	return h.Handler.Finding(&f)
+}
+
+func (h *redactHandler) Flush() error {
+	return Flush(h.Handler)
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/test"
+)
+
+func TestRedactHandler(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses unix paths")
+	}
+	t.Setenv("HOME", "/home/gopher")
+
+	mock := test.NewMockHandler()
+	h := newRedactHandler(mock, []string{"corp.example/internal", "/home/gopher/src/secret/"})
+	if err := h.Config(&govulncheck.Config{DB: "file:///home/gopher/vulndb"}); err != nil {
+		t.Fatal(err)
+	}
+	finding := &govulncheck.Finding{
+		OSV: "GO-0000-0001",
+		Trace: []*govulncheck.Frame{
+			{Module: "golang.org/vmod", Package: "golang.org/vmod", Function: "Vuln"},
+			{
+				Module:   "corp.example/internal/app",
+				Package:  "corp.example/internal/app/cmd",
+				Function: "main",
+				Position: &govulncheck.Position{Filename: "/home/gopher/src/secret/app/main.go", Line: 1},
+			},
+			{
+				Module:   "corp.example/internalother",
+				Function: "f",
+				Position: &govulncheck.Position{Filename: "/home/gopher/go/other.go", Line: 2},
+			},
+		},
+		ImportChain: []string{"corp.example/internal/app", "golang.org/vmod"},
+	}
+	if err := h.Finding(finding); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := mock.ConfigMessages[0].DB, "file://~/vulndb"; got != want {
+		t.Errorf("got DB %q; want %q", got, want)
+	}
+	want := &govulncheck.Finding{
+		OSV: "GO-0000-0001",
+		Trace: []*govulncheck.Frame{
+			{Module: "golang.org/vmod", Package: "golang.org/vmod", Function: "Vuln"},
+			{
+				Module:   "<redacted>/app",
+				Package:  "<redacted>/app/cmd",
+				Function: "main",
+				Position: &govulncheck.Position{Filename: "<redacted>/app/main.go", Line: 1},
+			},
+			{
+				Module:   "corp.example/internalother",
+				Function: "f",
+				Position: &govulncheck.Position{Filename: "~/go/other.go", Line: 2},
+			},
+		},
+		ImportChain: []string{"<redacted>/app", "golang.org/vmod"},
+	}
+	if diff := cmp.Diff(want, mock.FindingMessages[0]); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	// The original finding must not be modified.
+	if got := finding.Trace[1].Module; got != "corp.example/internal/app" {
+		t.Errorf("original finding was modified: module is %q", got)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+// isRemoteModule reports whether patterns name a single module at a
+// version, of the form module@version, rather than local packages.
+func isRemoteModule(patterns []string) bool {
+	return len(patterns) == 1 && strings.Contains(patterns[0], "@")
+}
+
+// fetchModule downloads the module named by the module@version pattern
+// of cfg through the module proxy and copies it into a temporary
+// directory. It then points cfg at all packages of that directory and
+// records the fetched version in cfg.Module. The returned function
+// removes the temporary directory.
+func fetchModule(ctx context.Context, cfg *config) (cleanup func(), err error) {
+	mod, ver, err := parseModuleQuery(cfg.patterns[0])
+	if err != nil {
+		return nil, err
+	}
+	env := cfg.env
+	if len(env) == 0 {
+		env = os.Environ()
+	}
+	cmd := exec.CommandContext(ctx, "go", "mod", "download", "-json", mod+"@"+ver)
+	cmd.Env = env
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	// go mod download -json reports most failures in the Error field.
+	var info struct {
+		Version string
+		Dir     string
+		Error   string
+	}
+	if jerr := json.Unmarshal(out, &info); jerr == nil && info.Error != "" {
+		return nil, fmt.Errorf("govulncheck: fetching %s@%s: %s", mod, ver, info.Error)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("govulncheck: fetching %s@%s: %v\n%s", mod, ver, err, stderr.Bytes())
+	}
+
+	tmp, err := os.MkdirTemp("", "govulncheck-")
+	if err != nil {
+		return nil, err
+	}
+	cleanup = func() { os.RemoveAll(tmp) }
+	if err := copyDir(tmp, info.Dir); err != nil {
+		cleanup()
+		return nil, err
+	}
+	// Modules that predate modules have no go.mod file.
+	gomod := filepath.Join(tmp, "go.mod")
+	if !fileExists(gomod) {
+		if err := os.WriteFile(gomod, []byte("module "+mod+"\n"), 0644); err != nil {
+			cleanup()
+			return nil, err
+		}
+	}
+
+	cfg.Module = mod + "@" + info.Version
+	cfg.dir = tmp
+	cfg.patterns = []string{"./..."}
+	// The go.sum file of the module, if any, need not cover all the
+	// packages that are loaded, so let the go command update it.
+	cfg.env = append(append([]string(nil), env...), "GOFLAGS=-mod=mod")
+	return cleanup, nil
+}
+
+// copyDir copies the files of the directory tree at src into dst. The
+// copies are writable, unlike the files in the module cache.
+func copyDir(dst, src string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		return copyFile(target, path)
+	})
+}
+
+func copyFile(dst, src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// remoteModuleProgressMessage returns the progress message reported
+// after a module has been fetched for scanning.
+func remoteModuleProgressMessage(module string) *govulncheck.Progress {
+	return &govulncheck.Progress{
+		Message: fmt.Sprintf("Fetched %s for scanning.", module),
+	}
+}
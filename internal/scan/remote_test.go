@@ -0,0 +1,88 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/zip"
+	"golang.org/x/vuln/internal/web"
+)
+
+func TestFetchModule(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	// Serve example.com/m@v1.0.0 from a file-based module proxy.
+	src := t.TempDir()
+	const gomod = "module example.com/m\n\ngo 1.18\n"
+	if err := os.WriteFile(filepath.Join(src, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "m.go"), []byte("package m\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	proxy := t.TempDir()
+	vdir := filepath.Join(proxy, "example.com", "m", "@v")
+	if err := os.MkdirAll(vdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"list":        "v1.0.0\n",
+		"v1.0.0.info": `{"Version":"v1.0.0"}`,
+		"v1.0.0.mod":  gomod,
+	} {
+		if err := os.WriteFile(filepath.Join(vdir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	z, err := os.Create(filepath.Join(vdir, "v1.0.0.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := zip.CreateFromDir(z, module.Version{Path: "example.com/m", Version: "v1.0.0"}, src); err != nil {
+		t.Fatal(err)
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+	proxyURL, err := web.URLFromFilePath(proxy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config{
+		patterns: []string{"example.com/m@v1.0.0"},
+		env: append(os.Environ(),
+			"GOPROXY="+proxyURL.String(),
+			"GOSUMDB=off",
+			"GOMODCACHE="+t.TempDir(),
+			"GOFLAGS=-modcacherw",
+		),
+	}
+	cleanup, err := fetchModule(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := cfg.dir
+	if got, want := cfg.Module, "example.com/m@v1.0.0"; got != want {
+		t.Errorf("got module %q; want %q", got, want)
+	}
+	if got := cfg.patterns; len(got) != 1 || got[0] != "./..." {
+		t.Errorf("got patterns %q; want [./...]", got)
+	}
+	if !fileExists(filepath.Join(dir, "m.go")) {
+		t.Errorf("m.go was not copied to %s", dir)
+	}
+	cleanup()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("temporary directory %s was not removed", dir)
+	}
+}
@@ -5,17 +5,20 @@
 package scan
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
 )
 
 func TestFrame(t *testing.T) {
 	for _, test := range []struct {
 		name     string
 		frame    *govulncheck.Frame
-		short    bool
+		format   string
 		wantFunc string
 		wantPos  string
 	}{
@@ -56,20 +59,81 @@ func TestFrame(t *testing.T) {
 		{
 			name:     "short",
 			frame:    &govulncheck.Frame{Package: "net/http", Function: "Get"},
-			short:    true,
+			format:   symbolQualified,
 			wantFunc: "http.Get",
 		},
+		{
+			name:     "function only",
+			frame:    &govulncheck.Frame{Package: "net/http", Receiver: "*ServeMux", Function: "Handle"},
+			format:   symbolShort,
+			wantFunc: "ServeMux.Handle",
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			buf := &strings.Builder{}
-			addSymbolName(buf, test.frame, test.short)
+			addSymbolName(buf, test.frame, test.format)
 			got := buf.String()
 			if got != test.wantFunc {
 				t.Errorf("want %v func name; got %v", test.wantFunc, got)
 			}
-			if got := posToString(test.frame.Position); got != test.wantPos {
+			if got := posToString(test.frame.Position, missingKeep); got != test.wantPos {
 				t.Errorf("want %v call position; got %v", test.wantPos, got)
 			}
 		})
 	}
 }
+
+func TestPosToStringMissing(t *testing.T) {
+	dir := t.TempDir()
+	present := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(present, []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gone := filepath.Join(dir, "gen.go")
+	for _, test := range []struct {
+		file, missing string
+		want          string
+	}{
+		{present, missingOmit, present + ":3:1"},
+		{present, missingFlag, present + ":3:1"},
+		{gone, missingKeep, gone + ":3:1"},
+		{gone, missingOmit, ""},
+		{gone, missingFlag, gone + ":3:1 (file not found)"},
+	} {
+		p := &govulncheck.Position{Filename: test.file, Line: 3, Column: 1}
+		if got := posToString(p, test.missing); got != test.want {
+			t.Errorf("posToString(%s, %s) = %q; want %q", filepath.Base(test.file), test.missing, got, test.want)
+		}
+	}
+}
+
+func TestAffectsPlatform(t *testing.T) {
+	e := &osv.Entry{
+		Affected: []osv.Affected{{
+			Module: osv.Module{Path: "golang.org/vmod"},
+			EcosystemSpecific: osv.EcosystemSpecific{
+				Packages: []osv.Package{{
+					Path:   "golang.org/vmod",
+					GOOS:   []string{"windows"},
+					GOARCH: []string{"amd64", "arm64"},
+				}},
+			},
+		}, {
+			Module: osv.Module{Path: "golang.org/all"},
+		}},
+	}
+	for _, test := range []struct {
+		mod, goos, goarch string
+		want              bool
+	}{
+		{"golang.org/vmod", "windows", "amd64", true},
+		{"golang.org/vmod", "windows", "386", false},
+		{"golang.org/vmod", "linux", "amd64", false},
+		{"golang.org/all", "linux", "386", true},
+		{"golang.org/other", "linux", "amd64", false},
+	} {
+		if got := affectsPlatform(test.mod, e, test.goos, test.goarch); got != test.want {
+			t.Errorf("affectsPlatform(%s, %s/%s) = %t; want %t", test.mod, test.goos, test.goarch, got, test.want)
+		}
+	}
+}
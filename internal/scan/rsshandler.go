@@ -0,0 +1,172 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// rssDocument is an RSS 2.0 document, as specified at
+// https://www.rssboard.org/rss-specification.
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string     `xml:"title"`
+	Link          string     `xml:"link"`
+	Description   string     `xml:"description"`
+	LastBuildDate string     `xml:"lastBuildDate,omitempty"`
+	Items         []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	Description string  `xml:"description,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// rssChannelLink is the link of the feed, to the list of vulnerabilities
+// of the Go vulnerability database.
+const rssChannelLink = "https://pkg.go.dev/vuln/list"
+
+// rssHandler writes an RSS feed with an item for each called
+// vulnerability.
+type rssHandler struct {
+	w        io.Writer
+	osvs     []*osv.Entry
+	findings []*findingSummary
+}
+
+// newRSSHandler returns a handler that writes an RSS feed to w.
+func newRSSHandler(w io.Writer) *rssHandler {
+	return &rssHandler{w: w}
+}
+
+func (h *rssHandler) Config(config *govulncheck.Config) error {
+	return nil
+}
+
+func (h *rssHandler) Progress(progress *govulncheck.Progress) error {
+	return nil
+}
+
+// OSV gathers osv entries to be reported.
+func (h *rssHandler) OSV(entry *osv.Entry) error {
+	h.osvs = append(h.osvs, entry)
+	return nil
+}
+
+// Finding gathers vulnerability findings to be reported.
+func (h *rssHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	h.findings = append(h.findings, newFindingSummary(finding))
+	return nil
+}
+
+// Flush writes the feed, with the items in the order of the text output.
+// The feed was last built when its latest entry was last modified, so
+// that it does not change when the findings do not.
+func (h *rssHandler) Flush() error {
+	fixupFindings(h.osvs, h.findings)
+	doc := &rssDocument{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       "govulncheck",
+			Link:        rssChannelLink,
+			Description: "Vulnerabilities called by the scanned code",
+		},
+	}
+	var latest time.Time
+	for _, findings := range groupByVuln(h.findings) {
+		if !isCalled(findings) {
+			continue
+		}
+		doc.Channel.Items = append(doc.Channel.Items, rssEntryItem(findings))
+		if m := findings[0].OSV.Modified; m.After(latest) {
+			latest = m
+		}
+	}
+	if !latest.IsZero() {
+		doc.Channel.LastBuildDate = latest.UTC().Format(time.RFC1123Z)
+	}
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(h.w, xml.Header); err != nil {
+		return err
+	}
+	_, err = h.w.Write(append(b, '\n'))
+	return err
+}
+
+// rssEntryItem returns the item of the called vulnerability of findings.
+func rssEntryItem(findings []*findingSummary) *rssItem {
+	entry := findings[0].OSV
+	item := &rssItem{
+		Title:       entry.ID,
+		Description: entry.Details,
+		GUID:        rssGUID{Value: rssItemGUID(findings)},
+	}
+	if entry.Summary != "" {
+		item.Title += ": " + entry.Summary
+	}
+	if entry.DatabaseSpecific != nil {
+		item.Link = entry.DatabaseSpecific.URL
+	}
+	if !entry.Modified.IsZero() {
+		item.PubDate = entry.Modified.UTC().Format(time.RFC1123Z)
+	}
+	return item
+}
+
+// rssItemGUID returns a GUID for the called vulnerability of findings,
+// from its ID and the vulnerable symbols that are called. Like Code
+// Climate fingerprints, it leaves out positions and versions, so that
+// feed readers see the same item for as long as the same symbols are
+// called, and a new one once others are.
+func rssItemGUID(findings []*findingSummary) string {
+	var symbols []string
+	seen := map[string]bool{}
+	for _, f := range findings {
+		frame := f.Trace[0]
+		if frame.Function == "" {
+			continue
+		}
+		s := fmt.Sprint(frame.Module, " ", frame.Package, " ", frame.Receiver, " ", frame.Function)
+		if !seen[s] {
+			seen[s] = true
+			symbols = append(symbols, s)
+		}
+	}
+	sort.Strings(symbols)
+	hash := sha256.New()
+	fmt.Fprintln(hash, findings[0].OSV.ID)
+	for _, s := range symbols {
+		fmt.Fprintln(hash, s)
+	}
+	return "govulncheck:" + findings[0].OSV.ID + ":" + hex.EncodeToString(hash.Sum(nil))[:16]
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestRSSHandler(t *testing.T) {
+	var buf strings.Builder
+	h := newRSSHandler(&buf)
+	entries := testEntries()
+	for i, e := range entries {
+		e.Modified = time.Date(2023, 4, 1+i, 12, 0, 0, 0, time.UTC)
+		e.Details = "Some details."
+	}
+	entries[0].Summary = "Crash in <parser>"
+	if err := runHandler(t, h, entries, testFindings()); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<rss version="2.0">`,
+		`<lastBuildDate>Sat, 01 Apr 2023 12:00:00 +0000</lastBuildDate>`,
+		`<title>GO-0000-0001: Crash in &lt;parser&gt;</title>`,
+		`<link>https://pkg.go.dev/vuln/GO-0000-0001</link>`,
+		`<guid isPermaLink="false">govulncheck:GO-0000-0001:`,
+		`<pubDate>Sat, 01 Apr 2023 12:00:00 +0000</pubDate>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("feed does not contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "GO-0000-0002") {
+		t.Errorf("feed contains the informational GO-0000-0002:\n%s", got)
+	}
+}
+
+func TestRSSItemGUID(t *testing.T) {
+	summary := func(fn string, line int) *findingSummary {
+		return &findingSummary{
+			OSV: &osv.Entry{ID: "GO-0000-0001"},
+			Finding: &govulncheck.Finding{
+				OSV: "GO-0000-0001",
+				Trace: []*govulncheck.Frame{
+					{Module: "golang.org/vmod", Version: "v1.0.0", Package: "golang.org/vmod", Function: fn},
+					{Module: "golang.org/main", Package: "golang.org/main", Function: "main", Position: &govulncheck.Position{Line: line}},
+				},
+			},
+		}
+	}
+	a := rssItemGUID([]*findingSummary{summary("Vuln", 1), summary("Other", 2)})
+	b := rssItemGUID([]*findingSummary{summary("Other", 7), summary("Vuln", 3), summary("Vuln", 4)})
+	if a != b {
+		t.Errorf("GUIDs of the same called symbols differ: %s and %s", a, b)
+	}
+	if c := rssItemGUID([]*findingSummary{summary("Vuln", 1)}); c == a {
+		t.Errorf("GUIDs of different called symbols are both %s", c)
+	}
+}
@@ -8,43 +8,134 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
+	"runtime"
 	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/vuln/internal/client"
 	"golang.org/x/vuln/internal/govulncheck"
 )
 
+// An Option configures optional behavior of RunGovulncheck.
+type Option func(*runOptions)
+
+type runOptions struct {
+	findingHooks []func(*govulncheck.Finding)
+}
+
+// WithFindingHook returns an Option that calls hook for each finding, in
+// addition to the handler selected by the command line flags.
+//
+// The hook is called after the handler's Finding method has accepted the
+// finding, and all hooks have been called by the time the handler is
+// flushed. Hooks are called in the order they are supplied.
+func WithFindingHook(hook func(*govulncheck.Finding)) Option {
+	return func(o *runOptions) {
+		o.findingHooks = append(o.findingHooks, hook)
+	}
+}
+
+// wrap returns handler augmented with the behavior requested by o.
+func (o *runOptions) wrap(handler govulncheck.Handler) govulncheck.Handler {
+	if len(o.findingHooks) > 0 {
+		handler = &hookHandler{Handler: handler, hooks: o.findingHooks}
+	}
+	return handler
+}
+
 // RunGovulncheck performs main govulncheck functionality and exits the
 // program upon success with an appropriate exit status. Otherwise,
 // returns an error.
-func RunGovulncheck(ctx context.Context, env []string, r io.Reader, stdout io.Writer, stderr io.Writer, args []string) error {
+func RunGovulncheck(ctx context.Context, env []string, r io.Reader, stdout io.Writer, stderr io.Writer, args []string, opts ...Option) error {
+	options := &runOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
 	cfg := &config{env: env}
-	if err := parseFlags(cfg, stderr, args); err != nil {
+	if err := parseFlags(cfg, r, stderr, args); err != nil {
 		return err
 	}
+	if cfg.verbose {
+		cfg.timings = stderr
+	}
+	if cfg.checkOnly {
+		// Only the exit code is wanted. Errors and warnings still go to
+		// stderr, so that real failures are not hidden.
+		stdout = io.Discard
+	}
+	if cfg.mode == modeSource {
+		for _, w := range overlappingPatterns(cfg.patterns) {
+			fmt.Fprintf(stderr, "govulncheck: warning: %s\n", w)
+		}
+	}
 	if cfg.mode == modeConvert {
-		return convertJSONToText(r, stdout)
+		// Convert the JSON in the given file, if any, or else standard input.
+		if len(cfg.patterns) == 1 {
+			f, err := os.Open(cfg.patterns[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		if cfg.verify {
+			return verifyJSON(r)
+		}
+		return convertJSONToText(ctx, r, stdout, stderr, cfg, options)
+	}
+	if cfg.mode == modeTrend {
+		return runTrend(stdout, cfg)
+	}
+	if cfg.mode == modeMerge {
+		return runMerge(newHandler(ctx, cfg, stdout, stderr, options), cfg)
 	}
 
-	client, err := client.NewClient(cfg.db, nil)
+	logDBHeaders(cfg)
+	var throttled sync.Once
+	client, err := client.NewClient(cfg.db, &client.Options{
+		Retries: cfg.retries,
+		Rate:    cfg.dbRate,
+		OnRetry: func(err error, retry int, delay time.Duration) {
+			fmt.Fprintf(stderr, "govulncheck: warning: %v; retrying in %v (%d/%d)\n", err, delay, retry, cfg.retries)
+		},
+		OnThrottle: func(time.Duration) {
+			throttled.Do(func() {
+				fmt.Fprintf(stderr, "govulncheck: warning: throttling vulnerability database requests to %v per second\n", cfg.dbRate)
+			})
+		},
+		Provenance: cfg.showing(showProvenance),
+		Header:     cfg.headers,
+	})
 	if err != nil {
 		return fmt.Errorf("creating client: %w", err)
 	}
 
+	if cfg.mode == modeSource && isRemoteModule(cfg.patterns) {
+		cleanup, err := fetchModule(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+	}
 	prepareConfig(ctx, cfg, client)
-	var handler govulncheck.Handler
-	switch {
-	case cfg.json:
-		handler = govulncheck.NewJSONHandler(stdout)
-	default:
-		th := NewTextHandler(stdout)
-		th.Show(cfg.show)
-		handler = th
+	if err := checkDBSchema(cfg, stderr); err != nil {
+		return err
+	}
+	if err := checkDBAge(cfg, stderr); err != nil {
+		return err
+	}
+	if cfg.indexOnly {
+		return runIndexOnly(ctx, stdout, cfg, client)
+	}
+	handler := newHandler(ctx, cfg, stdout, stderr, options)
+	if cfg.showing(showProvenance) {
+		handler = &provenanceHandler{Handler: handler, client: client}
 	}
 
 	// Write the introductory message to the user.
@@ -55,7 +146,11 @@ func RunGovulncheck(ctx context.Context, env []string, r io.Reader, stdout io.Wr
 	switch cfg.mode {
 	case modeSource:
 		dir := filepath.FromSlash(cfg.dir)
-		err = runSource(ctx, handler, cfg, client, dir)
+		if len(cfg.modfiles) > 0 {
+			err = runModules(ctx, handler, cfg, client, dir)
+		} else {
+			err = runSource(ctx, handler, cfg, client, dir)
+		}
 	case modeBinary:
 		err = runBinary(ctx, handler, cfg, client)
 	case modeQuery:
@@ -70,9 +165,145 @@ func RunGovulncheck(ctx context.Context, env []string, r io.Reader, stdout io.Wr
 	return nil
 }
 
+// newHandler returns the handler of the output selected by cfg, wrapped
+// in the handlers of the other behavior that cfg and options request.
+func newHandler(ctx context.Context, cfg *config, stdout, stderr io.Writer, options *runOptions) govulncheck.Handler {
+	var handler govulncheck.Handler
+	switch {
+	case cfg.plan:
+		handler = newPlanHandler(stdout)
+	case cfg.applyFixes:
+		handler = newApplyFixesHandler(ctx, stdout, cfg)
+	case cfg.format == formatJSON && cfg.showing(showReachability):
+		handler = govulncheck.NewReachabilityJSONHandler(stdout)
+	case cfg.format == formatJSON:
+		handler = govulncheck.NewJSONHandler(stdout)
+	case cfg.format == formatNDJSON:
+		handler = newNDJSONHandler(stdout)
+	case cfg.format == formatOSV:
+		handler = newOSVHandler(stdout)
+	case cfg.format == formatFix:
+		handler = newFixHandler(stdout)
+	case cfg.format == formatSPDX:
+		handler = newSPDXHandler(stdout)
+	case cfg.format == formatTeamCity:
+		handler = newTeamCityHandler(stdout)
+	case cfg.format == formatCodeClimate:
+		handler = newCodeClimateHandler(stdout, cfg.dir, cfg.missing)
+	case cfg.format == formatOSVScanner:
+		handler = newOSVScannerHandler(stdout, scannedPath(cfg))
+	case cfg.format == formatRSS:
+		handler = newRSSHandler(stdout)
+	case cfg.machineText:
+		handler = newMachineTextHandler(stdout)
+	default:
+		th := NewTextHandler(stdout)
+		th.Show(cfg.show)
+		th.ColorBy(cfg.colorBy)
+		th.Group(cfg.group)
+		th.FooterOnClean(!cfg.noFooter)
+		th.CompactWidth(cfg.compactWidth)
+		th.SortBy(cfg.sortBy)
+		th.Top(cfg.top)
+		th.MaxFindings(cfg.maxFindings)
+		if cfg.showing(showVersionDelta) {
+			th.Versions(newProxyVersions(ctx, cfg).Versions)
+		}
+		th.SplitFixable(cfg.splitFixable)
+		th.NoTraces(cfg.noTraces)
+		th.TraceMarker(cfg.marker)
+		th.MissingFiles(cfg.missing)
+		th.ErrorModules(cfg.errorMods)
+		if len(cfg.modfiles) > 0 {
+			th.Roots(modfileRoots(cfg.modfiles))
+		}
+		th.SeverityOverrides(cfg.overrides)
+		th.Lang(cfg.lang)
+		th.Width(outputWidth(cfg.width, stdout))
+		th.SymbolFormat(cfg.symbolFormat)
+		if cfg.stripANSI {
+			th.StripANSI()
+		}
+		if cfg.indent != "" {
+			th.Indent(indentUnit(cfg.indent))
+		}
+		handler = th
+	}
+	// The output handler reports the counts of -blame, and takes the
+	// summary of JSON input, if it can.
+	blameSummary, _ := handler.(blameSummarizer)
+	inputSummary, _ := handler.(govulncheck.SummaryHandler)
+	output := handler
+	if cfg.overrides != nil {
+		handler = &severityOverrideHandler{Handler: handler, overrides: cfg.overrides}
+	}
+	if cfg.showing(showCWE) {
+		handler = &cweHandler{Handler: handler}
+	}
+	if cfg.calledOnly {
+		handler = newCalledOnlyHandler(handler)
+	}
+	if cfg.redact {
+		handler = newRedactHandler(handler, cfg.redacted)
+	}
+	if cfg.strict {
+		handler = &strictHandler{Handler: handler}
+	}
+	if cfg.metrics != "" || cfg.pushgateway != "" {
+		var push *pushgateway
+		if cfg.pushgateway != "" {
+			push = &pushgateway{url: cfg.pushgateway, job: cfg.pushJob, instance: cfg.pushInstance}
+		}
+		handler = newMetricsHandler(handler, cfg.metrics, push)
+	}
+	if cfg.syslog {
+		handler = newSyslogHandler(handler, cfg.syslogFac, cfg.syslogTag, stderr)
+	}
+	handler = options.wrap(handler)
+	if cfg.minStacks > 1 {
+		// Demote findings before the hooks see them.
+		handler = &minStacksHandler{Handler: handler, min: cfg.minStacks}
+	}
+	if cfg.blame != "" {
+		handler = newBlameHandler(handler, cfg.blame, blameSummary)
+	}
+	if cfg.ignored != nil {
+		// Drop the accepted risks before anything else sees them.
+		handler = newSuppressHandler(handler, cfg.ignored, suppressedMessage)
+	}
+	if cfg.excluded != nil {
+		handler = newSuppressHandler(handler, cfg.excluded, excludedMessage)
+	}
+	if inputSummary != nil && handler != output {
+		handler = &summaryHandler{Handler: handler, output: inputSummary}
+	}
+	return handler
+}
+
+// summaryHandler hands the summary of JSON input past the handlers that
+// wrap the output handler, to the output handler.
+type summaryHandler struct {
+	govulncheck.Handler
+	output govulncheck.SummaryHandler
+}
+
+func (h *summaryHandler) Summary(summary *govulncheck.Summary) error {
+	return h.output.Summary(summary)
+}
+
+func (h *summaryHandler) Flush() error {
+	return Flush(h.Handler)
+}
+
 func prepareConfig(ctx context.Context, cfg *config, client *client.Client) {
 	cfg.ProtocolVersion = govulncheck.ProtocolVersion
 	cfg.DB = cfg.db
+	cfg.BuildTags = cfg.tags
+	cfg.TestOnly = cfg.testOnly
+	if cfg.mode == modeSource {
+		goos, goarch := targetPlatform(cfg)
+		cfg.Platform = goos + "/" + goarch
+	}
 	if cfg.mode == modeSource && cfg.GoVersion == "" {
 		const goverPrefix = "GOVERSION="
 		for _, env := range cfg.env {
@@ -89,9 +320,98 @@ func prepareConfig(ctx context.Context, cfg *config, client *client.Client) {
 	if bi, ok := debug.ReadBuildInfo(); ok {
 		scannerVersion(cfg, bi)
 	}
-	if mod, err := client.LastModifiedTime(ctx); err == nil {
+	if mod, v, err := client.DBInfo(ctx); err == nil {
 		cfg.DBLastModified = &mod
+		cfg.DBSchemaVersion = v
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	cfg.ScanTime = &now
+	if cfg.maxDBAge > 0 && cfg.DBLastModified != nil {
+		cfg.DBAgeSeconds = int64(now.Sub(*cfg.DBLastModified) / time.Second)
+	}
+}
+
+// checkDBSchema verifies that the database follows the schema version
+// requested with -db-schema. Without -db-schema, it only warns if the
+// database follows a schema newer than the supported one.
+func checkDBSchema(cfg *config, stderr io.Writer) error {
+	got, supported := cfg.DBSchemaVersion, client.SupportedSchemaVersion
+	if cfg.dbSchema == 0 {
+		if got > supported {
+			fmt.Fprintf(stderr, "govulncheck: warning: vulnerability database %s follows schema version %d, but only version %d is supported; results may be wrong\n", cfg.db, got, supported)
+		}
+		return nil
+	}
+	if got == 0 {
+		return fmt.Errorf("govulncheck: cannot determine the schema version of vulnerability database %s", cfg.db)
+	}
+	if got != cfg.dbSchema {
+		return fmt.Errorf("govulncheck: vulnerability database %s follows schema version %d, not %d", cfg.db, got, cfg.dbSchema)
+	}
+	return nil
+}
+
+// checkDBAge warns if the database was last modified longer ago than
+// -max-db-age allows, which usually means that a mirror stopped syncing
+// and that recent vulnerabilities are missing. With -strict, it fails
+// instead.
+func checkDBAge(cfg *config, stderr io.Writer) error {
+	if cfg.maxDBAge == 0 {
+		return nil
+	}
+	var msg string
+	if cfg.DBLastModified == nil {
+		msg = fmt.Sprintf("cannot determine when vulnerability database %s was last modified", cfg.db)
+	} else if age := time.Duration(cfg.DBAgeSeconds) * time.Second; age > cfg.maxDBAge {
+		msg = fmt.Sprintf("vulnerability database %s was last modified %s ago, longer than the -max-db-age of %v", cfg.db, formatAge(age), cfg.maxDBAge)
+	} else {
+		return nil
+	}
+	if cfg.strict {
+		return fmt.Errorf("govulncheck: %s", msg)
+	}
+	fmt.Fprintf(stderr, "govulncheck: warning: %s; results may miss recent vulnerabilities\n", msg)
+	return nil
+}
+
+// formatAge returns d in days, if it is at least a day, or else in
+// hours and minutes.
+func formatAge(d time.Duration) string {
+	switch days := int(d / (24 * time.Hour)); {
+	case days == 1:
+		return "1 day"
+	case days > 1:
+		return fmt.Sprintf("%d days", days)
+	}
+	return d.Truncate(time.Minute).String()
+}
+
+// logTiming writes the duration d of the named analysis phase to the
+// -verbose log. It does nothing if -verbose is not set.
+func (cfg *config) logTiming(phase string, d time.Duration) {
+	if cfg.timings != nil {
+		fmt.Fprintf(cfg.timings, "govulncheck: %s took %v\n", phase, d.Round(time.Millisecond))
+	}
+}
+
+// targetPlatform returns the GOOS and GOARCH that source analysis is
+// performed for. These are taken from the -platform flag, or else from
+// the environment of cfg, falling back to the platform govulncheck is
+// running on.
+func targetPlatform(cfg *config) (goos, goarch string) {
+	if goos, goarch, ok := strings.Cut(cfg.platform, "/"); ok {
+		return goos, goarch
+	}
+	goos, goarch = runtime.GOOS, runtime.GOARCH
+	for _, env := range cfg.env {
+		if val := strings.TrimPrefix(env, "GOOS="); val != env && val != "" {
+			goos = val
+		}
+		if val := strings.TrimPrefix(env, "GOARCH="); val != env && val != "" {
+			goarch = val
+		}
 	}
+	return goos, goarch
 }
 
 // scannerVersion reconstructs the current version of
@@ -134,12 +454,12 @@ func scannerVersion(cfg *config, bi *debug.BuildInfo) {
 }
 
 // convertJSONToText converts r, which is expected to be the JSON output of govulncheck,
-// into the text output, and writes the output to w.
-func convertJSONToText(r io.Reader, w io.Writer) error {
-	h := NewTextHandler(w)
+// into the text output, and writes the output to w. The output goes through
+// the same handlers as that of a scan, so it fails the same way.
+func convertJSONToText(ctx context.Context, r io.Reader, w, stderr io.Writer, cfg *config, opts *runOptions) error {
+	h := newHandler(ctx, cfg, w, stderr, opts)
 	if err := govulncheck.HandleJSON(r, h); err != nil {
-		return err
+		return fmt.Errorf("govulncheck: converting JSON input: %v", err)
 	}
-	Flush(h)
-	return nil
+	return Flush(h)
 }
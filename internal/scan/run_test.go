@@ -5,8 +5,17 @@
 package scan
 
 import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
 	"runtime/debug"
+	"strings"
 	"testing"
+	"time"
+
+	"golang.org/x/vuln/internal/govulncheck"
 )
 
 func TestGovulncheckVersion(t *testing.T) {
@@ -24,3 +33,58 @@ func TestGovulncheckVersion(t *testing.T) {
 		t.Errorf("got %s; want %s", got.ScannerVersion, want)
 	}
 }
+
+func TestFindingHook(t *testing.T) {
+	input, err := os.Open(filepath.Join("testdata", "source.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer input.Close()
+	var got []string
+	hook := WithFindingHook(func(f *govulncheck.Finding) {
+		got = append(got, f.OSV)
+	})
+	out := &strings.Builder{}
+	if err := RunGovulncheck(context.Background(), nil, input, out, io.Discard, []string{"-mode=convert"}, hook); err != errVulnerabilitiesFound {
+		t.Fatalf("got error %v; want %v", err, errVulnerabilitiesFound)
+	}
+	want := []string{"GO-0000-0001", "GO-0000-0002"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got findings %v; want %v", got, want)
+	}
+	if !strings.Contains(out.String(), "Vulnerability #1: GO-0000-0001") {
+		t.Errorf("text output missing findings:\n%s", out)
+	}
+}
+
+func TestCheckDBAge(t *testing.T) {
+	modified := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name     string
+		age      time.Duration
+		strict   bool
+		wantErr  bool
+		wantWarn bool
+	}{
+		{"fresh", 12 * time.Hour, false, false, false},
+		{"stale", 72 * time.Hour, false, false, true},
+		{"stale strict", 72 * time.Hour, true, true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config{db: "testdb", maxDBAge: 48 * time.Hour, strict: tc.strict}
+			cfg.DBLastModified = &modified
+			cfg.DBAgeSeconds = int64(tc.age / time.Second)
+			var stderr strings.Builder
+			err := checkDBAge(cfg, &stderr)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("got error %v; want error: %t", err, tc.wantErr)
+			}
+			if got := stderr.Len() > 0; got != tc.wantWarn {
+				t.Errorf("got warning %q; want warning: %t", stderr.String(), tc.wantWarn)
+			}
+			if err != nil && !strings.Contains(err.Error(), "last modified 3 days ago") {
+				t.Errorf("got error %v; want it to give the age of the database", err)
+			}
+		})
+	}
+}
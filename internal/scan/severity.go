@@ -0,0 +1,148 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// severities are the severity ratings of the Go vulnerability database,
+// most severe first.
+var severities = []string{"CRITICAL", "HIGH", "MODERATE", "LOW"}
+
+// readSeverityOverrides reads the severities that replace those of the
+// database, by OSV ID or alias. Each line of file has the form
+// "ID: severity", where severity is one of critical, high, moderate (or
+// medium) and low, in any case. Text after a # is a comment, and blank
+// lines are skipped.
+func readSeverityOverrides(file string) (map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	overrides := map[string]string{}
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		id, severity, ok := strings.Cut(line, ":")
+		id, severity = strings.TrimSpace(id), strings.ToUpper(strings.TrimSpace(severity))
+		if severity == "MEDIUM" {
+			severity = "MODERATE"
+		}
+		if !ok || !isVulnID(id) || !isSeverity(severity) {
+			return nil, fmt.Errorf("%s:%d: %q is not of the form \"ID: severity\", with a severity of %s", file, n, line, strings.ToLower(strings.Join(severities, ", ")))
+		}
+		overrides[id] = severity
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+func isSeverity(s string) bool {
+	return severityRank(s) < len(severities)
+}
+
+// severityRank returns the index of severity in severities, so that more
+// severe ones rank lower, or len(severities) if it is not one of them.
+func severityRank(severity string) int {
+	for i, s := range severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(severities)
+}
+
+// entrySeverity returns the severity of e, one of severities, or "" if
+// the database gives none that is known. MEDIUM is read as MODERATE.
+func entrySeverity(e *osv.Entry) string {
+	if e.DatabaseSpecific == nil {
+		return ""
+	}
+	severity := strings.ToUpper(e.DatabaseSpecific.Severity)
+	if severity == "MEDIUM" {
+		severity = "MODERATE"
+	}
+	if !isSeverity(severity) {
+		return ""
+	}
+	return severity
+}
+
+// highestSeverity returns the most severe of the severities of the
+// vulnerabilities of findings, or "" if none of them has a known one.
+func highestSeverity(findings []*findingSummary) string {
+	highest := ""
+	for _, f := range findings {
+		if s := entrySeverity(f.OSV); severityRank(s) < severityRank(highest) {
+			highest = s
+		}
+	}
+	return highest
+}
+
+// moduleSeverities returns the highest severity of the vulnerabilities
+// of each module of findings, by module path.
+func moduleSeverities(findings []*findingSummary) map[string]string {
+	byModule := map[string]string{}
+	for _, module := range groupByModule(findings) {
+		byModule[module[0].Trace[0].Module] = highestSeverity(module)
+	}
+	return byModule
+}
+
+// overriddenSeverity returns the severity that overrides that of entry,
+// by its ID or one of its aliases, if any.
+func overriddenSeverity(overrides map[string]string, entry *osv.Entry) (string, bool) {
+	if s, ok := overrides[entry.ID]; ok {
+		return s, true
+	}
+	for _, alias := range entry.Aliases {
+		if s, ok := overrides[alias]; ok {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// severityOverrideHandler wraps a handler and replaces the severity of
+// the OSV entries listed in overrides before the wrapped handler sees
+// them, so that coloring, grouping and other formats all use it.
+type severityOverrideHandler struct {
+	govulncheck.Handler
+	overrides map[string]string
+}
+
+func (h *severityOverrideHandler) OSV(entry *osv.Entry) error {
+	if s, ok := overriddenSeverity(h.overrides, entry); ok {
+		// Change a copy, as the entry may be shared with the scan.
+		e := *entry
+		ds := osv.DatabaseSpecific{}
+		if entry.DatabaseSpecific != nil {
+			ds = *entry.DatabaseSpecific
+		}
+		ds.Severity = s
+		e.DatabaseSpecific = &ds
+		entry = &e
+	}
+	return h.Handler.OSV(entry)
+}
+
+func (h *severityOverrideHandler) Flush() error {
+	return Flush(h.Handler)
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/test"
+)
+
+func TestReadSeverityOverrides(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "severity.txt")
+	content := "# Our assessment.\nGO-0000-0001: low\nCVE-0000-0002: Medium # reachable only by admins\n\n"
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readSeverityOverrides(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"GO-0000-0001": "LOW", "CVE-0000-0002": "MODERATE"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+
+	if err := os.WriteFile(file, []byte("GO-0000-0001: low\nGO-0000-0002: urgent\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = readSeverityOverrides(file)
+	if err == nil || !strings.Contains(err.Error(), ":2: ") {
+		t.Errorf("got error %v; want one for line 2", err)
+	}
+}
+
+func TestSeverityOverrideHandler(t *testing.T) {
+	mock := test.NewMockHandler()
+	h := &severityOverrideHandler{Handler: mock, overrides: map[string]string{"CVE-0000-0002": "CRITICAL"}}
+	entry := &osv.Entry{ID: "GO-0000-0002", Aliases: []string{"CVE-0000-0002"}, DatabaseSpecific: &osv.DatabaseSpecific{Severity: "LOW"}}
+	other := &osv.Entry{ID: "GO-0000-0003"}
+	for _, e := range []*osv.Entry{entry, other} {
+		if err := h.OSV(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := mock.OSVMessages[0].DatabaseSpecific.Severity; got != "CRITICAL" {
+		t.Errorf("got severity %q; want CRITICAL", got)
+	}
+	if entry.DatabaseSpecific.Severity != "LOW" {
+		t.Errorf("the original entry was changed")
+	}
+	if mock.OSVMessages[1] != other {
+		t.Errorf("an entry without an override was changed")
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/vuln/internal"
@@ -23,14 +26,34 @@ import (
 // symbol is actually exercised) or just imported by the package
 // (likely having a non-affecting outcome).
 func runSource(ctx context.Context, handler govulncheck.Handler, cfg *config, client *client.Client, dir string) error {
+	if cfg.Module != "" {
+		if err := handler.Progress(remoteModuleProgressMessage(cfg.Module)); err != nil {
+			return err
+		}
+	}
 	var pkgs []*packages.Package
 	graph := vulncheck.NewPackageGraph(cfg.GoVersion)
+	env := cfg.env
+	if cfg.platform != "" {
+		goos, goarch := targetPlatform(cfg)
+		env = append(append([]string(nil), env...), "GOOS="+goos, "GOARCH="+goarch)
+	}
 	pkgConfig := &packages.Config{
 		Dir:   dir,
-		Tests: cfg.test,
-		Env:   cfg.env,
+		Tests: cfg.test || cfg.testOnly,
+		Env:   env,
+	}
+	if len(cfg.changed) > 0 {
+		// The files of packages are needed to find the changed ones.
+		pkgConfig.Mode |= packages.NeedFiles
 	}
+	// Loading can take a while, so let the user know it has started.
+	if err := handler.Progress(&govulncheck.Progress{Message: loadingProgressMessage}); err != nil {
+		return err
+	}
+	start := time.Now()
 	pkgs, err := graph.LoadPackages(pkgConfig, cfg.tags, cfg.patterns)
+	cfg.logTiming("loading packages", time.Since(start))
 	if err != nil {
 		// Try to provide a meaningful and actionable error message.
 		if !fileExists(filepath.Join(dir, "go.mod")) {
@@ -41,19 +64,95 @@ func runSource(ctx context.Context, handler govulncheck.Handler, cfg *config, cl
 		}
 		return fmt.Errorf("govulncheck: loading packages: %w", err)
 	}
+	if len(pkgs) == 0 && cfg.requirePkgs {
+		return fmt.Errorf("govulncheck: %v", errNoPackages)
+	}
+	if cfg.testOnly {
+		pkgs = testPackages(pkgs)
+		handler = &targetHandler{Handler: handler, tag: func(f *govulncheck.Finding) { f.Test = true }, seen: map[string]bool{}}
+	}
+	if len(cfg.changed) > 0 {
+		pkgs = changedPackages(pkgs, cfg.changed, dir)
+		if err := handler.Progress(changedProgressMessage(len(pkgs))); err != nil {
+			return err
+		}
+		if len(pkgs) == 0 {
+			return nil
+		}
+	}
 	if err := handler.Progress(sourceProgressMessage(pkgs)); err != nil {
 		return err
 	}
+	start = time.Now()
 	vr, err := vulncheck.Source(ctx, pkgs, &cfg.Config, client, graph)
 	if err != nil {
 		return err
 	}
 	callStacks := vulncheck.CallStacks(vr)
-	return emitResult(handler, vr, callStacks)
+	cfg.logTiming("fetching vulnerabilities", vr.FetchTime)
+	cfg.logTiming("matching vulnerabilities", time.Since(start)-vr.FetchTime)
+	return emitResult(handler, cfg, vr, callStacks)
+}
+
+// testPackages returns the packages of pkgs that are built for tests: the
+// test variants of packages, which include their _test.go files, and the
+// external test packages. The generated test main packages are left out,
+// as they only call the tests.
+func testPackages(pkgs []*packages.Package) []*packages.Package {
+	var out []*packages.Package
+	for _, pkg := range pkgs {
+		// Such packages have IDs of the form "p [p.test]".
+		if strings.HasSuffix(pkg.ID, ".test]") {
+			out = append(out, pkg)
+		}
+	}
+	return out
+}
+
+// runModules runs runSource in the module of each of the go.mod files
+// given with -modfile, relative to dir, and tags the findings with the
+// directory of the go.mod file.
+func runModules(ctx context.Context, handler govulncheck.Handler, cfg *config, client *client.Client, dir string) error {
+	seen := map[string]bool{}
+	roots := modfileRoots(cfg.modfiles)
+	for i, root := range roots {
+		p := &govulncheck.Progress{Message: fmt.Sprintf(moduleRootProgressMessage, root), Done: i + 1, Total: len(roots)}
+		if err := handler.Progress(p); err != nil {
+			return err
+		}
+		h := &targetHandler{Handler: handler, tag: func(f *govulncheck.Finding) { f.Root = root }, seen: seen}
+		if err := runSource(ctx, h, cfg, client, filepath.Join(dir, root)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func emitResult(handler govulncheck.Handler, vr *vulncheck.Result, callstacks map[*vulncheck.Vuln]vulncheck.CallStack) error {
+// modfileRoots returns the directories of the go.mod files given with
+// -modfile, with which their findings are tagged.
+func modfileRoots(modfiles []string) []string {
+	roots := make([]string, len(modfiles))
+	for i, modfile := range modfiles {
+		roots[i] = filepath.Dir(modfile)
+	}
+	return roots
+}
+
+// Reasons reported for informational findings, that is, findings
+// without a call stack leading to a vulnerable symbol.
+const (
+	reasonNoCallStack = "no call stack found"
+	reasonPlatform    = "not applicable on current platform"
+	reasonScanLevel   = "calls are not analyzed at %s scan level"
+	reasonIndirect    = "not a direct dependency, and -direct-only was given"
+)
+
+func emitResult(handler govulncheck.Handler, cfg *config, vr *vulncheck.Result, callstacks map[*vulncheck.Vuln]vulncheck.CallStack) error {
 	osvs := map[string]*osv.Entry{}
+	var whyChains map[*vulncheck.Vuln][]*packages.Package
+	if cfg.showing(showWhy) {
+		whyChains = vulncheck.ModuleChains(vr)
+	}
 	// first deal with all the affected vulnerabilities
 	emitted := map[string]bool{}
 	seen := map[string]bool{}
@@ -61,22 +160,33 @@ func emitResult(handler govulncheck.Handler, vr *vulncheck.Result, callstacks ma
 		osvs[vv.OSV.ID] = vv.OSV
 		fixed := fixedVersion(vv.ImportSink.Module.Path, vv.OSV.Affected)
 		stack := callstacks[vv]
-		if stack == nil {
+		if stack == nil || !onTargetPlatform(cfg, vv) || outsideDirectDeps(cfg, vv) {
 			continue
 		}
 		emitted[vv.OSV.ID] = true
 		emitFinding(handler, osvs, seen, &govulncheck.Finding{
 			OSV:          vv.OSV.ID,
 			FixedVersion: fixed,
-			Trace:        tracefromEntries(stack),
+			Trace:        withSignature(cfg, tracefromEntries(stack), stack),
+			ScanLevel:    vv.ScanLevel,
+			Depth:        traceDepth(cfg, stack),
+			Why:          packagePaths(whyChains[vv]),
 		})
 	}
+	var importChains map[*vulncheck.Vuln][]*packages.Package
+	if cfg.showing(showImportStacks) {
+		importChains = vulncheck.ImportChains(vr)
+	}
+	var boundaries map[string][]string
+	if cfg.showing(showCgoNotes) {
+		boundaries = boundariesByOSV(vr)
+	}
 	for _, vv := range vr.Vulns {
 		if emitted[vv.OSV.ID] {
 			continue
 		}
 		stacks := callstacks[vv]
-		if len(stacks) != 0 {
+		if len(stacks) != 0 && onTargetPlatform(cfg, vv) && !outsideDirectDeps(cfg, vv) {
 			continue
 		}
 		emitted[vv.OSV.ID] = true
@@ -84,11 +194,191 @@ func emitResult(handler govulncheck.Handler, vr *vulncheck.Result, callstacks ma
 			OSV:          vv.OSV.ID,
 			FixedVersion: fixedVersion(vv.ImportSink.Module.Path, vv.OSV.Affected),
 			Trace:        []*govulncheck.Frame{frameFromPackage(vv.ImportSink)},
+			Reason:       informationalReason(cfg, vv),
+			ScanLevel:    vv.ScanLevel,
+			ImportChain:  moduleChain(importChains[vv]),
+			Boundaries:   boundaries[vv.OSV.ID],
+			Why:          packagePaths(whyChains[vv]),
 		})
 	}
+	if cfg.showing(showConsidered) {
+		// Also hand over the entries that were checked but did not
+		// produce a finding, so that they can be listed.
+		for _, entry := range vr.Considered {
+			if !seen[entry.ID] {
+				seen[entry.ID] = true
+				if err := handler.OSV(entry); err != nil {
+					return err
+				}
+			}
+		}
+	}
 	return nil
 }
 
+// boundariesByOSV returns the boundaries that could hide a call to the
+// vulnerabilities of vr without a call stack, by OSV ID, in the form of
+// Finding.Boundaries.
+func boundariesByOSV(vr *vulncheck.Result) map[string][]string {
+	out := map[string][]string{}
+	seen := map[string]bool{}
+	for vv, bs := range vulncheck.Boundaries(vr) {
+		for _, b := range bs {
+			s := b.Kind + ": " + boundaryFunc(b.Func)
+			if !seen[vv.OSV.ID+" "+s] {
+				seen[vv.OSV.ID+" "+s] = true
+				out[vv.OSV.ID] = append(out[vv.OSV.ID], s)
+			}
+		}
+	}
+	for _, bs := range out {
+		sort.Strings(bs)
+	}
+	return out
+}
+
+// boundaryFunc returns the name of fn qualified by its package path, as
+// in example.com/c._Cfunc_open or example.com/x.T.Add.
+func boundaryFunc(fn *vulncheck.FuncNode) string {
+	name := fn.Name
+	if recv := strings.TrimPrefix(fn.Receiver(), "*"); recv != "" {
+		name = recv + "." + name
+	}
+	return fn.Package.PkgPath + "." + name
+}
+
+// withSignature sets the signature of the vulnerable symbol of trace,
+// the frame of stack, if -show=signatures was given.
+func withSignature(cfg *config, trace []*govulncheck.Frame, stack vulncheck.CallStack) []*govulncheck.Frame {
+	if cfg.showing(showSignatures) {
+		trace[0].Signature = stack[len(stack)-1].Function.Signature
+	}
+	return trace
+}
+
+// traceDepth returns the depth of stack, if -show=depth was given.
+func traceDepth(cfg *config, stack vulncheck.CallStack) int {
+	if !cfg.showing(showDepth) {
+		return 0
+	}
+	return len(stack)
+}
+
+// changedPackages returns the packages of pkgs that contain one of the
+// changed files, or that import, directly or not, a package that does.
+// Relative file names are interpreted relative to dir.
+func changedPackages(pkgs []*packages.Package, changed []string, dir string) []*packages.Package {
+	files := map[string]bool{}
+	for _, f := range changed {
+		if !filepath.IsAbs(f) {
+			f = filepath.Join(dir, f)
+		}
+		if abs, err := filepath.Abs(f); err == nil {
+			files[abs] = true
+		}
+	}
+	// affected memoizes whether a package is affected by the changes.
+	affected := map[*packages.Package]bool{}
+	var visit func(pkg *packages.Package) bool
+	visit = func(pkg *packages.Package) bool {
+		if a, ok := affected[pkg]; ok {
+			return a
+		}
+		affected[pkg] = false // break import cycles
+		a := false
+		for _, f := range pkg.GoFiles {
+			if files[f] {
+				a = true
+				break
+			}
+		}
+		for _, imp := range pkg.Imports {
+			// Visit all imports so that the whole graph is memoized.
+			if visit(imp) {
+				a = true
+			}
+		}
+		affected[pkg] = a
+		return a
+	}
+	var out []*packages.Package
+	for _, pkg := range pkgs {
+		if visit(pkg) {
+			out = append(out, pkg)
+		}
+	}
+	return out
+}
+
+// changedProgressMessage returns the progress message reported after
+// the scan has been restricted to the n packages affected by -changed.
+func changedProgressMessage(n int) *govulncheck.Progress {
+	if n == 0 {
+		return &govulncheck.Progress{Message: "No packages are affected by the changed files, nothing to scan."}
+	}
+	phrase := fmt.Sprintf("%d package", n)
+	if n != 1 {
+		phrase += "s"
+	}
+	return &govulncheck.Progress{Message: fmt.Sprintf("Scanning only the %s affected by the changed files.", phrase)}
+}
+
+// moduleChain returns the modules of the packages in chain, with
+// consecutive packages from the same module collapsed into one entry.
+func moduleChain(chain []*packages.Package) []string {
+	var mods []string
+	for _, pkg := range chain {
+		mod := frameFromPackage(pkg).Module
+		if len(mods) == 0 || mods[len(mods)-1] != mod {
+			mods = append(mods, mod)
+		}
+	}
+	return mods
+}
+
+// packagePaths returns the paths of pkgs.
+func packagePaths(pkgs []*packages.Package) []string {
+	var paths []string
+	for _, pkg := range pkgs {
+		paths = append(paths, pkg.PkgPath)
+	}
+	return paths
+}
+
+// informationalReason returns a short explanation of why vv is
+// reported as informational.
+func informationalReason(cfg *config, vv *vulncheck.Vuln) string {
+	if !onTargetPlatform(cfg, vv) {
+		return reasonPlatform
+	}
+	if outsideDirectDeps(cfg, vv) {
+		return reasonIndirect
+	}
+	if !cfg.ScanLevel.WantSymbols() {
+		return fmt.Sprintf(reasonScanLevel, cfg.ScanLevel)
+	}
+	return reasonNoCallStack
+}
+
+// outsideDirectDeps reports whether -direct-only was given and the module
+// of vv is an indirect dependency of the main module, so that its
+// findings are only informational. The standard library counts as a
+// direct dependency.
+func outsideDirectDeps(cfg *config, vv *vulncheck.Vuln) bool {
+	return cfg.directOnly && vv.ImportSink.Module != nil && vv.ImportSink.Module.Indirect
+}
+
+// onTargetPlatform reports whether vv affects the platform that is
+// analyzed. Vulnerabilities for other platforms are only informational.
+func onTargetPlatform(cfg *config, vv *vulncheck.Vuln) bool {
+	// Binaries are already filtered by the platform they were built for.
+	if cfg.mode != modeSource {
+		return true
+	}
+	goos, goarch := targetPlatform(cfg)
+	return affectsPlatform(vv.ImportSink.Module.Path, vv.OSV, goos, goarch)
+}
+
 func emitFinding(handler govulncheck.Handler, osvs map[string]*osv.Entry, seen map[string]bool, finding *govulncheck.Finding) error {
 	if !seen[finding.OSV] {
 		seen[finding.OSV] = true
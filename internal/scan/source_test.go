@@ -5,10 +5,19 @@
 package scan
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/vuln/internal/client"
 	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/test"
+	"golang.org/x/vuln/internal/testenv"
+	"golang.org/x/vuln/internal/vulncheck"
 )
 
 func TestSummarizeCallStack(t *testing.T) {
@@ -52,13 +61,54 @@ func TestSummarizeCallStack(t *testing.T) {
 		},
 	} {
 		in := stringToFinding(test.in)
-		got := compactTrace(in)
+		got := compactTrace(in, symbolQualified, missingKeep)
 		if got != test.want {
 			t.Errorf("%s:\ngot  %s\nwant %s", test.in, got, test.want)
 		}
 	}
 }
 
+func TestChangedPackages(t *testing.T) {
+	dir := t.TempDir()
+	pkg := func(path string, imports ...*packages.Package) *packages.Package {
+		p := &packages.Package{
+			PkgPath: path,
+			GoFiles: []string{filepath.Join(dir, path, "x.go")},
+			Imports: map[string]*packages.Package{},
+		}
+		for _, imp := range imports {
+			p.Imports[imp.PkgPath] = imp
+		}
+		return p
+	}
+	internal := pkg("internal")
+	a := pkg("a", internal)
+	b := pkg("b", a)
+	c := pkg("c")
+	roots := []*packages.Package{a, b, c}
+	for _, test := range []struct {
+		changed []string
+		want    []*packages.Package
+	}{
+		{[]string{filepath.Join("a", "x.go")}, []*packages.Package{a, b}},
+		// A package that is not a root is changed.
+		{[]string{filepath.Join(dir, "internal", "x.go")}, []*packages.Package{a, b}},
+		{[]string{filepath.Join("c", "x.go"), "README.md"}, []*packages.Package{c}},
+		{[]string{"README.md"}, nil},
+	} {
+		got := changedPackages(roots, test.changed, dir)
+		if len(got) != len(test.want) {
+			t.Errorf("%v: got %d packages; want %d", test.changed, len(got), len(test.want))
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%v: got package %s; want %s", test.changed, got[i].PkgPath, test.want[i].PkgPath)
+			}
+		}
+	}
+}
+
 func stringToFinding(s string) *govulncheck.Finding {
 	f := &govulncheck.Finding{}
 	entries := strings.Fields(s)
@@ -74,3 +124,132 @@ func stringToFinding(s string) *govulncheck.Finding {
 	}
 	return f
 }
+
+func TestTestPackages(t *testing.T) {
+	var pkgs []*packages.Package
+	for _, id := range []string{
+		"example.com/m/p",
+		"example.com/m/p [example.com/m/p.test]",
+		"example.com/m/p_test [example.com/m/p.test]",
+		"example.com/m/p.test",
+	} {
+		pkgs = append(pkgs, &packages.Package{ID: id})
+	}
+	var got []string
+	for _, pkg := range testPackages(pkgs) {
+		got = append(got, pkg.ID)
+	}
+	want := "example.com/m/p [example.com/m/p.test], example.com/m/p_test [example.com/m/p.test]"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("got %v; want %s", got, want)
+	}
+}
+
+func TestDirectOnly(t *testing.T) {
+	vuln := func(id string, mod *packages.Module) *vulncheck.Vuln {
+		pkg := &packages.Package{PkgPath: mod.Path, Module: mod}
+		return &vulncheck.Vuln{
+			OSV:        &osv.Entry{ID: id, Affected: []osv.Affected{{Module: osv.Module{Path: mod.Path}}}},
+			ImportSink: pkg,
+			Symbol:     "F",
+		}
+	}
+	direct := vuln("GO-0000-0001", &packages.Module{Path: "golang.org/direct"})
+	indirect := vuln("GO-0000-0002", &packages.Module{Path: "golang.org/indirect", Indirect: true})
+	vr := &vulncheck.Result{Vulns: []*vulncheck.Vuln{direct, indirect}}
+	stacks := map[*vulncheck.Vuln]vulncheck.CallStack{}
+	for _, vv := range vr.Vulns {
+		stacks[vv] = vulncheck.CallStack{{Function: &vulncheck.FuncNode{Package: vv.ImportSink, Name: "F"}}}
+	}
+	mock := test.NewMockHandler()
+	cfg := &config{mode: modeSource, directOnly: true}
+	if err := emitResult(mock, cfg, vr, stacks); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]*govulncheck.Finding{}
+	for _, f := range mock.FindingMessages {
+		got[f.OSV] = f
+	}
+	if f := got["GO-0000-0001"]; f == nil || f.Trace[0].Function != "F" {
+		t.Errorf("got finding %+v for the direct dependency; want a called one", f)
+	}
+	if f := got["GO-0000-0002"]; f == nil || f.Trace[0].Function != "" || f.Reason != reasonIndirect {
+		t.Errorf("got finding %+v for the indirect dependency; want an informational one", f)
+	}
+}
+
+func TestRunModules(t *testing.T) {
+	testenv.NeedsGoBuild(t)
+
+	// Module a calls the vulnerable archive/zip.OpenReader, and module b
+	// is clean.
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"a/go.mod": "module example.com/a\n\ngo 1.18\n",
+		"a/a.go":   "package main\n\nimport \"archive/zip\"\n\nfunc main() { zip.OpenReader(\"a.zip\") }\n",
+		"b/go.mod": "module example.com/b\n\ngo 1.18\n",
+		"b/b.go":   "package main\n\nfunc main() {}\n",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c, err := client.NewInMemoryClient([]*osv.Entry{{
+		ID: "GO-0000-0001",
+		Affected: []osv.Affected{{
+			Module: osv.Module{Path: "stdlib"},
+			Ranges: []osv.Range{{Type: osv.RangeTypeSemver, Events: []osv.RangeEvent{{Introduced: "0"}}}},
+			EcosystemSpecific: osv.EcosystemSpecific{Packages: []osv.Package{{
+				Path:    "archive/zip",
+				Symbols: []string{"OpenReader"},
+			}}},
+		}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config{
+		Config:   govulncheck.Config{ScanLevel: govulncheck.ScanLevelSymbol, GoVersion: "go1.18"},
+		mode:     modeSource,
+		patterns: []string{"./..."},
+		modfiles: []string{"a/go.mod", "b/go.mod"},
+		env:      os.Environ(),
+	}
+	mock := test.NewMockHandler()
+	if err := runModules(context.Background(), mock, cfg, c, dir); err != nil {
+		t.Fatal(err)
+	}
+	var steps []int
+	for _, p := range mock.ProgressMessages {
+		if p.Total != 0 {
+			if p.Total != 2 {
+				t.Errorf("got progress %+v; want a total of 2 steps", p)
+			}
+			steps = append(steps, p.Done)
+		}
+	}
+	if len(steps) != 2 || steps[0] != 1 || steps[1] != 2 {
+		t.Errorf("got steps %v; want [1 2]", steps)
+	}
+	if len(mock.FindingMessages) != 1 || mock.FindingMessages[0].Root != "a" || mock.FindingMessages[0].Trace[0].Function != "OpenReader" {
+		t.Errorf("got findings %v; want the call of zip.OpenReader in a", mock.FindingMessages)
+	}
+}
+
+func TestRootSummary(t *testing.T) {
+	var buf strings.Builder
+	h := NewTextHandler(&buf)
+	h.Roots([]string{"a", "b"})
+	h.OSV(&osv.Entry{ID: "GO-0000-0001", DatabaseSpecific: &osv.DatabaseSpecific{}})
+	h.Finding(&govulncheck.Finding{OSV: "GO-0000-0001", Root: "a", Trace: []*govulncheck.Frame{{Module: "m", Package: "m/p", Function: "F"}}})
+	h.Flush()
+	for _, want := range []string{"a: 1 vulnerability\n", "b: 0 vulnerabilities\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, buf.String())
+		}
+	}
+}
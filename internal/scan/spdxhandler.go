@@ -0,0 +1,183 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"golang.org/x/vuln/internal"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// spdxHandler writes an SPDX 2.3 document, in JSON, with one package per
+// module that has a finding. Each vulnerability of a module is linked to
+// its package by an external reference to the advisory.
+type spdxHandler struct {
+	w        io.Writer
+	config   *govulncheck.Config
+	osvs     []*osv.Entry
+	findings []*findingSummary
+}
+
+type spdxDocument struct {
+	SPDXVersion       string              `json:"spdxVersion"`
+	DataLicense       string              `json:"dataLicense"`
+	SPDXID            string              `json:"SPDXID"`
+	Name              string              `json:"name"`
+	DocumentNamespace string              `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo    `json:"creationInfo"`
+	Packages          []*spdxPackage      `json:"packages"`
+	Relationships     []*spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string             `json:"SPDXID"`
+	Name             string             `json:"name"`
+	VersionInfo      string             `json:"versionInfo,omitempty"`
+	DownloadLocation string             `json:"downloadLocation"`
+	Comment          string             `json:"comment,omitempty"`
+	ExternalRefs     []*spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+	Comment           string `json:"comment,omitempty"`
+}
+
+type spdxRelationship struct {
+	Element string `json:"spdxElementId"`
+	Type    string `json:"relationshipType"`
+	Related string `json:"relatedSpdxElement"`
+}
+
+// newSPDXHandler returns a handler that writes an SPDX document to w.
+func newSPDXHandler(w io.Writer) *spdxHandler {
+	return &spdxHandler{w: w}
+}
+
+// Config records the scanner and the time of the scan, which describe
+// the document.
+func (h *spdxHandler) Config(config *govulncheck.Config) error {
+	h.config = config
+	return nil
+}
+
+func (h *spdxHandler) Progress(progress *govulncheck.Progress) error {
+	return nil
+}
+
+// OSV gathers osv entries to be referenced.
+func (h *spdxHandler) OSV(entry *osv.Entry) error {
+	h.osvs = append(h.osvs, entry)
+	return nil
+}
+
+// Finding gathers vulnerability findings to be written.
+func (h *spdxHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	h.findings = append(h.findings, newFindingSummary(finding))
+	return nil
+}
+
+// Flush writes the document, with the packages in module path order.
+func (h *spdxHandler) Flush() error {
+	fixupFindings(h.osvs, h.findings)
+	doc := &spdxDocument{
+		SPDXVersion: "SPDX-2.3",
+		DataLicense: "CC0-1.0",
+		SPDXID:      "SPDXRef-DOCUMENT",
+		Name:        "govulncheck",
+		CreationInfo: spdxCreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: govulncheck"},
+		},
+		Packages:      []*spdxPackage{},
+		Relationships: []*spdxRelationship{},
+	}
+	if h.config != nil {
+		if h.config.ScanTime != nil {
+			doc.CreationInfo.Created = h.config.ScanTime.UTC().Format(time.RFC3339)
+		}
+		if h.config.ScannerVersion != "" {
+			doc.CreationInfo.Creators[0] += "-" + h.config.ScannerVersion
+		}
+	}
+	for i, module := range groupByModule(h.findings) {
+		pkg := spdxModule(module)
+		pkg.SPDXID = fmt.Sprintf("SPDXRef-Package-%d", i+1)
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, &spdxRelationship{
+			Element: doc.SPDXID,
+			Type:    "DESCRIBES",
+			Related: pkg.SPDXID,
+		})
+	}
+	// The namespace must be unique to the document, so derive it from
+	// its contents.
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	doc.DocumentNamespace = fmt.Sprintf("https://pkg.go.dev/golang.org/x/vuln/spdx/%x", sha256.Sum256(b))
+	b, err = json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = h.w.Write(append(b, '\n'))
+	return err
+}
+
+// spdxModule returns the package for the module of findings, which all
+// belong to the same module, with one advisory reference per
+// vulnerability, sorted by ID. The comment of the package gives the
+// highest severity of its vulnerabilities, if any is known.
+func spdxModule(findings []*findingSummary) *spdxPackage {
+	mod := findings[0].Trace[0].Module
+	version := findings[0].Trace[0].Version
+	if version != "" && (mod == internal.GoStdModulePath || mod == internal.GoCmdModulePath) {
+		version = semverToGoTag(version)
+	}
+	pkg := &spdxPackage{
+		Name:             mod,
+		VersionInfo:      version,
+		DownloadLocation: "NOASSERTION",
+	}
+	if severity := highestSeverity(findings); severity != "" {
+		pkg.Comment = "Highest severity: " + severity
+	}
+	for _, vuln := range groupByVuln(findings) {
+		entry := vuln[0].OSV
+		status := "informational"
+		if isCalled(vuln) {
+			status = "called"
+		}
+		pkg.ExternalRefs = append(pkg.ExternalRefs, &spdxExternalRef{
+			ReferenceCategory: "SECURITY",
+			ReferenceType:     "advisory",
+			ReferenceLocator:  entry.DatabaseSpecific.URL,
+			Comment:           fmt.Sprintf("%s (%s)", entry.ID, status),
+		})
+	}
+	// groupByVuln sorts in reverse ID order.
+	sort.Slice(pkg.ExternalRefs, func(i, j int) bool {
+		return pkg.ExternalRefs[i].Comment < pkg.ExternalRefs[j].Comment
+	})
+	return pkg
+}
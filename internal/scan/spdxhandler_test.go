@@ -0,0 +1,119 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestSPDXHandler(t *testing.T) {
+	scanTime := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	entry := func(id string) *osv.Entry {
+		return &osv.Entry{ID: id, DatabaseSpecific: &osv.DatabaseSpecific{URL: "https://pkg.go.dev/vuln/" + id}}
+	}
+	frame := func(mod, version, fn string) []*govulncheck.Frame {
+		return []*govulncheck.Frame{{Module: mod, Version: version, Package: mod, Function: fn}}
+	}
+	write := func() string {
+		var buf strings.Builder
+		h := newSPDXHandler(&buf)
+		h.Config(&govulncheck.Config{ScannerVersion: "v1.0.0", ScanTime: &scanTime})
+		for _, id := range []string{"GO-0000-0001", "GO-0000-0002", "GO-0000-0003"} {
+			h.OSV(entry(id))
+		}
+		for _, f := range []*govulncheck.Finding{
+			{OSV: "GO-0000-0002", Trace: frame("golang.org/b", "v1.0.0", "")},
+			{OSV: "GO-0000-0001", Trace: frame("golang.org/b", "v1.0.0", "F")},
+			{OSV: "GO-0000-0003", Trace: frame("stdlib", "v1.20.1", "G")},
+		} {
+			if err := h.Finding(f); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := h.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	out := write()
+	if again := write(); again != out {
+		t.Errorf("output is not deterministic:\n%s\n%s", out, again)
+	}
+	var doc spdxDocument
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := doc.CreationInfo, (spdxCreationInfo{
+		Created:  "2023-06-01T12:00:00Z",
+		Creators: []string{"Tool: govulncheck-v1.0.0"},
+	}); !cmp.Equal(got, want) {
+		t.Errorf("creation info: got %+v, want %+v", got, want)
+	}
+	ref := func(id, status string) *spdxExternalRef {
+		return &spdxExternalRef{
+			ReferenceCategory: "SECURITY",
+			ReferenceType:     "advisory",
+			ReferenceLocator:  "https://pkg.go.dev/vuln/" + id,
+			Comment:           id + " (" + status + ")",
+		}
+	}
+	wantPkgs := []*spdxPackage{
+		{
+			SPDXID:           "SPDXRef-Package-1",
+			Name:             "golang.org/b",
+			VersionInfo:      "v1.0.0",
+			DownloadLocation: "NOASSERTION",
+			ExternalRefs:     []*spdxExternalRef{ref("GO-0000-0001", "called"), ref("GO-0000-0002", "informational")},
+		},
+		{
+			SPDXID:           "SPDXRef-Package-2",
+			Name:             "stdlib",
+			VersionInfo:      "go1.20.1",
+			DownloadLocation: "NOASSERTION",
+			ExternalRefs:     []*spdxExternalRef{ref("GO-0000-0003", "called")},
+		},
+	}
+	if diff := cmp.Diff(wantPkgs, doc.Packages); diff != "" {
+		t.Errorf("packages mismatch (-want, +got):\n%s", diff)
+	}
+	if len(doc.Relationships) != 2 || doc.Relationships[1].Related != "SPDXRef-Package-2" {
+		t.Errorf("got relationships %+v; want one DESCRIBES per package", doc.Relationships)
+	}
+}
+
+func TestSPDXModuleSeverity(t *testing.T) {
+	module := func(severities ...string) []*findingSummary {
+		var findings []*findingSummary
+		for i, severity := range severities {
+			f := newFindingSummary(&govulncheck.Finding{
+				OSV:   fmt.Sprintf("GO-0000-000%d", i+1),
+				Trace: []*govulncheck.Frame{{Module: "golang.org/a", Package: "golang.org/a"}},
+			})
+			f.OSV = &osv.Entry{ID: f.Finding.OSV, DatabaseSpecific: &osv.DatabaseSpecific{Severity: severity}}
+			findings = append(findings, f)
+		}
+		return findings
+	}
+	for _, tc := range []struct {
+		severities []string
+		want       string
+	}{
+		{[]string{"LOW", "CRITICAL", "MODERATE"}, "Highest severity: CRITICAL"},
+		{[]string{"", "Medium"}, "Highest severity: MODERATE"},
+		{[]string{""}, ""},
+	} {
+		if got := spdxModule(module(tc.severities...)).Comment; got != tc.want {
+			t.Errorf("severities %q: got comment %q, want %q", tc.severities, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// strictHandler wraps a handler and records data-quality issues in the
+// OSV entries passed through it. The issues are reported as an error
+// once the wrapped handler has been flushed, so the full output is still
+// written.
+type strictHandler struct {
+	govulncheck.Handler
+	issues []string
+}
+
+// OSV checks entry for data-quality issues before handing it on.
+func (h *strictHandler) OSV(entry *osv.Entry) error {
+	if entry.Summary == "" && entry.Details == "" {
+		h.issues = append(h.issues, fmt.Sprintf("%s: entry has neither a summary nor details", entry.ID))
+	}
+	return h.Handler.OSV(entry)
+}
+
+func (h *strictHandler) Flush() error {
+	err := Flush(h.Handler)
+	if len(h.issues) > 0 {
+		return fmt.Errorf("govulncheck: vulnerability data failed -strict checks:\n\t%s", strings.Join(h.issues, "\n\t"))
+	}
+	return err
+}
@@ -0,0 +1,38 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"testing"
+
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/test"
+)
+
+func TestStrictHandler(t *testing.T) {
+	mock := test.NewMockHandler()
+	h := &strictHandler{Handler: mock}
+	entries := []*osv.Entry{
+		{ID: "GO-0000-0001", Summary: "summary only"},
+		{ID: "GO-0000-0002", Details: "details only"},
+		{ID: "GO-0000-0003"},
+	}
+	for _, e := range entries {
+		if err := h.OSV(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(mock.OSVMessages) != len(entries) {
+		t.Errorf("got %d entries passed through; want %d", len(mock.OSVMessages), len(entries))
+	}
+	err := h.Flush()
+	if err == nil {
+		t.Fatal("want error for entry with neither summary nor details")
+	}
+	want := "govulncheck: vulnerability data failed -strict checks:\n\tGO-0000-0003: entry has neither a summary nor details"
+	if got := err.Error(); got != want {
+		t.Errorf("got error %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// suppressions are the vulnerabilities and modules whose findings are
+// accepted risks, and left out of the output.
+type suppressions struct {
+	ids     map[string]bool // OSV IDs and aliases
+	modules []string        // module path patterns
+}
+
+// isVulnID reports whether s is a vulnerability ID rather than a module
+// path pattern.
+func isVulnID(s string) bool {
+	for _, prefix := range []string{"GO-", "CVE-", "GHSA-"} {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// add adds entry, a vulnerability ID or a module path pattern, to s.
+func (s *suppressions) add(entry string) {
+	if isVulnID(entry) {
+		if s.ids == nil {
+			s.ids = map[string]bool{}
+		}
+		s.ids[entry] = true
+		return
+	}
+	s.modules = append(s.modules, entry)
+}
+
+// readIgnoreFile reads the suppressions listed in file. Each line has
+// the form "ignore: entry", where entry is an OSV ID or alias, such as
+// GO-2021-0113 or CVE-2021-38561, or a module path pattern, such as
+// example.com/legacy/*. Text after a # is a comment, and blank lines are
+// skipped.
+func readIgnoreFile(file string) (*suppressions, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s := &suppressions{}
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		ok := strings.HasPrefix(line, "ignore:")
+		entry := strings.TrimSpace(strings.TrimPrefix(line, "ignore:"))
+		if !ok || entry == "" || strings.ContainsAny(entry, " \t") {
+			return nil, fmt.Errorf("%s:%d: %q is not of the form \"ignore: ID-or-module\"", file, n, line)
+		}
+		s.add(entry)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// matchesOSV reports whether entry is suppressed by ID or alias.
+func (s *suppressions) matchesOSV(entry *osv.Entry) bool {
+	if s.ids[entry.ID] {
+		return true
+	}
+	for _, alias := range entry.Aliases {
+		if s.ids[alias] {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesModule reports whether the module path matches one of the
+// module patterns of s. A * matches any string, and a trailing /* also
+// matches the module path before it.
+func (s *suppressions) matchesModule(module string) bool {
+	for _, pattern := range s.modules {
+		if matchPattern(strings.ReplaceAll(pattern, "*", "..."), module) {
+			return true
+		}
+	}
+	return false
+}
+
+// suppressedMessage and excludedMessage report the vulnerabilities whose
+// findings were dropped because of the ignore file and of -exclude.
+const (
+	suppressedMessage = `Ignored the findings of %d %s as listed in the ignore file: %s.`
+	excludedMessage   = `Excluded %d %s with -exclude: %s.`
+)
+
+// suppressHandler wraps a handler and drops the findings that match the
+// suppressions. Before flushing the wrapped handler, it reports the
+// vulnerabilities whose findings were dropped, so that accepted risks
+// stay visible.
+type suppressHandler struct {
+	govulncheck.Handler
+	s          *suppressions
+	message    string // format of the report of the suppressed vulnerabilities
+	osvs       map[string]*osv.Entry
+	suppressed map[string]bool
+}
+
+// newSuppressHandler returns a handler that drops the findings matching s
+// and reports them with message, which is suppressedMessage or
+// excludedMessage.
+func newSuppressHandler(h govulncheck.Handler, s *suppressions, message string) *suppressHandler {
+	return &suppressHandler{Handler: h, s: s, message: message, osvs: map[string]*osv.Entry{}, suppressed: map[string]bool{}}
+}
+
+func (h *suppressHandler) OSV(entry *osv.Entry) error {
+	h.osvs[entry.ID] = entry
+	return h.Handler.OSV(entry)
+}
+
+func (h *suppressHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	entry := h.osvs[finding.OSV]
+	if (entry != nil && h.s.matchesOSV(entry)) || h.s.ids[finding.OSV] || h.s.matchesModule(finding.Trace[0].Module) {
+		h.suppressed[finding.OSV] = true
+		return nil
+	}
+	return h.Handler.Finding(finding)
+}
+
+// Flush reports the suppressed vulnerabilities, if any, and flushes the
+// wrapped handler.
+func (h *suppressHandler) Flush() error {
+	if len(h.suppressed) > 0 {
+		var ids []string
+		for id := range h.suppressed {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		msg := fmt.Sprintf(h.message, len(ids), choose(len(ids) == 1, "vulnerability", "vulnerabilities"), strings.Join(ids, ", "))
+		if err := h.Handler.Progress(&govulncheck.Progress{Message: msg}); err != nil {
+			return err
+		}
+	}
+	return Flush(h.Handler)
+}
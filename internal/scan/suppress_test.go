@@ -0,0 +1,63 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/test"
+)
+
+func TestReadIgnoreFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ignore")
+	os.WriteFile(file, []byte("# accepted risks\n\nignore: GO-0000-0001\nignore: CVE-0000-0002 # by alias\nignore: example.com/legacy/*\n"), 0o644)
+	s, err := readIgnoreFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.ids["GO-0000-0001"] || !s.ids["CVE-0000-0002"] || len(s.modules) != 1 {
+		t.Errorf("got %+v", s)
+	}
+
+	os.WriteFile(file, []byte("ignore: GO-0000-0001\nGO-0000-0002\n"), 0o644)
+	_, err = readIgnoreFile(file)
+	if err == nil || !strings.Contains(err.Error(), ":2: ") {
+		t.Errorf("got error %v; want one for line 2", err)
+	}
+}
+
+func TestSuppressHandler(t *testing.T) {
+	mock := test.NewMockHandler()
+	s := &suppressions{}
+	for _, e := range []string{"GO-0000-0001", "CVE-0000-0002", "example.com/legacy/*"} {
+		s.add(e)
+	}
+	h := newSuppressHandler(mock, s, suppressedMessage)
+	entries := append(testEntries(), &osv.Entry{ID: "GO-0000-0003"})
+	entries[1].Aliases = []string{"CVE-0000-0002"}
+	finding := func(mod string) *govulncheck.Finding {
+		return &govulncheck.Finding{OSV: "GO-0000-0003", Trace: []*govulncheck.Frame{{Module: mod, Package: mod, Function: "F"}}}
+	}
+	findings := append(testFindings(),
+		finding("example.com/legacy"),
+		finding("example.com/legacy/v2"),
+		finding("example.com/legacyx"),
+	)
+	if err := runHandler(t, h, entries, findings); err != nil {
+		t.Fatal(err)
+	}
+	if len(mock.FindingMessages) != 1 || mock.FindingMessages[0].Trace[0].Module != "example.com/legacyx" {
+		t.Errorf("got findings %v; want only the one in example.com/legacyx", mock.FindingMessages)
+	}
+	want := "Ignored the findings of 3 vulnerabilities as listed in the ignore file: GO-0000-0001, GO-0000-0002, GO-0000-0003."
+	if len(mock.ProgressMessages) != 1 || mock.ProgressMessages[0].Message != want {
+		t.Errorf("got progress %v; want %q", mock.ProgressMessages, want)
+	}
+}
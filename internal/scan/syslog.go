@@ -0,0 +1,130 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// syslogFacilities are the syslog facilities that -syslog-facility
+// accepts, with their codes.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// facilityNames returns the names of the syslog facilities, sorted.
+func facilityNames() string {
+	var names []string
+	for name := range syslogFacilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// syslogWriter is the part of a connection to the syslog daemon that is
+// used to log findings.
+type syslogWriter interface {
+	Warning(m string) error
+	Close() error
+}
+
+// syslogHandler wraps a handler and, once the wrapped handler has been
+// flushed, logs a line for each called vulnerability to the local
+// syslog daemon. Failing to log is reported as a warning, and does not
+// change the result of the scan.
+type syslogHandler struct {
+	govulncheck.Handler
+	dial     func() (syslogWriter, error)
+	stderr   io.Writer
+	osvs     []*osv.Entry
+	findings []*findingSummary
+}
+
+// newSyslogHandler returns a handler that passes everything on to h and
+// logs the called vulnerabilities with the given facility and tag.
+func newSyslogHandler(h govulncheck.Handler, facility, tag string, stderr io.Writer) *syslogHandler {
+	return &syslogHandler{
+		Handler: h,
+		dial:    func() (syslogWriter, error) { return dialSyslog(syslogFacilities[facility], tag) },
+		stderr:  stderr,
+	}
+}
+
+func (h *syslogHandler) OSV(entry *osv.Entry) error {
+	h.osvs = append(h.osvs, entry)
+	return h.Handler.OSV(entry)
+}
+
+func (h *syslogHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	if finding.Trace[0].Function != "" {
+		h.findings = append(h.findings, newFindingSummary(finding))
+	}
+	return h.Handler.Finding(finding)
+}
+
+// Flush flushes the wrapped handler and then logs the called
+// vulnerabilities.
+func (h *syslogHandler) Flush() error {
+	err := Flush(h.Handler)
+	if lerr := h.log(); lerr != nil {
+		fmt.Fprintf(h.stderr, "govulncheck: warning: logging to syslog: %v\n", lerr)
+	}
+	return err
+}
+
+func (h *syslogHandler) log() error {
+	fixupFindings(h.osvs, h.findings)
+	byVuln := groupByVuln(h.findings)
+	if len(byVuln) == 0 {
+		return nil
+	}
+	w, err := h.dial()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	for _, findings := range byVuln {
+		if err := w.Warning(syslogLine(findings)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syslogLine returns the line logged for the called vulnerability of
+// findings, as key=value pairs, with its first compact trace.
+func syslogLine(findings []*findingSummary) string {
+	f := findings[0]
+	frame := f.Trace[0]
+	var b strings.Builder
+	fmt.Fprintf(&b, "vuln=%s module=%s", f.OSV.ID, frame.Module)
+	if frame.Version != "" {
+		fmt.Fprintf(&b, " version=%s", frame.Version)
+	}
+	if f.FixedVersion != "" {
+		fmt.Fprintf(&b, " fixed=%s", f.FixedVersion)
+	}
+	for _, f := range findings {
+		if f.Compact != "" {
+			fmt.Fprintf(&b, " trace=%s", strconv.Quote(f.Compact))
+			break
+		}
+	}
+	return b.String()
+}
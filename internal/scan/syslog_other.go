@@ -0,0 +1,19 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build windows || plan9
+// +build windows plan9
+
+package scan
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// dialSyslog reports that there is no syslog daemon to log to on
+// Windows and Plan 9.
+func dialSyslog(facility int, tag string) (syslogWriter, error) {
+	return nil, fmt.Errorf("syslog is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+type fakeSyslog struct {
+	lines []string
+	err   error
+}
+
+func (s *fakeSyslog) Warning(m string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.lines = append(s.lines, m)
+	return nil
+}
+
+func (s *fakeSyslog) Close() error { return nil }
+
+func TestSyslogHandler(t *testing.T) {
+	findings := []*govulncheck.Finding{{
+		OSV:          "GO-0000-0001",
+		FixedVersion: "v1.0.1",
+		Trace: []*govulncheck.Frame{
+			{Module: "golang.org/a", Version: "v1.0.0", Package: "golang.org/a", Function: "F"},
+			{Module: "golang.org/main", Package: "golang.org/main", Function: "main"},
+		},
+	}, {
+		// Informational findings are not logged.
+		OSV:   "GO-0000-0002",
+		Trace: []*govulncheck.Frame{{Module: "golang.org/b", Version: "v1.0.0"}},
+	}}
+	run := func(t *testing.T, w *fakeSyslog) (*strings.Builder, error) {
+		var stderr strings.Builder
+		h := newSyslogHandler(govulncheck.NewJSONHandler(&strings.Builder{}), "user", "govulncheck", &stderr)
+		h.dial = func() (syslogWriter, error) { return w, nil }
+		for _, id := range []string{"GO-0000-0001", "GO-0000-0002"} {
+			if err := h.OSV(&osv.Entry{ID: id}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, f := range findings {
+			if err := h.Finding(f); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return &stderr, h.Flush()
+	}
+
+	t.Run("log", func(t *testing.T) {
+		w := &fakeSyslog{}
+		if _, err := run(t, w); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{`vuln=GO-0000-0001 module=golang.org/a version=v1.0.0 fixed=v1.0.1 trace="main.main calls a.F"`}
+		if strings.Join(w.lines, "\n") != strings.Join(want, "\n") {
+			t.Errorf("got %q, want %q", w.lines, want)
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		stderr, err := run(t, &fakeSyslog{err: errors.New("connection refused")})
+		if err != nil {
+			t.Fatalf("got %v, want no error", err)
+		}
+		if want := "govulncheck: warning: logging to syslog: connection refused\n"; stderr.String() != want {
+			t.Errorf("got %q, want %q", stderr.String(), want)
+		}
+	})
+}
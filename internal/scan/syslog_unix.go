@@ -0,0 +1,16 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package scan
+
+import "log/syslog"
+
+// dialSyslog connects to the local syslog daemon, to log with the given
+// facility code and tag.
+func dialSyslog(facility int, tag string) (syslogWriter, error) {
+	return syslog.New(syslog.Priority(facility<<3)|syslog.LOG_WARNING, tag)
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// targetHandler wraps the handler of a scan of several targets, such as
+// the binaries of an archive or the modules given with -modfile, for the
+// scan of one of them. It tags the findings with the target, and drops
+// the OSV entries that the scans of the previous targets have already
+// handed on.
+type targetHandler struct {
+	govulncheck.Handler
+	tag  func(*govulncheck.Finding)
+	seen map[string]bool // shared by the scans of all targets
+}
+
+func (h *targetHandler) OSV(entry *osv.Entry) error {
+	if h.seen[entry.ID] {
+		return nil
+	}
+	h.seen[entry.ID] = true
+	return h.Handler.OSV(entry)
+}
+
+func (h *targetHandler) Finding(finding *govulncheck.Finding) error {
+	h.tag(finding)
+	return h.Handler.Finding(finding)
+}
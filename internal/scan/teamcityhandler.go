@@ -0,0 +1,170 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// teamcitySuite is the name of the test suite that holds the findings.
+const teamcitySuite = "govulncheck"
+
+// teamcityHandler writes findings as TeamCity service messages. Each
+// called vulnerability is reported as a failed test named after its ID,
+// and each informational one as an inspection.
+type teamcityHandler struct {
+	w        io.Writer
+	osvs     []*osv.Entry
+	findings []*findingSummary
+	err      error
+}
+
+// newTeamCityHandler returns a handler that writes service messages to w.
+func newTeamCityHandler(w io.Writer) *teamcityHandler {
+	return &teamcityHandler{w: w}
+}
+
+func (h *teamcityHandler) Config(config *govulncheck.Config) error {
+	return nil
+}
+
+func (h *teamcityHandler) Progress(progress *govulncheck.Progress) error {
+	return nil
+}
+
+// OSV gathers osv entries to be reported.
+func (h *teamcityHandler) OSV(entry *osv.Entry) error {
+	h.osvs = append(h.osvs, entry)
+	return nil
+}
+
+// Finding gathers vulnerability findings to be reported.
+func (h *teamcityHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	h.findings = append(h.findings, newFindingSummary(finding))
+	return nil
+}
+
+// Flush writes the messages, in the order of the text output.
+func (h *teamcityHandler) Flush() error {
+	fixupFindings(h.osvs, h.findings)
+	byVuln := groupByVuln(h.findings)
+	h.message("testSuiteStarted", "name", teamcitySuite)
+	for _, findings := range byVuln {
+		if isCalled(findings) {
+			h.test(findings)
+		}
+	}
+	h.message("testSuiteFinished", "name", teamcitySuite)
+	typed := false
+	for _, findings := range byVuln {
+		if isCalled(findings) {
+			continue
+		}
+		if !typed {
+			typed = true
+			h.message("inspectionType",
+				"id", "govulncheck-informational",
+				"name", "Informational vulnerability",
+				"category", "Vulnerabilities",
+				"description", "A vulnerability in an imported package, with no call stacks leading to it")
+		}
+		h.inspection(findings)
+	}
+	return h.err
+}
+
+// test writes a failed test for the called vulnerability of findings.
+func (h *teamcityHandler) test(findings []*findingSummary) {
+	entry := findings[0].OSV
+	var traces []string
+	for _, f := range findings {
+		if f.Compact != "" {
+			traces = append(traces, f.Compact)
+		}
+	}
+	message := entry.ID
+	if len(traces) > 0 {
+		message = traces[0]
+	}
+	details := strings.Join(append(traces, "More info: "+entry.DatabaseSpecific.URL), "\n")
+	h.message("testStarted", "name", entry.ID)
+	h.message("testFailed", "name", entry.ID, "message", message, "details", details)
+	h.message("testFinished", "name", entry.ID)
+}
+
+// inspection writes an inspection for the informational vulnerability
+// of findings, attached to the module it was found in.
+func (h *teamcityHandler) inspection(findings []*findingSummary) {
+	entry := findings[0].OSV
+	description := entry.Summary
+	if description == "" {
+		description = entry.Details
+	}
+	h.message("inspection",
+		"typeId", "govulncheck-informational",
+		"message", fmt.Sprintf("%s: %s (%s)", entry.ID, description, entry.DatabaseSpecific.URL),
+		"file", findings[0].Trace[0].Module,
+		"SEVERITY", "INFO")
+}
+
+// message writes a service message with the given attribute names and
+// values, which alternate in attrs.
+func (h *teamcityHandler) message(name string, attrs ...string) {
+	if h.err != nil {
+		return
+	}
+	var b strings.Builder
+	b.WriteString("##teamcity[")
+	b.WriteString(name)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		fmt.Fprintf(&b, " %s='%s'", attrs[i], teamcityEscape(attrs[i+1]))
+	}
+	b.WriteString("]\n")
+	_, h.err = io.WriteString(h.w, b.String())
+}
+
+// teamcityEscape escapes s for use as an attribute value of a service
+// message, as described at
+// https://www.jetbrains.com/help/teamcity/service-messages.html#Escaped+Values.
+func teamcityEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '|':
+			b.WriteString("||")
+		case '\'':
+			b.WriteString("|'")
+		case '\n':
+			b.WriteString("|n")
+		case '\r':
+			b.WriteString("|r")
+		case '[':
+			b.WriteString("|[")
+		case ']':
+			b.WriteString("|]")
+		case '\u0085':
+			b.WriteString("|x")
+		case '\u2028':
+			b.WriteString("|l")
+		case '\u2029':
+			b.WriteString("|p")
+		default:
+			if r > 0x7f {
+				fmt.Fprintf(&b, "|0x%04x", r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTeamCityHandler(t *testing.T) {
+	var buf strings.Builder
+	h := newTeamCityHandler(&buf)
+	entries := testEntries()
+	entries[1].Summary = "Crash in [parser]"
+	if err := runHandler(t, h, entries, testFindings()); err != nil {
+		t.Fatal(err)
+	}
+	want := `##teamcity[testSuiteStarted name='govulncheck']
+##teamcity[testStarted name='GO-0000-0001']
+##teamcity[testFailed name='GO-0000-0001' message='main.main calls vmod.Vuln' details='main.main calls vmod.Vuln|nMore info: https://pkg.go.dev/vuln/GO-0000-0001']
+##teamcity[testFinished name='GO-0000-0001']
+##teamcity[testSuiteFinished name='govulncheck']
+##teamcity[inspectionType id='govulncheck-informational' name='Informational vulnerability' category='Vulnerabilities' description='A vulnerability in an imported package, with no call stacks leading to it']
+##teamcity[inspection typeId='govulncheck-informational' message='GO-0000-0002: Crash in |[parser|] (https://pkg.go.dev/vuln/GO-0000-0002)' file='golang.org/vmod' SEVERITY='INFO']
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTeamCityEscape(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{"it's [x] | y", "it|'s |[x|] || y"},
+		{"a\nb\rc", "a|nb|rc"},
+		{"\u0085\u2028\u2029", "|x|l|p"},
+		{"café", "caf|0x00e9"},
+	} {
+		if got := teamcityEscape(tc.in); got != tc.want {
+			t.Errorf("teamcityEscape(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
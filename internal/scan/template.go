@@ -5,8 +5,11 @@
 package scan
 
 import (
+	"errors"
 	"go/token"
 	"io"
+	"io/fs"
+	"os"
 	"path"
 	"sort"
 	"strconv"
@@ -37,6 +40,26 @@ func fixupFindings(osvs []*osv.Entry, findings []*findingSummary) {
 	}
 }
 
+// withOSV returns the findings whose OSV entry is in osvs, and the sorted
+// IDs of the entries that are missing for the others.
+func withOSV(osvs []*osv.Entry, findings []*findingSummary) ([]*findingSummary, []string) {
+	ids := map[string]bool{}
+	for _, entry := range osvs {
+		ids[entry.ID] = true
+	}
+	var known []*findingSummary
+	var missing []string
+	for _, f := range findings {
+		if ids[f.Finding.OSV] {
+			known = append(known, f)
+		} else {
+			missing = appendUnique(missing, f.Finding.OSV)
+		}
+	}
+	sort.Strings(missing)
+	return known, missing
+}
+
 func groupByVuln(findings []*findingSummary) [][]*findingSummary {
 	return groupBy(findings, func(left, right *findingSummary) int {
 		return -strings.Compare(left.OSV.ID, right.OSV.ID)
@@ -74,6 +97,9 @@ func groupBy(findings []*findingSummary, compare func(left, right *findingSummar
 	return result
 }
 
+// counters counts the vulnerabilities called in findings, and their
+// modules. Findings are counted by the ID of their vulnerability, so
+// those whose OSV entry was not reported count too.
 func counters(findings []*findingSummary) summaryCounters {
 	vulns := map[string]struct{}{}
 	modules := map[string]struct{}{}
@@ -81,8 +107,7 @@ func counters(findings []*findingSummary) summaryCounters {
 		if f.Trace[0].Function == "" {
 			continue
 		}
-		id := f.OSV.ID
-		vulns[id] = struct{}{}
+		vulns[f.Finding.OSV] = struct{}{}
 		mod := f.Trace[0].Module
 		modules[mod] = struct{}{}
 	}
@@ -97,6 +122,40 @@ func counters(findings []*findingSummary) summaryCounters {
 	return result
 }
 
+// stackCount returns the number of distinct call stacks in findings.
+func stackCount(findings []*findingSummary) int {
+	stacks := map[string]bool{}
+	for _, f := range findings {
+		if f.Trace[0].Function != "" {
+			stacks[traceKey(f.Trace)] = true
+		}
+	}
+	return len(stacks)
+}
+
+// dependencies returns the sorted modules through which the traces of
+// findings reach their vulnerable symbol: for each trace, the first module
+// called from the module at the root of the trace. Traces that stay within
+// their root module are skipped.
+func dependencies(findings []*findingSummary) []string {
+	seen := map[string]bool{}
+	var deps []string
+	for _, f := range findings {
+		top := f.Trace[len(f.Trace)-1].Module
+		for i := len(f.Trace) - 2; i >= 0; i-- {
+			if mod := f.Trace[i].Module; mod != top {
+				if !seen[mod] {
+					seen[mod] = true
+					deps = append(deps, mod)
+				}
+				break
+			}
+		}
+	}
+	sort.Strings(deps)
+	return deps
+}
+
 func isCalled(findings []*findingSummary) bool {
 	for _, f := range findings {
 		if f.Trace[0].Function != "" {
@@ -120,7 +179,7 @@ func getOSV(osvs []*osv.Entry, id string) *osv.Entry {
 func newFindingSummary(f *govulncheck.Finding) *findingSummary {
 	return &findingSummary{
 		Finding: f,
-		Compact: compactTrace(f),
+		Compact: compactTrace(f, symbolQualified, missingKeep),
 	}
 }
 
@@ -170,73 +229,138 @@ func platforms(mod string, e *osv.Entry) []string {
 	return keys
 }
 
-func posToString(p *govulncheck.Position) string {
+// affectsPlatform reports whether e affects module mod when built for
+// goos and goarch. An entry with no platform restrictions affects all
+// platforms.
+func affectsPlatform(mod string, e *osv.Entry, goos, goarch string) bool {
+	if e == nil {
+		return true
+	}
+	for _, a := range e.Affected {
+		if a.Module.Path != mod {
+			continue
+		}
+		if len(a.EcosystemSpecific.Packages) == 0 {
+			return true
+		}
+		for _, p := range a.EcosystemSpecific.Packages {
+			if matchesPlatform(goos, p.GOOS) && matchesPlatform(goarch, p.GOARCH) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesPlatform reports whether s is one of ps. An empty
+// ps matches every platform.
+func matchesPlatform(s string, ps []string) bool {
+	if len(ps) == 0 {
+		return true
+	}
+	for _, p := range ps {
+		if s == p {
+			return true
+		}
+	}
+	return false
+}
+
+// posToString returns p as file:line:column, or "" if p has no line. A
+// position in a file that no longer exists is handled as missing says:
+// missingOmit leaves it out, and missingFlag follows it with a note.
+func posToString(p *govulncheck.Position, missing string) string {
 	if p == nil || p.Line <= 0 {
 		return ""
 	}
+	note := ""
+	if fileMissing(p, missing) {
+		if missing == missingOmit {
+			return ""
+		}
+		note = missingFileNote
+	}
 	return token.Position{
 		Filename: AbsRelShorter(p.Filename),
 		Offset:   p.Offset,
 		Line:     p.Line,
 		Column:   p.Column,
-	}.String()
+	}.String() + note
+}
+
+// fileMissing reports whether the file of p does not exist, for the
+// missing policies that check it. Files that cannot be checked for
+// another reason are assumed to exist.
+func fileMissing(p *govulncheck.Position, missing string) bool {
+	if p == nil || p.Filename == "" || (missing != missingOmit && missing != missingFlag) {
+		return false
+	}
+	_, err := os.Stat(p.Filename)
+	return errors.Is(err, fs.ErrNotExist)
 }
 
-func symbol(frame *govulncheck.Frame, short bool) string {
+func symbol(frame *govulncheck.Frame, format string) string {
 	buf := &strings.Builder{}
-	addSymbolName(buf, frame, short)
+	addSymbolName(buf, frame, format)
 	return buf.String()
 }
 
 // compactTrace returns a short description of the call stack.
 // It prefers to show you the edge from the top module to other code, along with
-// the vulnerable symbol.
+// the vulnerable symbol, with symbol names in the given format.
 // Where the vulnerable symbol directly called by the users code, it will only
 // show those two points.
 // If the vulnerable symbol is in the users code, it will show the entry point
-// and the vulnerable symbol.
-func compactTrace(finding *govulncheck.Finding) string {
+// and the vulnerable symbol. The position of the call is handled as missing
+// says if its file no longer exists.
+func compactTrace(finding *govulncheck.Finding, format, missing string) string {
 	if len(finding.Trace) < 1 {
 		return ""
 	}
-	iTop := len(finding.Trace) - 1
-	topModule := finding.Trace[iTop].Module
-	// search for the exit point of the top module
-	for i, frame := range finding.Trace {
-		if frame.Module == topModule {
-			iTop = i
-			break
-		}
-	}
-
-	if iTop == 0 {
-		// all in one module, reset to the end
-		iTop = len(finding.Trace) - 1
-	}
-
+	iTop := topFrame(finding.Trace)
 	buf := &strings.Builder{}
-	topPos := posToString(finding.Trace[iTop].Position)
+	topPos := posToString(finding.Trace[iTop].Position, missing)
 	if topPos != "" {
 		buf.WriteString(topPos)
 		buf.WriteString(": ")
 	}
 
 	if iTop > 0 {
-		addSymbolName(buf, finding.Trace[iTop], true)
+		addSymbolName(buf, finding.Trace[iTop], format)
 		buf.WriteString(" calls ")
 	}
 	if iTop > 1 {
-		addSymbolName(buf, finding.Trace[iTop-1], true)
+		addSymbolName(buf, finding.Trace[iTop-1], format)
 		buf.WriteString(", which")
 		if iTop > 2 {
 			buf.WriteString(" eventually")
 		}
 		buf.WriteString(" calls ")
 	}
-	addSymbolName(buf, finding.Trace[0], true)
+	addSymbolName(buf, finding.Trace[0], format)
 	return buf.String()
 }
 
+// topFrame returns the index in trace of the exit point of the top module:
+// the frame in the scanned code that calls into other code. If the whole
+// trace is in one module, it is the entry point.
+func topFrame(trace []*govulncheck.Frame) int {
+	iTop := len(trace) - 1
+	topModule := trace[iTop].Module
+	// search for the exit point of the top module
+	for i, frame := range trace {
+		if frame.Module == topModule {
+			iTop = i
+			break
+		}
+	}
+	if iTop == 0 {
+		// all in one module, reset to the end
+		iTop = len(trace) - 1
+	}
+	return iTop
+}
+
 // notIdentifier reports whether ch is an invalid identifier character.
 func notIdentifier(ch rune) bool {
 	return !('a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' ||
@@ -265,13 +389,22 @@ func importPathToAssumedName(importPath string) string {
 	return base
 }
 
-func addSymbolName(w io.Writer, frame *govulncheck.Frame, short bool) {
+// Formats of symbol names, which are the values of -symbol-format.
+const (
+	symbolShort     = "short"     // the function, with its receiver
+	symbolQualified = "qualified" // also qualified by the package name
+	symbolFull      = "full"      // also qualified by the package path
+)
+
+// addSymbolName writes the name of the symbol of frame in the given
+// format. An empty format is the same as symbolFull.
+func addSymbolName(w io.Writer, frame *govulncheck.Frame, format string) {
 	if frame.Function == "" {
 		return
 	}
-	if frame.Package != "" {
+	if frame.Package != "" && format != symbolShort {
 		pkg := frame.Package
-		if short {
+		if format == symbolQualified {
 			pkg = importPathToAssumedName(frame.Package)
 		}
 		io.WriteString(w, pkg)
@@ -5,10 +5,15 @@
 package scan
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"time"
 
+	modulepkg "golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 	"golang.org/x/vuln/internal"
 	"golang.org/x/vuln/internal/govulncheck"
 	"golang.org/x/vuln/internal/osv"
@@ -26,13 +31,25 @@ const (
 	sectionStyle
 	keyStyle
 	valueStyle
+	criticalStyle
+	highStyle
+	moderateStyle
+	lowStyle
+	unknownSeverityStyle
+	summaryStyle
+	cleanStyle
 )
 
 // NewtextHandler returns a handler that writes govulncheck output as text.
 func NewTextHandler(w io.Writer) *TextHandler {
-	return &TextHandler{w: w}
+	return &TextHandler{w: w, indentUnit: defaultIndent, footerOnClean: true, lang: defaultLang, width: defaultWidth, missing: missingKeep}
 }
 
+// TextHandler writes govulncheck output as text. It gathers the OSV
+// entries and findings of a scan and writes them when flushed, so its
+// methods are expected to be called in the order of the JSON stream:
+// Config, Progress any number of times, OSV and Finding any number of
+// times, and then Flush. Call Reset before reusing it for another scan.
 type TextHandler struct {
 	w        io.Writer
 	osvs     []*osv.Entry
@@ -40,14 +57,175 @@ type TextHandler struct {
 
 	err error
 
-	showColor  bool
-	showTraces bool
+	showColor        bool
+	showTraces       bool
+	showConsidered   bool
+	showImportStacks bool
+	showSymbols      bool
+	showRawOSV       bool
+	showFixCommand   bool
+	showDepth        bool
+	showSignatures   bool
+	showReachability bool
+	showEffort       bool
+	showProvenance   bool
+	showVersionDelta bool
+	showCWE          bool
+	showRanges       bool
+	showCgoNotes     bool
+	showWhy          bool
+	showModSeverity  bool
+
+	// blame is the counts of -blame, if given.
+	blame *govulncheck.Blame
+
+	// modSeverities is the highest severity of each module, by path,
+	// with -show=module-severity.
+	modSeverities map[string]string
+
+	indentUnit   string
+	colorBy      string
+	group        string
+	compactWidth int
+	sortBy       string
+	top          int
+	maxFindings  int
+	symbolFormat string
+	splitFixable bool
+	noTraces     bool
+	marker       string
+	missing      string
+	versions     func(module string) ([]string, error)
+	overrides    map[string]string // severities by OSV ID or alias
+	errorMods    map[string]bool   // modules whose findings all fail
+	roots        []string          // the -modfile roots, listed even if clean
+	lang         string
+	width        int
+
+	footerOnClean bool
 }
 
 const (
 	detailsMessage = `For details, see https://pkg.go.dev/golang.org/x/vuln/cmd/govulncheck.`
 
 	binaryProgressMessage = `Scanning your binary for known vulnerabilities...`
+
+	archiveProgressMessage = `Scanning %s for known vulnerabilities...`
+
+	pluginProgressMessage = `Scanning your plugin for known vulnerabilities...`
+
+	moduleRootProgressMessage = `Scanning the module in %s...`
+
+	loadingProgressMessage = `Loading packages...`
+
+	noPatternsMessage = `No package patterns given, nothing to scan.`
+
+	// showConsidered is the -show option that lists every OSV entry
+	// checked during the scan.
+	showConsidered = "considered"
+
+	// showImportStacks is the -show option that prints how the module of
+	// an informational finding is imported.
+	showImportStacks = "import-stacks"
+
+	// showSymbols is the -show option that lists the vulnerable symbols
+	// that are called.
+	showSymbols = "symbols"
+
+	// showRawOSV is the -show option that prints the full OSV entry of
+	// each vulnerability, as JSON.
+	showRawOSV = "raw-osv"
+
+	// showFixCommand is the -show option that follows each fixed version
+	// with the command that upgrades to it.
+	showFixCommand = "fix-command"
+
+	// showDepth is the -show option that prints the depth of the trace of
+	// each called finding.
+	showDepth = "depth"
+
+	// showSignatures is the -show option that follows the vulnerable
+	// symbol of each trace with its signature, where it is known.
+	showSignatures = "signatures"
+
+	// showReachability is the -show option that counts the
+	// vulnerabilities by reachability before listing them.
+	showReachability = "reachability-summary"
+
+	// showEffort is the -show option that follows each fixed version
+	// with the kind of version bump that upgrades to it.
+	showEffort = "effort"
+
+	// showProvenance is the -show option that identifies the copy of the
+	// database entry each vulnerability was matched against.
+	showProvenance = "provenance"
+
+	// showVersionDelta is the -show option that follows each fixed
+	// version with how many releases it is ahead of the found version.
+	showVersionDelta = "version-delta"
+
+	// showCWE is the -show option that lists the CWE weaknesses of each
+	// vulnerability.
+	showCWE = "cwe"
+
+	// showRanges is the -show option that lists the version ranges that
+	// the OSV entry of each vulnerability declares affected.
+	showRanges = "ranges"
+
+	// showCgoNotes is the -show option that notes the cgo calls and
+	// assembly functions that the call graph of an informational
+	// vulnerability is not followed past.
+	showCgoNotes = "cgo-notes"
+
+	// showWhy is the -show option that prints how each vulnerable module
+	// is imported by the main module, as go mod why -m does.
+	showWhy = "why"
+
+	// showModSeverity is the -show option that prints the highest
+	// severity of the vulnerabilities of each module, and ranks the
+	// modules of -group=module by it.
+	showModSeverity = "module-severity"
+
+	// colorByStatus and colorBySeverity are the values of -color-by.
+	// They select whether the color of an OSV ID shows if the
+	// vulnerability is called, or how severe it is.
+	colorByStatus   = "status"
+	colorBySeverity = "severity"
+
+	// groupVuln, groupModule and groupSeverity are the values of -group.
+	// They select whether findings are listed by vulnerability, by module,
+	// or in sections by severity.
+	groupVuln     = "vuln"
+	groupModule   = "module"
+	groupSeverity = "severity"
+
+	// sortID and sortStacks are the values of -sort. They select whether
+	// called vulnerabilities are listed by ID or by the number of
+	// distinct call stacks that reach them, most first.
+	sortID     = "id"
+	sortStacks = "stacks"
+
+	// markerNumbered, markerDashes and markerNone are the values of
+	// -trace-marker. They select whether example traces start with their
+	// number, as in "#1: ", with a dash, or with nothing.
+	markerNumbered = "numbered"
+	markerDashes   = "dashes"
+	markerNone     = "none"
+
+	// missingKeep, missingOmit and missingFlag are the values of
+	// -missing-files. They select whether a trace position in a file
+	// that no longer exists, such as a deleted generated file, is shown
+	// as is, left out, or shown with missingFileNote.
+	missingKeep = "keep"
+	missingOmit = "omit"
+	missingFlag = "flag"
+
+	missingFileNote = " (file not found)"
+
+	// defaultIndent is the default unit of indentation of text output.
+	defaultIndent = "  "
+
+	binaryTagsMessage = `Build tags %s are recorded but do not affect the analysis of binaries.`
 )
 
 func (h *TextHandler) Show(show []string) {
@@ -57,10 +235,206 @@ func (h *TextHandler) Show(show []string) {
 			h.showTraces = true
 		case "color":
 			h.showColor = true
+		case showConsidered:
+			h.showConsidered = true
+		case showImportStacks:
+			h.showImportStacks = true
+		case showSymbols:
+			h.showSymbols = true
+		case showRawOSV:
+			h.showRawOSV = true
+		case showFixCommand:
+			h.showFixCommand = true
+		case showDepth:
+			h.showDepth = true
+		case showSignatures:
+			h.showSignatures = true
+		case showReachability:
+			h.showReachability = true
+		case showEffort:
+			h.showEffort = true
+		case showProvenance:
+			h.showProvenance = true
+		case showVersionDelta:
+			h.showVersionDelta = true
+		case showCWE:
+			h.showCWE = true
+		case showRanges:
+			h.showRanges = true
+		case showCgoNotes:
+			h.showCgoNotes = true
+		case showWhy:
+			h.showWhy = true
+		case showModSeverity:
+			h.showModSeverity = true
 		}
 	}
 }
 
+// Reset forgets the OSV entries, the findings and the write error of the
+// previous scan, so that h can be used for the next one. The options of h
+// are kept.
+func (h *TextHandler) Reset() {
+	h.osvs = nil
+	h.findings = nil
+	h.err = nil
+}
+
+// Group sets how findings are grouped: by vulnerability, the default,
+// by module, or by severity. Only informational findings are grouped
+// by module, and only called ones by severity.
+func (h *TextHandler) Group(by string) {
+	h.group = by
+}
+
+// SymbolFormat sets the format of symbol names in traces: symbolShort,
+// symbolQualified or symbolFull. By default, compact traces qualify
+// symbols by package name and full traces by package path.
+func (h *TextHandler) SymbolFormat(format string) {
+	h.symbolFormat = format
+}
+
+// SortBy sets the order of called vulnerabilities: by ID, the default,
+// or by the number of distinct call stacks reaching them.
+func (h *TextHandler) SortBy(by string) {
+	h.sortBy = by
+}
+
+// Top limits the called vulnerabilities listed in detail to the n most
+// severe ones, or lists them all if n is 0. The others are only counted.
+// Findings are still reported in full to Flush, so the error it returns
+// does not depend on n.
+func (h *TextHandler) Top(n int) {
+	h.top = n
+}
+
+// MaxFindings sets how many called vulnerabilities Flush tolerates
+// before it fails. The default, 0, fails on any of them. Modules given to
+// ErrorModules fail regardless.
+func (h *TextHandler) MaxFindings(n int) {
+	h.maxFindings = n
+}
+
+// Versions sets how the released versions of a module are listed, for
+// -show=version-delta. Modules whose versions cannot be listed get no
+// delta.
+func (h *TextHandler) Versions(list func(module string) ([]string, error)) {
+	h.versions = list
+}
+
+// TraceMarker sets how example traces start: markerNumbered, the
+// default, markerDashes or markerNone.
+func (h *TextHandler) TraceMarker(marker string) {
+	h.marker = marker
+}
+
+// Summary keeps the counts of -blame of the summary of JSON input, unless
+// BlameSummary sets them.
+func (h *TextHandler) Summary(summary *govulncheck.Summary) error {
+	if h.blame == nil {
+		h.blame = summary.Blame
+	}
+	return nil
+}
+
+// BlameSummary sets the counts of -blame for the summary. The handler
+// that keeps the findings gives them before flushing.
+func (h *TextHandler) BlameSummary(blame *govulncheck.Blame) {
+	h.blame = blame
+}
+
+// MissingFiles sets how trace positions in files that no longer exist
+// are shown: missingKeep, the default, missingOmit or missingFlag.
+func (h *TextHandler) MissingFiles(missing string) {
+	h.missing = missing
+}
+
+// Roots sets the directories of the go.mod files of the modules that
+// are scanned, with -modfile, so that the summary lists each of them,
+// including those without vulnerabilities.
+func (h *TextHandler) Roots(roots []string) {
+	h.roots = roots
+}
+
+// ErrorModules sets the modules whose vulnerabilities make Flush fail
+// even if they are only informational. Such informational
+// vulnerabilities are flagged in the output.
+func (h *TextHandler) ErrorModules(modules []string) {
+	h.errorMods = map[string]bool{}
+	for _, m := range modules {
+		h.errorMods[m] = true
+	}
+}
+
+// SplitFixable sets whether called vulnerabilities are listed in two
+// sections, those with a fixed version and those without, since they
+// usually call for different action.
+func (h *TextHandler) SplitFixable(split bool) {
+	h.splitFixable = split
+}
+
+// SeverityOverrides sets the severities, by OSV ID or alias, that replace
+// those of the database. The severity of the entries is expected to be
+// replaced already; the entries are only annotated.
+func (h *TextHandler) SeverityOverrides(overrides map[string]string) {
+	h.overrides = overrides
+}
+
+// NoTraces sets whether the example traces of called vulnerabilities are
+// left out, for users who only act on module upgrades.
+func (h *TextHandler) NoTraces(omit bool) {
+	h.noTraces = omit
+}
+
+// Width sets the width, in characters, that descriptions are wrapped
+// to. It is 80 by default.
+func (h *TextHandler) Width(width int) {
+	h.width = width
+}
+
+// Lang sets the language of the labels and headings of the output; it
+// must have a catalog. It is English by default.
+func (h *TextHandler) Lang(lang string) {
+	h.lang = lang
+}
+
+// FooterOnClean sets whether the closing feedback message is printed
+// when no vulnerabilities are called. It is printed by default.
+func (h *TextHandler) FooterOnClean(show bool) {
+	h.footerOnClean = show
+}
+
+// Indent sets the string used for each level of indentation in the
+// output. The default is two spaces.
+func (h *TextHandler) Indent(unit string) {
+	h.indentUnit = unit
+}
+
+// StripANSI makes the handler remove any terminal escape sequences from
+// its output, whether they come from -show=color or from the
+// vulnerability data.
+func (h *TextHandler) StripANSI() {
+	h.w = &ansiStripper{w: h.w}
+}
+
+// CompactWidth sets the number of characters compact traces are
+// truncated to, from the middle. There is no limit when width is 0, the
+// default. Traces shown in full are never truncated.
+func (h *TextHandler) CompactWidth(width int) {
+	h.compactWidth = width
+}
+
+// ColorBy sets what the color of OSV IDs represents, either colorByStatus
+// (the default) or colorBySeverity.
+func (h *TextHandler) ColorBy(by string) {
+	h.colorBy = by
+}
+
+// indent returns the prefix for the given level of indentation.
+func (h *TextHandler) indent(level int) string {
+	return strings.Repeat(h.indentUnit, level)
+}
+
 func Flush(h govulncheck.Handler) error {
 	if th, ok := h.(interface{ Flush() error }); ok {
 		return th.Flush()
@@ -69,38 +443,78 @@ func Flush(h govulncheck.Handler) error {
 }
 
 func (h *TextHandler) Flush() error {
-	fixupFindings(h.osvs, h.findings)
-	h.byVulnerability(h.findings)
-	h.summary(h.findings)
-	h.print("\nShare feedback at https://go.dev/s/govulncheck-feedback.\n")
+	findings, missing := withOSV(h.osvs, h.findings)
+	fixupFindings(h.osvs, findings)
+	if h.showModSeverity {
+		h.modSeverities = moduleSeverities(findings)
+	}
+	h.byVulnerability(findings)
+	if h.showSymbols {
+		h.symbols(findings)
+	}
+	if h.showConsidered {
+		h.considered(h.osvs, findings)
+	}
+	if len(missing) > 0 {
+		skipped := len(h.findings) - len(findings)
+		h.print(h.msgf(plural(skipped, msgMissingOSVOne, msgMissingOSVMany), skipped, strings.Join(missing, ", ")), "\n\n")
+	}
+	h.summary(findings)
+	if h.footerOnClean || isCalled(h.findings) {
+		h.print("\n", h.msg(msgFeedback), "\n")
+	}
 	if h.err != nil {
 		return h.err
 	}
-	if isCalled(h.findings) {
+	// A finding without its OSV entry still counts for the exit code,
+	// so that a consistency bug cannot hide a called vulnerability.
+	if counters(h.findings).VulnerabilitiesCalled > h.maxFindings || h.inErrorModule(h.findings) {
 		return errVulnerabilitiesFound
 	}
 	return nil
 }
 
+// inErrorModule reports whether any of findings is in a module given to
+// ErrorModules.
+func (h *TextHandler) inErrorModule(findings []*findingSummary) bool {
+	for _, f := range findings {
+		if h.errorMods[f.Trace[0].Module] {
+			return true
+		}
+	}
+	return false
+}
+
 // Config writes text output formatted according to govulncheck-intro.tmpl.
 func (h *TextHandler) Config(config *govulncheck.Config) error {
-	h.print("Using ")
+	data := phrase{msgDataFrom, []any{config.DB}}
+	if config.DBLastModified != nil {
+		data = phrase{msgDataModified, []any{config.DB, *config.DBLastModified}}
+		if config.DBAgeSeconds > 0 {
+			data = phrase{msgDataModifiedAge, []any{config.DB, *config.DBLastModified, formatAge(time.Duration(config.DBAgeSeconds) * time.Second)}}
+		}
+	}
+	var goVersion, scanner any
 	if config.GoVersion != "" {
-		h.style(goStyle, config.GoVersion)
-		h.print(` and `)
+		goVersion = styled{goStyle, config.GoVersion}
 	}
 	if config.ScannerName != "" {
-		h.style(scannerStyle, config.ScannerName)
+		scanner = styled{scannerStyle, config.ScannerName}
 		if config.ScannerVersion != "" {
-			h.print(`@`, config.ScannerVersion)
+			scanner = []styled{{scannerStyle, config.ScannerName}, {defaultStyle, "@" + config.ScannerVersion}}
 		}
-		h.print(` with `)
 	}
-	h.print(`vulnerability data from `, config.DB)
-	if config.DBLastModified != nil {
-		h.print(` (last modified `, *config.DBLastModified, `)`)
+	switch {
+	case goVersion != nil && scanner != nil:
+		h.stylef(defaultStyle, msgUsingWith, phrase{msgAnd, []any{goVersion, scanner}}, data)
+	case scanner != nil:
+		h.stylef(defaultStyle, msgUsingWith, scanner, data)
+	case goVersion != nil:
+		h.stylef(defaultStyle, msgUsing, phrase{msgAnd, []any{goVersion, data}})
+	default:
+		h.stylef(defaultStyle, msgUsing, data)
 	}
-	h.print(".\n\n")
+	h.print("\n\n")
 	return h.err
 }
 
@@ -121,30 +535,86 @@ func (h *TextHandler) Finding(finding *govulncheck.Finding) error {
 	if err := validateFindings(finding); err != nil {
 		return err
 	}
-	h.findings = append(h.findings, newFindingSummary(finding))
+	summary := newFindingSummary(finding)
+	if h.symbolFormat != "" || h.missing != missingKeep {
+		format := h.symbolFormat
+		if format == "" {
+			format = symbolQualified
+		}
+		summary.Compact = compactTrace(finding, format, h.missing)
+	}
+	h.findings = append(h.findings, summary)
 	return nil
 }
 
+// reachability prints how many vulnerabilities of findings are called,
+// imported but not called, and only required.
+func (h *TextHandler) reachability(findings []*findingSummary) {
+	var all []*govulncheck.Finding
+	for _, f := range findings {
+		all = append(all, f.Finding)
+	}
+	r := govulncheck.CountReachability(all)
+	h.print(h.msgf(msgReachability, r.Called, r.Imported, r.Required), "\n\n")
+}
+
 func (h *TextHandler) byVulnerability(findings []*findingSummary) {
 	byVuln := groupByVuln(findings)
+	if h.sortBy == sortStacks {
+		sortByStacks(byVuln)
+	}
 	called := 0
 	for _, findings := range byVuln {
 		if isCalled(findings) {
-			h.vulnerability(called, findings)
 			called++
 		}
 	}
 	unCalled := len(byVuln) - called
+	if len(byVuln) > 0 {
+		h.print(h.msgf(plural(len(byVuln), msgFoundOne, msgFoundMany), len(byVuln), called, unCalled), "\n\n")
+		if h.showReachability {
+			h.reachability(findings)
+		}
+	}
+	hidden := 0
+	if h.top > 0 {
+		sortBySeverity(byVuln)
+		byVuln, hidden = topCalled(byVuln, h.top)
+	}
+	index := 0
+	if h.splitFixable {
+		index = h.calledSection(index, h.section(msgFixableSection)+"\n", byVuln, isFixable)
+		h.calledSection(index, h.section(msgNoFixSection)+"\n", byVuln, func(findings []*findingSummary) bool {
+			return !isFixable(findings)
+		})
+	} else if h.group == groupSeverity {
+		for _, sec := range severitySections {
+			sec := sec
+			index = h.calledSection(index, h.section(sec.heading)+"\n", byVuln, func(findings []*findingSummary) bool {
+				return entrySeverity(findings[0].OSV) == sec.severity
+			})
+		}
+	} else {
+		for _, findings := range byVuln {
+			if isCalled(findings) {
+				h.vulnerability(index, findings)
+				index++
+			}
+		}
+	}
+	if hidden > 0 {
+		h.print(h.msgf(plural(hidden, msgHiddenOne, msgHiddenMany), hidden, h.top), "\n\n")
+	}
 	if unCalled == 0 {
 		return
 	}
-	h.style(sectionStyle, "=== Informational ===\n")
-	h.print("\nFound ", unCalled)
-	h.print(choose(unCalled == 1, ` vulnerability`, ` vulnerabilities`))
-	h.print(" in packages that you import, but there are no call\nstacks leading to the use of ")
-	h.print(choose(unCalled == 1, `this vulnerability`, `these vulnerabilities`))
-	h.print(". You may not need to\ntake any action. See https://pkg.go.dev/golang.org/x/vuln/cmd/govulncheck\nfor details.\n\n")
-	index := 0
+	h.style(sectionStyle, h.section(msgInformationalSection))
+	h.print("\n", h.msgf(plural(unCalled, msgInformationalOne, msgInformationalMany), unCalled), "\n\n")
+	if h.group == groupModule {
+		h.informationalByModule(byVuln)
+		return
+	}
+	index = 0
 	for _, findings := range byVuln {
 		if !isCalled(findings) {
 			h.vulnerability(index, findings)
@@ -153,25 +623,293 @@ func (h *TextHandler) byVulnerability(findings []*findingSummary) {
 	}
 }
 
+// severitySection is the section of called vulnerabilities of a
+// severity with -group=severity, and the style of their entries.
+type severitySection struct {
+	severity string // "" for those without a known severity
+	heading  message
+	style    style
+}
+
+// severitySections are the sections of -group=severity, most severe
+// first.
+var severitySections = []severitySection{
+	{"CRITICAL", msgCriticalSection, criticalStyle},
+	{"HIGH", msgHighSection, highStyle},
+	{"MODERATE", msgMediumSection, moderateStyle},
+	{"LOW", msgLowSection, lowStyle},
+	{"", msgUnclassifiedSection, unknownSeverityStyle},
+}
+
+// sectionOf returns the section of severity, one of severities or "".
+func sectionOf(severity string) severitySection {
+	for _, sec := range severitySections {
+		if sec.severity == severity {
+			return sec
+		}
+	}
+	return severitySections[len(severitySections)-1]
+}
+
+// moduleSeverity prints the highest severity of module at the indent
+// level, with -show=module-severity.
+func (h *TextHandler) moduleSeverity(level int, module string) {
+	if !h.showModSeverity {
+		return
+	}
+	sec := sectionOf(h.modSeverities[module])
+	h.style(keyStyle, h.indent(level)+h.msg(msgHighestSeverity)+" ")
+	h.style(sec.style, h.msg(sec.heading))
+	h.print("\n")
+}
+
+// calledSection prints the called vulnerabilities of byVuln for which in
+// reports true under heading, numbering them from index. It prints
+// nothing if there are none, and returns the next index.
+func (h *TextHandler) calledSection(index int, heading string, byVuln [][]*findingSummary, in func([]*findingSummary) bool) int {
+	first := true
+	for _, findings := range byVuln {
+		if !isCalled(findings) || !in(findings) {
+			continue
+		}
+		if first {
+			h.style(sectionStyle, heading)
+			first = false
+		}
+		h.vulnerability(index, findings)
+		index++
+	}
+	return index
+}
+
+// isFixable reports whether every module of the vulnerability findings
+// has a fixed version.
+func isFixable(findings []*findingSummary) bool {
+	for _, f := range findings {
+		if f.FixedVersion == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// sortByStacks sorts the called vulnerabilities of byVuln by the number
+// of distinct call stacks reaching them, most first. Vulnerabilities with
+// as many stacks, and the informational ones, keep their order by ID.
+func sortByStacks(byVuln [][]*findingSummary) {
+	counts := map[string]int{}
+	for _, vuln := range byVuln {
+		counts[vuln[0].OSV.ID] = stackCount(vuln)
+	}
+	sort.SliceStable(byVuln, func(i, j int) bool {
+		return counts[byVuln[i][0].OSV.ID] > counts[byVuln[j][0].OSV.ID]
+	})
+}
+
+// sortBySeverity sorts the called vulnerabilities of byVuln by severity,
+// most severe first, and then by the number of distinct call stacks
+// reaching them. Vulnerabilities that tie keep their order, and the
+// informational ones are moved after the called ones.
+func sortBySeverity(byVuln [][]*findingSummary) {
+	rank := func(vuln []*findingSummary) int {
+		if !isCalled(vuln) {
+			return len(severities) + 1
+		}
+		return severityRank(entrySeverity(vuln[0].OSV))
+	}
+	ranks := map[string]int{}
+	counts := map[string]int{}
+	for _, vuln := range byVuln {
+		ranks[vuln[0].OSV.ID] = rank(vuln)
+		counts[vuln[0].OSV.ID] = stackCount(vuln)
+	}
+	sort.SliceStable(byVuln, func(i, j int) bool {
+		a, b := byVuln[i][0].OSV.ID, byVuln[j][0].OSV.ID
+		if ranks[a] != ranks[b] {
+			return ranks[a] < ranks[b]
+		}
+		return counts[a] > counts[b]
+	})
+}
+
+// topCalled returns byVuln without the called vulnerabilities after the
+// first n, and the number of those left out.
+func topCalled(byVuln [][]*findingSummary, n int) ([][]*findingSummary, int) {
+	var kept [][]*findingSummary
+	called, hidden := 0, 0
+	for _, vuln := range byVuln {
+		if isCalled(vuln) {
+			if called == n {
+				hidden++
+				continue
+			}
+			called++
+		}
+		kept = append(kept, vuln)
+	}
+	return kept, hidden
+}
+
+// informationalByModule lists the modules that bring in the
+// informational vulnerabilities of byVuln, with the most affected
+// modules first, to help decide which dependencies to drop.
+func (h *TextHandler) informationalByModule(byVuln [][]*findingSummary) {
+	var findings []*findingSummary
+	for _, vuln := range byVuln {
+		if !isCalled(vuln) {
+			findings = append(findings, vuln...)
+		}
+	}
+	type moduleVulns struct {
+		frame    *govulncheck.Frame
+		ids      []string
+		fixed    string // highest fixed version of the vulnerabilities
+		severity string // of the most severe one
+	}
+	var mods []moduleVulns
+	for _, module := range groupByModule(findings) {
+		seen := map[string]bool{}
+		var ids []string
+		fixed := ""
+		for _, f := range module {
+			if !seen[f.OSV.ID] {
+				seen[f.OSV.ID] = true
+				ids = append(ids, f.OSV.ID)
+			}
+			if semver.Compare(f.FixedVersion, fixed) > 0 {
+				fixed = f.FixedVersion
+			}
+		}
+		sort.Strings(ids)
+		mods = append(mods, moduleVulns{frame: module[0].Trace[0], ids: ids, fixed: fixed, severity: h.modSeverities[module[0].Trace[0].Module]})
+	}
+	sort.SliceStable(mods, func(i, j int) bool {
+		if h.showModSeverity && mods[i].severity != mods[j].severity {
+			return severityRank(mods[i].severity) < severityRank(mods[j].severity)
+		}
+		return len(mods[i].ids) > len(mods[j].ids)
+	})
+	for _, m := range mods {
+		if m.frame.Module == internal.GoStdModulePath {
+			h.style(keyStyle, h.msg(msgStandardLibrary))
+		} else {
+			h.style(keyStyle, h.msg(msgModule)+" ")
+			h.print(m.frame.Module)
+		}
+		h.print("\n")
+		if version := moduleVersionString(m.frame.Module, m.frame.Version); version != "" {
+			h.style(keyStyle, h.indent(1)+h.msg(msgFoundIn)+" ")
+			if m.frame.Module != internal.GoStdModulePath {
+				h.print(m.frame.Module, "@")
+			}
+			h.print(version, "\n")
+		}
+		h.style(keyStyle, h.indent(1)+h.msg(msgFixedIn)+" ")
+		if fixed := moduleVersionString(m.frame.Module, m.fixed); fixed != "" {
+			if m.frame.Module != internal.GoStdModulePath {
+				h.print(m.frame.Module, "@")
+			}
+			h.print(fixed, h.fixCommand(m.frame.Module, m.fixed), "\n")
+		} else {
+			h.print(h.msg(msgNotAvailable), "\n")
+		}
+		h.moduleSeverity(1, m.frame.Module)
+		h.style(keyStyle, h.indent(1)+h.msg(msgInformationalVulns)+" ")
+		h.print(len(m.ids), " (", strings.Join(m.ids, ", "), ")\n\n")
+	}
+}
+
+// fixCommand returns the note with the go get command that upgrades
+// module to fixed, if -show=fix-command was given. Go itself is not
+// upgraded with go get, so there is no note for the standard library.
+func (h *TextHandler) fixCommand(module, fixed string) string {
+	if !h.showFixCommand || fixed == "" || module == internal.GoStdModulePath || module == internal.GoCmdModulePath {
+		return ""
+	}
+	return " (" + h.msgf(msgFixCommand, module, fixed) + ")"
+}
+
+// effort returns the note telling whether the upgrade of module from
+// found to fixed is a patch, minor or major version bump, if
+// -show=effort was given.
+func (h *TextHandler) effort(module, found, fixed string) string {
+	if !h.showEffort || found == "" || fixed == "" {
+		return ""
+	}
+	return " (" + h.msg(upgradeEffort(module, found, fixed)) + ")"
+}
+
+// upgradeEffort returns the message naming the kind of version bump from
+// found to fixed, in semver. A major bump to v2 or later of a module other than Go itself
+// changes its import path, which is more work, so it is flagged.
+func upgradeEffort(module, found, fixed string) message {
+	switch {
+	case semver.Major(found) != semver.Major(fixed):
+		if newModulePath(module, fixed) {
+			return msgNewImportPath
+		}
+		return msgMajorUpgrade
+	case semver.MajorMinor(found) != semver.MajorMinor(fixed):
+		return msgMinorUpgrade
+	default:
+		return msgPatchUpgrade
+	}
+}
+
+// newModulePath reports whether version is of a major version that has
+// a module path other than module, such as v2.0.0 of a module path
+// without a /v2 suffix.
+func newModulePath(module, version string) bool {
+	if module == internal.GoStdModulePath || module == internal.GoCmdModulePath {
+		return false
+	}
+	_, pathMajor, ok := modulepkg.SplitPathVersion(module)
+	return ok && modulepkg.CheckPathMajor(version, pathMajor) != nil
+}
+
 func (h *TextHandler) vulnerability(index int, findings []*findingSummary) {
-	h.style(keyStyle, "Vulnerability")
+	h.style(keyStyle, h.msg(msgVulnerability))
 	h.print(" #", index+1, ": ")
-	if isCalled(findings) {
+	switch {
+	case h.colorBy == colorBySeverity:
+		h.style(severityStyle(findings[0].OSV), findings[0].OSV.ID)
+	case isCalled(findings):
 		h.style(osvCalledStyle, findings[0].OSV.ID)
-	} else {
+	default:
 		h.style(osvImportedStyle, findings[0].OSV.ID)
 	}
+	if _, ok := overriddenSeverity(h.overrides, findings[0].OSV); ok {
+		h.print(" (", h.msg(msgSeverityOverridden), ")")
+	}
+	if findings[0].Test {
+		h.print(" (", h.msg(msgTestCode), ")")
+	}
+	if !isCalled(findings) && h.inErrorModule(findings) {
+		h.print(" (")
+		h.style(osvCalledStyle, h.msg(msgErrorModule))
+		h.print(")")
+	}
 	h.print("\n")
 	h.style(detailsStyle)
 	description := findings[0].OSV.Summary
 	if description == "" {
 		description = findings[0].OSV.Details
 	}
-	h.wrap("    ", description, 80)
+	if description == "" {
+		description = findings[0].OSV.ID
+	}
+	h.wrap(h.indent(2), description, h.width)
 	h.style(defaultStyle)
 	h.print("\n")
-	h.style(keyStyle, "  More info:")
+	h.style(keyStyle, h.indent(1)+h.msg(msgMoreInfo))
 	h.print(" ", findings[0].OSV.DatabaseSpecific.URL, "\n")
+	if h.showProvenance {
+		h.provenance(findings[0].OSV, findings[0].Provenance)
+	}
+	if ids := cwes(findings[0].OSV); h.showCWE && len(ids) > 0 {
+		h.style(keyStyle, h.indent(1)+h.msg(msgCWE))
+		h.print(" ", strings.Join(ids, ", "), "\n")
+	}
 
 	byModule := groupByModule(findings)
 	first := true
@@ -189,26 +927,31 @@ func (h *TextHandler) vulnerability(index int, findings []*findingSummary) {
 			h.print("\n")
 		}
 		first = false
-		h.print("  ")
+		h.print(h.indent(1))
 		if mod == internal.GoStdModulePath {
-			h.print("Standard library")
+			h.print(h.msg(msgStandardLibrary))
 		} else {
-			h.style(keyStyle, "Module: ")
+			h.style(keyStyle, h.msg(msgModule)+" ")
 			h.print(mod)
 		}
-		h.print("\n    ")
-		h.style(keyStyle, "Found in: ")
-		h.print(path, "@", foundVersion, "\n    ")
-		h.style(keyStyle, "Fixed in: ")
+		h.print("\n", h.indent(2))
+		h.style(keyStyle, h.msg(msgFoundIn)+" ")
+		h.print(path, "@", foundVersion, "\n", h.indent(2))
+		h.style(keyStyle, h.msg(msgFixedIn)+" ")
 		if fixedVersion != "" {
-			h.print(path, "@", fixedVersion)
+			h.print(path, "@", fixedVersion, h.versionDelta(mod, lastFrame.Version, module[0].FixedVersion), h.effort(mod, lastFrame.Version, module[0].FixedVersion), h.fixCommand(mod, module[0].FixedVersion))
 		} else {
-			h.print("N/A")
+			h.print(h.msg(msgNotAvailable))
 		}
 		h.print("\n")
+		h.moduleSeverity(2, mod)
+		if ranges := affectedRanges(h, mod, module[0].OSV.Affected); h.showRanges && len(ranges) > 0 {
+			h.style(keyStyle, h.indent(2)+h.msg(msgAffected)+" ")
+			h.print(strings.Join(ranges, ", "), "\n")
+		}
 		platforms := platforms(mod, module[0].OSV)
 		if len(platforms) > 0 {
-			h.style(keyStyle, "    Platforms: ")
+			h.style(keyStyle, h.indent(2)+h.msg(msgPlatforms)+" ")
 			for ip, p := range platforms {
 				if ip > 0 {
 					h.print(", ")
@@ -217,61 +960,334 @@ func (h *TextHandler) vulnerability(index int, findings []*findingSummary) {
 			}
 			h.print("\n")
 		}
+		if why := module[0].Why; h.showWhy && len(why) > 0 {
+			h.style(keyStyle, h.indent(2)+h.msg(msgWhy)+" ")
+			h.print(strings.Join(why, " -> "), "\n")
+		}
+		if deps := dependencies(module); len(deps) > 1 {
+			h.style(keyStyle, h.indent(2)+h.msg(msgReachedThrough)+" ")
+			h.print(strings.Join(deps, ", "), "\n")
+		}
+		if reason := module[0].Reason; reason != "" {
+			h.style(keyStyle, h.indent(2)+h.msg(msgReason)+" ")
+			h.print(reason, "\n")
+		}
+		if chain := module[0].ImportChain; h.showImportStacks && len(chain) > 0 {
+			h.style(keyStyle, h.indent(2)+h.msg(msgImportChain)+" ")
+			h.print(strings.Join(chain, " -> "), "\n")
+		}
+		if notes := boundaryNotes(module[0].Boundaries); h.showCgoNotes && len(notes) > 0 {
+			h.style(keyStyle, h.indent(2)+h.msg(msgBoundaries)+" ")
+			h.print(strings.Join(notes, "; "), "\n")
+		}
 		h.traces(module)
 	}
+	if h.showRawOSV {
+		h.rawOSV(findings[0].OSV)
+	}
 	h.print("\n")
 }
 
+// provenance prints which copy of entry the findings were matched
+// against: its modification time and, from the provenance p of a finding
+// if the database client recorded it, its endpoint in the database and
+// the hash of its content.
+func (h *TextHandler) provenance(entry *osv.Entry, p *govulncheck.Provenance) {
+	var parts []string
+	if p != nil {
+		parts = append(parts, p.Endpoint)
+	}
+	if !entry.Modified.IsZero() {
+		parts = append(parts, h.msgf(msgModified, entry.Modified.UTC().Format(time.RFC3339)))
+	}
+	if p != nil {
+		parts = append(parts, h.msgf(msgSHA256, p.SHA256))
+	}
+	if len(parts) == 0 {
+		return
+	}
+	h.style(keyStyle, h.indent(1)+h.msg(msgDatabaseEntry))
+	h.print(" ", strings.Join(parts, ", "), "\n")
+}
+
+// truncateMiddle shortens s to width characters, if it is longer, by
+// replacing its middle with an ellipsis. The tail of s, which names the
+// vulnerable function, is kept whole where width allows.
+func truncateMiddle(s string, width int) string {
+	const ellipsis = "..."
+	r := []rune(s)
+	if width <= 0 || len(r) <= width {
+		return s
+	}
+	if width <= len(ellipsis) {
+		return string(r[len(r)-width:])
+	}
+	room := width - len(ellipsis)
+	tail := room - room/3
+	for i := len(r) - 1; i >= 0; i-- {
+		if r[i] == ' ' {
+			if last := len(r) - i - 1; last > tail {
+				tail = last
+			}
+			break
+		}
+	}
+	if tail > room {
+		tail = room
+	}
+	return string(r[:room-tail]) + ellipsis + string(r[len(r)-tail:])
+}
+
+// rawOSV prints entry as indented JSON.
+func (h *TextHandler) rawOSV(entry *osv.Entry) {
+	b, err := json.MarshalIndent(entry, h.indent(2), h.indentUnit)
+	if err != nil {
+		h.err = err
+		return
+	}
+	h.style(keyStyle, h.indent(1)+h.msg(msgOSVEntry))
+	h.print("\n", h.indent(2), string(b), "\n")
+}
+
+// traceMarker returns what the i-th example trace starts with.
+func (h *TextHandler) traceMarker(i int) string {
+	switch h.marker {
+	case markerDashes:
+		return "- "
+	case markerNone:
+		return ""
+	default:
+		return fmt.Sprintf("#%d: ", i+1)
+	}
+}
+
 func (h *TextHandler) traces(traces []*findingSummary) {
+	if h.noTraces {
+		return
+	}
 	first := true
 	for i, entry := range traces {
 		if entry.Compact == "" {
 			continue
 		}
 		if first {
-			h.style(keyStyle, "    Example traces found:\n")
+			h.style(keyStyle, h.indent(2)+h.msg(msgExampleTraces)+"\n")
 		}
 		first = false
 
-		h.print("      #", i+1, ": ")
+		h.print(h.indent(3), h.traceMarker(i))
+		if entry.Binary != "" {
+			h.print(h.msgf(msgIn, entry.Binary), " ")
+		}
+		if entry.Source != "" {
+			h.print(h.msgf(msgIn, entry.Source), " ")
+		}
+		if entry.Root != "" {
+			h.print(h.msgf(msgIn, entry.Root), " ")
+		}
+		depth := ""
+		if h.showDepth {
+			depth = " (" + h.msgf(msgDepth, len(entry.Trace)) + ")"
+		}
 		if !h.showTraces {
-			h.print(entry.Compact, "\n")
+			h.print(truncateMiddle(entry.Compact, h.compactWidth), h.signature(entry.Trace[0]), depth, "\n")
 		} else {
-			h.print("for function ", symbol(entry.Trace[0], false), "\n")
+			h.print(h.msgf(msgForFunction, symbol(entry.Trace[0], h.symbolFormat)), h.signature(entry.Trace[0]), depth, "\n")
 			for i := len(entry.Trace) - 1; i >= 0; i-- {
 				t := entry.Trace[i]
-				h.print("        ")
-				if t.Position != nil {
-					h.print(posToString(t.Position), ": ")
+				h.print(h.indent(4))
+				if pos := posToString(t.Position, h.missing); pos != "" {
+					h.print(pos, ": ")
 				}
-				h.print(symbol(t, false), "\n")
+				h.print(symbol(t, h.symbolFormat), "\n")
 			}
 		}
 	}
 }
 
+// signature returns the signature of the symbol of frame, if it is known
+// and -show=signatures was given.
+func (h *TextHandler) signature(frame *govulncheck.Frame) string {
+	if !h.showSignatures {
+		return ""
+	}
+	return frame.Signature
+}
+
+// symbols lists the called vulnerable symbols, each with the IDs of the
+// vulnerabilities it is affected by.
+func (h *TextHandler) symbols(findings []*findingSummary) {
+	ids := map[string]map[string]bool{}
+	for _, f := range findings {
+		sym := symbol(f.Trace[0], symbolFull)
+		if sym == "" {
+			continue
+		}
+		if ids[sym] == nil {
+			ids[sym] = map[string]bool{}
+		}
+		ids[sym][f.OSV.ID] = true
+	}
+	if len(ids) == 0 {
+		return
+	}
+	syms := make([]string, 0, len(ids))
+	for sym := range ids {
+		syms = append(syms, sym)
+	}
+	sort.Strings(syms)
+	h.style(sectionStyle, h.section(msgSymbolsSection))
+	h.print("\n", h.msgf(plural(len(syms), msgCallsOne, msgCallsMany), len(syms)), "\n\n")
+	for _, sym := range syms {
+		var osvs []string
+		for id := range ids[sym] {
+			osvs = append(osvs, id)
+		}
+		sort.Strings(osvs)
+		h.print(h.indent(1), sym, ": ", strings.Join(osvs, ", "), "\n")
+	}
+	h.print("\n")
+}
+
+// considered lists every OSV entry that was checked against the
+// dependencies, and whether it produced a finding.
+func (h *TextHandler) considered(osvs []*osv.Entry, findings []*findingSummary) {
+	found := map[string]bool{}
+	for _, f := range findings {
+		found[f.OSV.ID] = true
+	}
+	entries := append([]*osv.Entry(nil), osvs...)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	h.style(sectionStyle, h.section(msgConsideredSection))
+	h.print("\n", h.msgf(plural(len(entries), msgCheckedOne, msgCheckedMany), len(entries)), "\n\n")
+	for _, e := range entries {
+		h.print(h.indent(1), e.ID, ": ")
+		m := msgNoFinding
+		if found[e.ID] {
+			m = msgFindingReported
+		}
+		h.print(h.msg(m), "\n")
+	}
+	h.print("\n")
+}
+
 func (h *TextHandler) summary(findings []*findingSummary) {
 	counters := counters(findings)
 	if counters.VulnerabilitiesCalled == 0 {
-		h.print("No vulnerabilities found.\n")
+		// The scan still fails on the vulnerabilities of -error-modules,
+		// so it is not reported as clean.
+		if h.errorModuleVulns(findings) > 0 {
+			h.style(summaryStyle, h.msg(msgNoneCalled))
+		} else {
+			h.style(cleanStyle, h.msg(msgNoVulnerabilities))
+		}
+		h.print("\n")
+		h.errorModuleSummary(findings)
+		h.blameSummary()
+		h.rootSummary(findings)
 		return
 	}
-	h.print(`Your code is affected by `)
-	h.style(valueStyle, counters.VulnerabilitiesCalled)
-	h.print(choose(counters.VulnerabilitiesCalled == 1, ` vulnerability`, ` vulnerabilities`))
-	h.print(` from`)
-	if counters.ModulesCalled > 0 {
-		h.print(` `)
-		h.style(valueStyle, counters.ModulesCalled)
-		h.print(choose(counters.ModulesCalled == 1, ` module`, ` modules`))
-	}
+	var from any = phrase{plural(counters.ModulesCalled, msgModulesOne, msgModulesMany), []any{styled{valueStyle, counters.ModulesCalled}}}
 	if counters.StdlibCalled {
-		if counters.ModulesCalled != 0 {
-			h.print(` and`)
+		from = phrase{msgAnd, []any{from, h.msg(msgGoStandardLibrary)}}
+		if counters.ModulesCalled == 0 {
+			from = h.msg(msgGoStandardLibrary)
+		}
+	}
+	n := counters.VulnerabilitiesCalled
+	h.stylef(summaryStyle, plural(n, msgAffectedByOne, msgAffectedByMany), styled{valueStyle, n}, from)
+	h.print("\n")
+	h.errorModuleSummary(findings)
+	h.blameSummary()
+	h.budgetSummary()
+	h.rootSummary(findings)
+}
+
+// budgetSummary prints whether the called vulnerabilities are within the
+// budget set by MaxFindings, if there is one.
+func (h *TextHandler) budgetSummary() {
+	if h.maxFindings == 0 {
+		return
+	}
+	m := plural(h.maxFindings, msgWithinBudgetOne, msgWithinBudgetMany)
+	if counters(h.findings).VulnerabilitiesCalled > h.maxFindings {
+		m = plural(h.maxFindings, msgOverBudgetOne, msgOverBudgetMany)
+	}
+	h.style(summaryStyle, h.msgf(m, h.maxFindings))
+	h.print("\n")
+}
+
+// errorModuleVulns returns the number of informational vulnerabilities
+// of findings in modules given to ErrorModules.
+func (h *TextHandler) errorModuleVulns(findings []*findingSummary) int {
+	n := 0
+	for _, vuln := range groupByVuln(findings) {
+		if !isCalled(vuln) && h.inErrorModule(vuln) {
+			n++
+		}
+	}
+	return n
+}
+
+// errorModuleSummary prints the number of informational vulnerabilities
+// that fail the scan because of ErrorModules, if any.
+func (h *TextHandler) errorModuleSummary(findings []*findingSummary) {
+	n := h.errorModuleVulns(findings)
+	if n == 0 {
+		return
+	}
+	h.style(summaryStyle, h.msgf(plural(n, msgErrorModulesOne, msgErrorModulesMany), n))
+	h.print("\n")
+}
+
+// blameSummary prints how many vulnerabilities -blame kept, if given.
+func (h *TextHandler) blameSummary() {
+	if h.blame == nil {
+		return
+	}
+	h.style(summaryStyle, h.msgf(plural(h.blame.Total, msgBlameOne, msgBlameMany), h.blame.Kept, h.blame.Total, h.blame.Module))
+	h.print("\n")
+}
+
+// rootSummary prints the number of called vulnerabilities of each module
+// root, when the modules of several go.mod files were scanned, and of each
+// report, when several reports were merged.
+func (h *TextHandler) rootSummary(findings []*findingSummary) {
+	h.tagSummary(findings, func(f *findingSummary) string { return f.Source }, nil)
+	h.tagSummary(findings, func(f *findingSummary) string { return f.Root }, h.roots)
+}
+
+// tagSummary prints the number of called vulnerabilities of findings for
+// each value of tag, if any finding has one, and for each of all, the
+// values to list even without findings.
+func (h *TextHandler) tagSummary(findings []*findingSummary, tag func(*findingSummary) string, all []string) {
+	var tags []string
+	byTag := map[string][]*findingSummary{}
+	for _, t := range all {
+		if _, ok := byTag[t]; !ok {
+			tags = append(tags, t)
+			byTag[t] = nil
+		}
+	}
+	for _, f := range findings {
+		t := tag(f)
+		if t == "" {
+			continue
+		}
+		if _, ok := byTag[t]; !ok {
+			tags = append(tags, t)
 		}
-		h.print(` the Go standard library`)
+		byTag[t] = append(byTag[t], f)
+	}
+	if len(tags) == 0 {
+		return
+	}
+	sort.Strings(tags)
+	h.print("\n")
+	for _, t := range tags {
+		called := counters(byTag[t]).VulnerabilitiesCalled
+		h.print(h.indent(1), t, ": ", h.msgf(plural(called, msgVulnerabilitiesOne, msgVulnerabilitiesMany), called), "\n")
 	}
-	h.print(".\n")
 }
 
 func (h *TextHandler) style(style style, values ...any) {
@@ -295,6 +1311,20 @@ func (h *TextHandler) style(style style, values ...any) {
 			h.print(colorFaint, fgYellow)
 		case valueStyle:
 			h.print(colorBold, fgCyan)
+		case criticalStyle:
+			h.print(colorBold, fgRed)
+		case highStyle:
+			h.print(colorBold, fgOrange)
+		case moderateStyle:
+			h.print(colorBold, fgYellow)
+		case lowStyle:
+			h.print(colorBold, fgGreen)
+		case unknownSeverityStyle:
+			h.print(colorBold)
+		case summaryStyle:
+			h.print(colorBold)
+		case cleanStyle:
+			h.print(colorBold, fgGreen)
 		}
 	}
 	h.print(values...)
@@ -303,6 +1333,12 @@ func (h *TextHandler) style(style style, values ...any) {
 	}
 }
 
+// severityStyle returns the style for the OSV ID of e when coloring by
+// severity. Entries without a known severity are only shown in bold.
+func severityStyle(e *osv.Entry) style {
+	return sectionOf(entrySeverity(e)).style
+}
+
 func (h *TextHandler) print(values ...any) int {
 	total, w := 0, 0
 	for _, v := range values {
@@ -318,7 +1354,29 @@ func (h *TextHandler) print(values ...any) int {
 
 // wrap wraps s to fit in maxWidth by breaking it into lines at whitespace. If a
 // single word is longer than maxWidth, it is retained as its own line.
+// Newlines in s separate paragraphs, which are reflowed independently and
+// start on a new line. Blank lines between paragraphs are kept, but runs of
+// them are collapsed into one.
 func (h *TextHandler) wrap(indent string, s string, maxWidth int) {
+	blank := false
+	for i, para := range strings.Split(strings.TrimSpace(s), "\n") {
+		if strings.TrimSpace(para) == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		if i > 0 {
+			h.print("\n")
+		}
+		h.wrapParagraph(indent, para, maxWidth)
+	}
+}
+
+// wrapParagraph wraps the single paragraph s as described by wrap.
+func (h *TextHandler) wrapParagraph(indent string, s string, maxWidth int) {
 	w := 0
 	for _, f := range strings.Fields(s) {
 		if w > 0 && w+len(f)+1 > maxWidth {
@@ -0,0 +1,146 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+func TestWrap(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "reflow",
+			in:   "one two\tthree  four five six",
+			want: "  one two three four\n  five six",
+		},
+		{
+			name: "two paragraphs",
+			in:   "The first paragraph is long enough to wrap.\n\nThe second one too, as it is long.\n",
+			want: "  The first\n  paragraph is long\n  enough to wrap.\n\n  The second one\n  too, as it is\n  long.",
+		},
+		{
+			name: "line break",
+			in:   "first line\nsecond line",
+			want: "  first line\n  second line",
+		},
+		{
+			name: "blank lines collapsed",
+			in:   "a\n\n\n  \nb",
+			want: "  a\n\n  b",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf strings.Builder
+			h := NewTextHandler(&buf)
+			h.wrap("  ", tc.in, 20)
+			if got := buf.String(); got != tc.want {
+				t.Errorf("got\n%q\nwant\n%q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	const trace = "vuln.go:14:20: vuln.main calls golang.org/x/text/language.Parse"
+	for _, tc := range []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{trace, 0, trace},
+		{trace, len(trace), trace},
+		{trace, 40, "vuln....golang.org/x/text/language.Parse"},
+		{trace, 10, "...e.Parse"},
+		{trace, 2, "se"},
+		{"a b c d e f g h i j k l m n o p", 13, "a b...m n o p"},
+	} {
+		if got := truncateMiddle(tc.in, tc.width); got != tc.want {
+			t.Errorf("truncateMiddle(%q, %d) = %q; want %q", tc.in, tc.width, got, tc.want)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	var buf strings.Builder
+	h := NewTextHandler(&buf)
+	h.Show([]string{"traces"})
+	scan := func(id string) string {
+		buf.Reset()
+		h.Reset()
+		h.OSV(&osv.Entry{ID: id, DatabaseSpecific: &osv.DatabaseSpecific{}})
+		h.Finding(&govulncheck.Finding{
+			OSV:   id,
+			Trace: []*govulncheck.Frame{{Module: "golang.org/a", Version: "v1.0.0", Package: "golang.org/a", Function: "V"}},
+		})
+		h.Flush()
+		return buf.String()
+	}
+	scan("GO-0000-0001")
+	got := scan("GO-0000-0002")
+	if strings.Contains(got, "GO-0000-0001") {
+		t.Errorf("second scan reports a finding of the first:\n%s", got)
+	}
+	if !strings.Contains(got, "Vulnerability #1: GO-0000-0002") || !strings.Contains(got, "for function") {
+		t.Errorf("second scan lost its finding or the options of the handler:\n%s", got)
+	}
+}
+
+func TestUpgradeEffort(t *testing.T) {
+	for _, tc := range []struct {
+		module, found, fixed string
+		want                 string
+	}{
+		{"golang.org/x/text", "v0.3.5", "v0.3.7", "patch upgrade"},
+		{"golang.org/x/text", "v0.3.5", "v0.4.0", "minor upgrade"},
+		{"example.com/m", "v0.9.0", "v1.0.0", "major upgrade"},
+		{"example.com/m", "v1.2.0", "v2.0.1", "major upgrade, new import path"},
+		{"example.com/m", "v1.2.0", "v2.0.1+incompatible", "major upgrade"},
+		{"stdlib", "v1.20.3", "v1.21.0", "minor upgrade"},
+	} {
+		if got := catalogs[defaultLang][upgradeEffort(tc.module, tc.found, tc.fixed)]; got != tc.want {
+			t.Errorf("upgradeEffort(%q, %q, %q) = %q; want %q", tc.module, tc.found, tc.fixed, got, tc.want)
+		}
+	}
+}
+
+func TestFlushError(t *testing.T) {
+	for _, tc := range []struct {
+		input   string
+		set     func(h *TextHandler)
+		wantErr error
+	}{
+		{"no-vulns.json", func(h *TextHandler) {}, nil},
+		{"no-vulns.json", func(h *TextHandler) { h.ErrorModules([]string{"golang.org/vmod"}) }, errVulnerabilitiesFound},
+		{"fixable.json", func(h *TextHandler) {}, errVulnerabilitiesFound},
+		{"fixable.json", func(h *TextHandler) { h.MaxFindings(3) }, nil},
+		{"fixable.json", func(h *TextHandler) { h.MaxFindings(2) }, errVulnerabilitiesFound},
+		{"missing-osv.json", func(h *TextHandler) {}, errVulnerabilitiesFound},
+	} {
+		input, err := os.Open(filepath.Join("testdata", tc.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer input.Close()
+		h := NewTextHandler(io.Discard)
+		tc.set(h)
+		if err := govulncheck.HandleJSON(input, h); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.Flush(); err != tc.wantErr {
+			t.Errorf("%s: got error %v; want %v", tc.input, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// trendReport summarizes a JSON report read in trend mode.
+type trendReport struct {
+	name   string          // name of the report file
+	time   *time.Time      // scan time recorded in the report, if any
+	called map[string]bool // IDs of the called vulnerabilities
+}
+
+// label identifies the report in the trend output: by its scan time if
+// it was recorded, or else by its file name.
+func (r *trendReport) label() string {
+	if r.time != nil {
+		return r.time.UTC().Format("2006-01-02 15:04")
+	}
+	return filepath.Base(r.name)
+}
+
+// trendEntry is the trend of one vulnerability across the reports.
+type trendEntry struct {
+	OSV       string `json:"osv"`
+	FirstSeen string `json:"first_seen"`
+	LastSeen  string `json:"last_seen"`
+	Present   bool   `json:"present"`
+}
+
+// runTrend reads the JSON reports named by the patterns of cfg and
+// writes, for each vulnerability called in any of them, the first and
+// last reports it was called in and whether it is still called in the
+// latest report.
+func runTrend(w io.Writer, cfg *config) error {
+	var reports []*trendReport
+	for _, name := range cfg.patterns {
+		r, err := readTrendReport(name)
+		if err != nil {
+			return err
+		}
+		reports = append(reports, r)
+	}
+	entries := trend(reports)
+	if cfg.format == formatJSON {
+		b, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(b, '\n'))
+		return err
+	}
+	return writeTrend(w, len(reports), entries)
+}
+
+// readTrendReport reads the called vulnerabilities of the JSON report in
+// the named file.
+func readTrendReport(name string) (*trendReport, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := &trendHandler{report: &trendReport{name: name, called: map[string]bool{}}}
+	if err := govulncheck.HandleJSON(f, h); err != nil {
+		return nil, fmt.Errorf("govulncheck: reading %s: %v", name, err)
+	}
+	return h.report, nil
+}
+
+// trend orders the reports by scan time, when all of them record one,
+// and returns the trend of every called vulnerability, sorted by ID.
+// Otherwise the reports are taken in the order given.
+func trend(reports []*trendReport) []*trendEntry {
+	timed := true
+	for _, r := range reports {
+		if r.time == nil {
+			timed = false
+		}
+	}
+	if timed {
+		sort.SliceStable(reports, func(i, j int) bool {
+			return reports[i].time.Before(*reports[j].time)
+		})
+	}
+	byID := map[string]*trendEntry{}
+	var entries []*trendEntry
+	for i, r := range reports {
+		for id := range r.called {
+			e := byID[id]
+			if e == nil {
+				e = &trendEntry{OSV: id, FirstSeen: r.label()}
+				byID[id] = e
+				entries = append(entries, e)
+			}
+			e.LastSeen = r.label()
+			e.Present = i == len(reports)-1
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].OSV < entries[j].OSV })
+	return entries
+}
+
+// writeTrend writes entries as a table.
+func writeTrend(w io.Writer, n int, entries []*trendEntry) error {
+	if len(entries) == 0 {
+		_, err := fmt.Fprintf(w, "No called vulnerabilities found in %d reports.\n", n)
+		return err
+	}
+	fmt.Fprintf(w, "Called vulnerabilities across %d reports:\n\n", n)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OSV\tFirst seen\tLast seen\tPresent")
+	for _, e := range entries {
+		present := "no"
+		if e.Present {
+			present = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.OSV, e.FirstSeen, e.LastSeen, present)
+	}
+	return tw.Flush()
+}
+
+// trendHandler records the scan time and the called vulnerabilities of
+// a report.
+type trendHandler struct {
+	report *trendReport
+}
+
+func (h *trendHandler) Config(config *govulncheck.Config) error {
+	h.report.time = config.ScanTime
+	return nil
+}
+
+func (h *trendHandler) Progress(progress *govulncheck.Progress) error {
+	return nil
+}
+
+func (h *trendHandler) OSV(entry *osv.Entry) error {
+	return nil
+}
+
+func (h *trendHandler) Finding(finding *govulncheck.Finding) error {
+	if err := validateFindings(finding); err != nil {
+		return err
+	}
+	if finding.Trace[0].Function != "" {
+		h.report.called[finding.OSV] = true
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+func TestTrend(t *testing.T) {
+	dir := t.TempDir()
+	// writeReport writes a report scanned on the given day, with a
+	// called finding for each of the called IDs and an informational
+	// one for each of the imported IDs.
+	writeReport := func(name string, day int, called, imported []string) string {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		h := govulncheck.NewJSONHandler(f)
+		at := time.Date(2023, 6, day, 0, 0, 0, 0, time.UTC)
+		if err := h.Config(&govulncheck.Config{ScanTime: &at}); err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range called {
+			h.Finding(&govulncheck.Finding{OSV: id, Trace: []*govulncheck.Frame{{Module: "m", Package: "m/p", Function: "F"}}})
+		}
+		for _, id := range imported {
+			h.Finding(&govulncheck.Finding{OSV: id, Trace: []*govulncheck.Frame{{Module: "m", Package: "m/p"}}})
+		}
+		return f.Name()
+	}
+	// The reports are given out of order.
+	cfg := &config{patterns: []string{
+		writeReport("c.json", 15, []string{"GO-0000-0002"}, []string{"GO-0000-0001"}),
+		writeReport("a.json", 1, []string{"GO-0000-0001"}, nil),
+		writeReport("b.json", 8, []string{"GO-0000-0001", "GO-0000-0002"}, []string{"GO-0000-0003"}),
+	}}
+	var buf strings.Builder
+	if err := runTrend(&buf, cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := `Called vulnerabilities across 3 reports:
+
+OSV           First seen        Last seen         Present
+GO-0000-0001  2023-06-01 00:00  2023-06-08 00:00  no
+GO-0000-0002  2023-06-08 00:00  2023-06-15 00:00  yes
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
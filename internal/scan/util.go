@@ -6,6 +6,7 @@ package scan
 
 import (
 	"fmt"
+	"strings"
 
 	"golang.org/x/vuln/internal"
 	"golang.org/x/vuln/internal/govulncheck"
@@ -67,6 +68,51 @@ func fixedVersion(modulePath string, affected []osv.Affected) string {
 	return fixed
 }
 
+// affectedRanges describes the semver ranges of versions that affected
+// declares vulnerable for modulePath, in their order, such as "before
+// v0.3.7" or "from v1.1.2 before v1.1.5", with the versions written as
+// those of the module. The ranges are worded by the text handler h, in
+// its -lang language.
+func affectedRanges(h *TextHandler, modulePath string, affected []osv.Affected) []string {
+	version := func(v string) string { return moduleVersionString(modulePath, "v"+v) }
+	span := func(introduced, fixed string) string {
+		switch {
+		case (introduced == "" || introduced == "0") && fixed == "":
+			return h.msg(msgAllVersions)
+		case introduced == "" || introduced == "0":
+			return h.msgf(msgRangeBefore, version(fixed))
+		case fixed == "":
+			return h.msgf(msgRangeFrom, version(introduced))
+		}
+		return h.msgf(msgRangeFromBefore, version(introduced), version(fixed))
+	}
+	var ranges []string
+	for _, a := range affected {
+		if a.Module.Path != modulePath {
+			continue
+		}
+		for _, r := range a.Ranges {
+			if r.Type != osv.RangeTypeSemver {
+				continue
+			}
+			introduced, open := "", false
+			for _, e := range r.Events {
+				switch {
+				case e.Introduced != "" && !open:
+					introduced, open = e.Introduced, true
+				case e.Fixed != "":
+					ranges = append(ranges, span(introduced, e.Fixed))
+					introduced, open = "", false
+				}
+			}
+			if open {
+				ranges = append(ranges, span(introduced, ""))
+			}
+		}
+	}
+	return ranges
+}
+
 func moduleVersionString(modulePath, version string) string {
 	if version == "" {
 		return ""
@@ -76,3 +122,37 @@ func moduleVersionString(modulePath, version string) string {
 	}
 	return version
 }
+
+// maxBoundaryNotes is the number of functions listed for each kind of
+// boundary by boundaryNotes.
+const maxBoundaryNotes = 3
+
+// boundaryNotes describes boundaries, in the form of Finding.Boundaries,
+// with a note for each kind of boundary that lists its first functions,
+// such as "cgo: example.com/c._Cfunc_open".
+func boundaryNotes(boundaries []string) []string {
+	var kinds []string
+	byKind := map[string][]string{}
+	for _, b := range boundaries {
+		kind, fn, ok := strings.Cut(b, ": ")
+		if !ok {
+			continue
+		}
+		if _, ok := byKind[kind]; !ok {
+			kinds = append(kinds, kind)
+		}
+		byKind[kind] = append(byKind[kind], fn)
+	}
+	var notes []string
+	for _, kind := range kinds {
+		fns := byKind[kind]
+		note := kind + ": "
+		if len(fns) > maxBoundaryNotes {
+			note += strings.Join(fns[:maxBoundaryNotes], ", ") + fmt.Sprintf(" and %d more", len(fns)-maxBoundaryNotes)
+		} else {
+			note += strings.Join(fns, ", ")
+		}
+		notes = append(notes, note)
+	}
+	return notes
+}
@@ -5,8 +5,11 @@
 package scan
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
+	"golang.org/x/vuln/internal/govulncheck"
 	"golang.org/x/vuln/internal/osv"
 )
 
@@ -180,3 +183,92 @@ func TestLatestFixed(t *testing.T) {
 		})
 	}
 }
+
+func TestAffectedRanges(t *testing.T) {
+	semver := func(events ...osv.RangeEvent) osv.Range {
+		return osv.Range{Type: osv.RangeTypeSemver, Events: events}
+	}
+	for _, test := range []struct {
+		name   string
+		module string
+		in     []osv.Affected
+		want   string
+	}{
+		{
+			name:   "from the start",
+			module: "example.com/m",
+			in:     []osv.Affected{{Module: osv.Module{Path: "example.com/m"}, Ranges: []osv.Range{semver(osv.RangeEvent{Introduced: "0"}, osv.RangeEvent{Fixed: "0.3.7"})}}},
+			want:   "before v0.3.7",
+		},
+		{
+			name:   "several ranges and an open one",
+			module: "example.com/m",
+			in: []osv.Affected{
+				{Module: osv.Module{Path: "example.com/other"}, Ranges: []osv.Range{semver(osv.RangeEvent{Introduced: "0"})}},
+				{Module: osv.Module{Path: "example.com/m"}, Ranges: []osv.Range{semver(
+					osv.RangeEvent{Introduced: "1.0.0"}, osv.RangeEvent{Fixed: "1.0.4"}, osv.RangeEvent{Introduced: "1.1.2"})}},
+			},
+			want: "from v1.0.0 before v1.0.4, from v1.1.2",
+		},
+		{
+			name:   "no fix",
+			module: "example.com/m",
+			in:     []osv.Affected{{Module: osv.Module{Path: "example.com/m"}, Ranges: []osv.Range{semver(osv.RangeEvent{Introduced: "0"})}}},
+			want:   "all versions",
+		},
+		{
+			name:   "standard library",
+			module: "stdlib",
+			in:     []osv.Affected{{Module: osv.Module{Path: "stdlib"}, Ranges: []osv.Range{semver(osv.RangeEvent{Introduced: "1.20.0"}, osv.RangeEvent{Fixed: "1.20.3"})}}},
+			want:   "from go1.20 before go1.20.3",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := strings.Join(affectedRanges(&TextHandler{}, test.module, test.in), ", "); got != test.want {
+				t.Errorf("got %q; want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBoundaryNotes(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		boundaries []string
+		want       []string
+	}{
+		{"none", nil, nil},
+		{
+			"kinds",
+			[]string{"cgo: golang.org/c._Cfunc_open", "assembly: golang.org/x.add", "cgo: golang.org/c._Cfunc_close"},
+			[]string{"cgo: golang.org/c._Cfunc_open, golang.org/c._Cfunc_close", "assembly: golang.org/x.add"},
+		},
+		{
+			"many",
+			[]string{"assembly: golang.org/x.a", "assembly: golang.org/x.b", "assembly: golang.org/x.c", "assembly: golang.org/x.d", "assembly: golang.org/x.e"},
+			[]string{"assembly: golang.org/x.a, golang.org/x.b, golang.org/x.c and 2 more"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := boundaryNotes(test.boundaries); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestShowCgoNotes(t *testing.T) {
+	var buf strings.Builder
+	h := NewTextHandler(&buf)
+	h.Show([]string{showCgoNotes})
+	h.OSV(&osv.Entry{ID: "GO-0000-0001", DatabaseSpecific: &osv.DatabaseSpecific{URL: "https://pkg.go.dev/vuln/GO-0000-0001"}})
+	h.Finding(&govulncheck.Finding{
+		OSV:        "GO-0000-0001",
+		Trace:      []*govulncheck.Frame{{Module: "golang.org/a", Package: "golang.org/a"}},
+		Boundaries: []string{"cgo: golang.org/c._Cfunc_open"},
+	})
+	h.Flush()
+	if want := "    Not followed past: cgo: golang.org/c._Cfunc_open\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("output does not contain %q:\n%s", want, buf.String())
+	}
+}
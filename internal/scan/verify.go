@@ -0,0 +1,166 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/vuln/internal/govulncheck"
+)
+
+// verifyJSON reads the JSON output of govulncheck from r, writes it
+// again as govulncheck would, and reports an error listing the
+// differences if that loses or changes anything. The summary is only
+// compared if r has one, as it is recomputed when writing.
+func verifyJSON(r io.Reader) error {
+	in, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var out bytes.Buffer
+	h := govulncheck.NewJSONHandler(&out)
+	if err := govulncheck.HandleJSON(bytes.NewReader(in), h); err != nil {
+		return fmt.Errorf("govulncheck: verifying JSON input: %v", err)
+	}
+	if err := Flush(h); err != nil {
+		return err
+	}
+	want, err := decodeMessages(in)
+	if err != nil {
+		return err
+	}
+	got, err := decodeMessages(out.Bytes())
+	if err != nil {
+		return err
+	}
+	if len(got) > 0 && len(want) > 0 && !hasKey(want[len(want)-1], "summary") {
+		got = got[:len(got)-1]
+	}
+	var diffs []string
+	for i := 0; i < len(want) || i < len(got); i++ {
+		var w, g any
+		if i < len(want) {
+			w = want[i]
+		}
+		if i < len(got) {
+			g = got[i]
+		}
+		diffs = append(diffs, jsonDiff(fmt.Sprintf("message %d", i+1), w, g)...)
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("govulncheck: JSON input does not round-trip:\n\t%s", strings.Join(diffs, "\n\t"))
+	}
+	return nil
+}
+
+// decodeMessages decodes the stream of JSON messages in b into generic
+// values.
+func decodeMessages(b []byte) ([]any, error) {
+	var msgs []any
+	dec := json.NewDecoder(bytes.NewReader(b))
+	for dec.More() {
+		var msg any
+		if err := dec.Decode(&msg); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func hasKey(v any, key string) bool {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return false
+	}
+	_, ok = m[key]
+	return ok
+}
+
+// jsonDiff returns the differences between the generic JSON values want
+// and got, each prefixed by its path from path. A nil value stands for a
+// missing one. Zero values are the same as missing ones, as they decode
+// the same.
+func jsonDiff(path string, want, got any) []string {
+	switch {
+	case want == nil && isZeroJSON(got), got == nil && isZeroJSON(want):
+		return nil
+	case got == nil:
+		return []string{path + ": dropped"}
+	case want == nil:
+		return []string{path + ": added"}
+	}
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := map[string]bool{}
+		for k := range w {
+			keys[k] = true
+		}
+		for k := range g {
+			keys[k] = true
+		}
+		var sorted []string
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		var diffs []string
+		for _, k := range sorted {
+			diffs = append(diffs, jsonDiff(path+"."+k, w[k], g[k])...)
+		}
+		return diffs
+	case []any:
+		g, ok := got.([]any)
+		if !ok {
+			break
+		}
+		var diffs []string
+		for i := 0; i < len(w) || i < len(g); i++ {
+			var wi, gi any
+			if i < len(w) {
+				wi = w[i]
+			}
+			if i < len(g) {
+				gi = g[i]
+			}
+			diffs = append(diffs, jsonDiff(fmt.Sprintf("%s[%d]", path, i), wi, gi)...)
+		}
+		return diffs
+	}
+	if !reflect.DeepEqual(want, got) {
+		return []string{fmt.Sprintf("%s: %v became %v", path, want, got)}
+	}
+	return nil
+}
+
+// isZeroJSON reports whether the generic JSON value v is null or the zero
+// value of its type.
+func isZeroJSON(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
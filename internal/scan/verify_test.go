@@ -0,0 +1,34 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyJSON(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		input string
+		want  string // part of the error, or "" for none
+	}{
+		{"round trip", `{"finding": {"osv": "GO-0000-0001", "trace": [{"module": "m"}]}}`, ""},
+		// Zero values are left out when writing, and decode the same.
+		{"zero values", `{"progress": {"message": "Scanning...", "done": 0}}`, ""},
+		{"summary", `{"finding": {"osv": "GO-0000-0001", "trace": [{"module": "m"}]}}{"summary": {"affected": true}}`, "message 2.summary.affected: true became false"},
+		{"unknown field", `{"config": {"protocol_version": "v1.0.0", "extra": 1}}`, "message 1.config.extra: dropped"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verifyJSON(strings.NewReader(tc.input))
+			switch {
+			case tc.want == "" && err != nil:
+				t.Errorf("got %v; want no error", err)
+			case tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)):
+				t.Errorf("got %v; want an error containing %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/vuln/internal"
+	"golang.org/x/vuln/internal/web"
+)
+
+// proxyTimeout bounds each request to the module proxy, so that an
+// unreachable proxy does not hold up the output for long.
+const proxyTimeout = 5 * time.Second
+
+// proxyVersions lists the versions of modules known to the module proxy
+// of the environment of a scan, as needed. The list of each module is
+// fetched once, and a failure to fetch it is remembered too.
+type proxyVersions struct {
+	ctx   context.Context
+	proxy string              // URL of the proxy, or "" if there is none
+	cache map[string][]string // by module path, nil after a failure
+}
+
+// newProxyVersions returns a proxyVersions for the first proxy in the
+// GOPROXY of the environment of cfg.
+func newProxyVersions(ctx context.Context, cfg *config) *proxyVersions {
+	return &proxyVersions{ctx: ctx, proxy: firstProxy(goEnv(cfg, "GOPROXY")), cache: map[string][]string{}}
+}
+
+// goEnv returns the value of the Go environment variable key for the
+// environment and -C directory of cfg, as the go command reports it, or
+// "" if it cannot be run.
+func goEnv(cfg *config, key string) string {
+	cmd := exec.Command("go", "env", key)
+	cmd.Dir = cfg.dir
+	if len(cfg.env) > 0 {
+		cmd.Env = cfg.env
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// firstProxy returns the first proxy URL in goproxy, the value of
+// GOPROXY, or "" if it does not start with one. The go command defaults
+// to proxy.golang.org.
+func firstProxy(goproxy string) string {
+	if goproxy == "" {
+		return "https://proxy.golang.org"
+	}
+	first := strings.FieldsFunc(goproxy, func(r rune) bool { return r == ',' || r == '|' })
+	if len(first) == 0 || first[0] == "direct" || first[0] == "off" {
+		return ""
+	}
+	return strings.TrimRight(first[0], "/")
+}
+
+// Versions returns the released versions of mod, in no particular
+// order, or an error if they cannot be listed.
+func (p *proxyVersions) Versions(mod string) ([]string, error) {
+	if vs, ok := p.cache[mod]; ok {
+		if vs == nil {
+			return nil, fmt.Errorf("no versions of %s", mod)
+		}
+		return vs, nil
+	}
+	vs, err := p.list(mod)
+	if err == nil && vs == nil {
+		vs = []string{}
+	}
+	p.cache[mod] = vs
+	return vs, err
+}
+
+func (p *proxyVersions) list(mod string) ([]string, error) {
+	if p.proxy == "" {
+		return nil, fmt.Errorf("no module proxy")
+	}
+	escaped, err := module.EscapePath(mod)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(p.proxy + "/" + escaped + "/@v/list")
+	if err != nil {
+		return nil, err
+	}
+	var b []byte
+	if u.Scheme == "file" {
+		file, err := web.URLToFilePath(u)
+		if err != nil {
+			return nil, err
+		}
+		if b, err = os.ReadFile(file); err != nil {
+			return nil, err
+		}
+	} else {
+		ctx, cancel := context.WithTimeout(p.ctx, proxyTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("listing versions of %s: %s", mod, resp.Status)
+		}
+		if b, err = io.ReadAll(resp.Body); err != nil {
+			return nil, err
+		}
+	}
+	var vs []string
+	for _, v := range strings.Fields(string(b)) {
+		if semver.IsValid(v) {
+			vs = append(vs, v)
+		}
+	}
+	return vs, nil
+}
+
+// releasesBehind returns how many released versions of versions are
+// later than found, up to and including fixed. Prereleases are not
+// counted, except fixed itself.
+func releasesBehind(versions []string, found, fixed string) int {
+	n := 0
+	for _, v := range versions {
+		if semver.Compare(v, found) <= 0 || semver.Compare(v, fixed) > 0 {
+			continue
+		}
+		if semver.Prerelease(v) != "" && v != fixed {
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// versionDelta returns how far found is behind fixed, as a note to put
+// after the fixed version of module, or "" when that is not known. The
+// standard library and the toolchain have no versions in the proxy.
+func (h *TextHandler) versionDelta(mod, found, fixed string) string {
+	if !h.showVersionDelta || h.versions == nil || found == "" || fixed == "" ||
+		mod == internal.GoStdModulePath || mod == internal.GoCmdModulePath {
+		return ""
+	}
+	versions, err := h.versions(mod)
+	if err != nil {
+		return ""
+	}
+	n := releasesBehind(versions, found, fixed)
+	if n == 0 {
+		return ""
+	}
+	return " (" + h.msgf(plural(n, msgReleasesBehindOne, msgReleasesBehindMany), n) + ")"
+}
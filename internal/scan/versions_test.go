@@ -0,0 +1,77 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/osv"
+	"golang.org/x/vuln/internal/web"
+)
+
+func TestReleasesBehind(t *testing.T) {
+	versions := []string{"v1.2.3", "v1.2.4", "v1.2.5-rc.1", "v1.2.5", "v1.2.6-rc.1", "v1.3.0"}
+	for _, tc := range []struct {
+		found, fixed string
+		want         int
+	}{
+		{"v1.2.3", "v1.2.5", 2},
+		{"v1.2.3", "v1.2.6-rc.1", 3},
+		{"v1.2.3", "v1.3.0", 3},
+		{"v1.3.0", "v1.3.0", 0},
+	} {
+		if got := releasesBehind(versions, tc.found, tc.fixed); got != tc.want {
+			t.Errorf("releasesBehind(%q, %q) = %d; want %d", tc.found, tc.fixed, got, tc.want)
+		}
+	}
+}
+
+func TestVersionDelta(t *testing.T) {
+	proxy := t.TempDir()
+	dir := filepath.Join(proxy, "golang.org", "x", "text", "@v")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "list"), []byte("v0.3.5\nv0.3.6\nv0.3.7\nv0.4.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	proxyURL, err := web.URLFromFilePath(proxy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name, goproxy, want string
+	}{
+		{"proxy", proxyURL.String(), "Fixed in: golang.org/x/text@v0.3.7 (2 releases behind)\n"},
+		// Without versions, the delta is left out.
+		{"no proxy", "off", "Fixed in: golang.org/x/text@v0.3.7\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config{env: append(os.Environ(), "GOPROXY="+tc.goproxy)}
+			var buf strings.Builder
+			h := NewTextHandler(&buf)
+			h.Show([]string{showVersionDelta})
+			h.Versions(newProxyVersions(context.Background(), cfg).Versions)
+			h.OSV(&osv.Entry{ID: "GO-2021-0113", DatabaseSpecific: &osv.DatabaseSpecific{}})
+			h.Finding(&govulncheck.Finding{
+				OSV:          "GO-2021-0113",
+				FixedVersion: "v0.3.7",
+				Trace: []*govulncheck.Frame{
+					{Module: "golang.org/x/text", Version: "v0.3.5", Package: "golang.org/x/text/language", Function: "Parse"},
+					{Module: "golang.org/main", Package: "golang.org/main", Function: "main"},
+				},
+			})
+			h.Flush()
+			if !strings.Contains(buf.String(), tc.want) {
+				t.Errorf("got:\n%s\nwant it to contain %q", buf.String(), tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultWidth is the width text output is wrapped to when no other
+// width is known, for instance in CI logs.
+const defaultWidth = 80
+
+// resolveWidth returns the width to wrap text output to. The first of
+// these that is known wins: the -width flag, when positive, the $COLUMNS
+// environment variable, the size of the terminal, and defaultWidth.
+func resolveWidth(flag int, getenv func(string) string, terminal func() (int, bool)) int {
+	if flag > 0 {
+		return flag
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(getenv("COLUMNS"))); err == nil && n > 0 {
+		return n
+	}
+	if n, ok := terminal(); ok && n > 0 {
+		return n
+	}
+	return defaultWidth
+}
+
+// outputWidth returns the width to wrap text output written to w to.
+func outputWidth(flag int, w io.Writer) int {
+	return resolveWidth(flag, os.Getenv, func() (int, bool) {
+		f, ok := w.(*os.File)
+		if !ok {
+			return 0, false
+		}
+		return terminalWidth(f)
+	})
+}
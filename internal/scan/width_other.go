@@ -0,0 +1,16 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package scan
+
+import "os"
+
+// terminalWidth reports that the size of the terminal is unknown on
+// this platform.
+func terminalWidth(f *os.File) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,40 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import "testing"
+
+func TestResolveWidth(t *testing.T) {
+	env := func(columns string) func(string) string {
+		return func(key string) string {
+			if key == "COLUMNS" {
+				return columns
+			}
+			return ""
+		}
+	}
+	terminal := func(n int) func() (int, bool) {
+		return func() (int, bool) { return n, n > 0 }
+	}
+	for _, tc := range []struct {
+		name     string
+		flag     int
+		columns  string
+		terminal int
+		want     int
+	}{
+		{"flag", 100, "120", 140, 100},
+		{"columns", 0, "120", 140, 120},
+		{"terminal", 0, "", 140, 140},
+		{"bad columns", 0, "wide", 140, 140},
+		{"default", 0, "", 0, defaultWidth},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := resolveWidth(tc.flag, env(tc.columns), terminal(tc.terminal)); got != tc.want {
+				t.Errorf("got %d; want %d", got, tc.want)
+			}
+		})
+	}
+}
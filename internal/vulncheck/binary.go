@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"io"
 	"runtime/debug"
+	"time"
 
 	"golang.org/x/vuln/internal"
 	"golang.org/x/vuln/internal/client"
@@ -32,10 +33,12 @@ func Binary(ctx context.Context, exe io.ReaderAt, cfg *govulncheck.Config, clien
 	graph.AddModules(mods...)
 	mods = append(mods, graph.GetModule(internal.GoStdModulePath))
 
+	start := time.Now()
 	mv, err := FetchVulnerabilities(ctx, client, mods)
 	if err != nil {
 		return nil, err
 	}
+	fetchTime := time.Since(start)
 	modVulns := moduleVulnerabilities(mv)
 
 	goos := findSetting("GOOS", bi)
@@ -45,13 +48,14 @@ func Binary(ctx context.Context, exe io.ReaderAt, cfg *govulncheck.Config, clien
 	}
 
 	modVulns = modVulns.filter(goos, goarch)
-	result := &Result{}
+	result := &Result{Considered: consideredEntries(mv), FetchTime: fetchTime}
 
 	if packageSymbols == nil {
 		// The binary exe is stripped. We currently cannot detect inlined
 		// symbols for stripped binaries (see #57764), so we report
 		// vulnerabilities at the go.mod-level precision.
 		addRequiresOnlyVulns(result, graph, modVulns)
+		setScanLevel(result, govulncheck.ScanLevelModule)
 	} else {
 		for pkg, symbols := range packageSymbols {
 			if !cfg.ScanLevel.WantSymbols() {
@@ -60,6 +64,11 @@ func Binary(ctx context.Context, exe io.ReaderAt, cfg *govulncheck.Config, clien
 				addSymbolVulns(result, graph, pkg, symbols, modVulns)
 			}
 		}
+		level := govulncheck.ScanLevelPackage
+		if cfg.ScanLevel.WantSymbols() {
+			level = govulncheck.ScanLevelSymbol
+		}
+		setScanLevel(result, level)
 	}
 	return result, nil
 }
@@ -0,0 +1,111 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vulncheck
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/vuln/internal/osv"
+)
+
+// Kinds of boundaries.
+const (
+	BoundaryCgo      = "cgo"
+	BoundaryAssembly = "assembly"
+)
+
+// A Boundary is a function of the call graph that the analysis cannot
+// see through: a call into C, through which C code can call back into
+// Go, or a function implemented in assembly, whose calls are unknown.
+type Boundary struct {
+	// Func is the function at the boundary.
+	Func *FuncNode
+
+	// Kind is the kind of boundary, BoundaryCgo or BoundaryAssembly.
+	Kind string
+}
+
+// cgoFuncPrefix starts the names of the functions that cgo generates
+// for calls into C.
+const cgoFuncPrefix = "_Cfunc_"
+
+// boundaries returns the boundaries among the functions of cg, sorted by
+// package and name.
+func boundaries(cg *callgraph.Graph, graph *PackageGraph) []*Boundary {
+	nodes := make(map[*ssa.Function]*FuncNode)
+	var bs []*Boundary
+	for f := range cg.Nodes {
+		if f == nil || f.Pkg == nil || f.Synthetic != "" {
+			continue
+		}
+		var kind string
+		switch {
+		case strings.HasPrefix(f.Name(), cgoFuncPrefix):
+			kind = BoundaryCgo
+		case f.Blocks == nil:
+			kind = BoundaryAssembly
+		default:
+			continue
+		}
+		bs = append(bs, &Boundary{Func: createNode(nodes, f, graph), Kind: kind})
+	}
+	sort.Slice(bs, func(i, j int) bool { return bs[i].Func.String() < bs[j].Func.String() })
+	return bs
+}
+
+// Boundaries returns, for each vulnerability of res whose OSV entry has
+// no symbol with a call sink, the boundaries of res that could hide a
+// call to it, that is, the ones in the package of the vulnerability or
+// in packages that import it, directly or not. Packages are compared by path, so that the test
+// variant of a package stands for the package.
+func Boundaries(res *Result) map[*Vuln][]*Boundary {
+	out := make(map[*Vuln][]*Boundary)
+	if len(res.Boundaries) == 0 {
+		return out
+	}
+	// imports memoizes whether a package imports, directly or not,
+	// another one.
+	type pair struct{ from, to *packages.Package }
+	imports := make(map[pair]bool)
+	var reaches func(from, to *packages.Package) bool
+	reaches = func(from, to *packages.Package) bool {
+		if from.PkgPath == to.PkgPath {
+			return true
+		}
+		p := pair{from, to}
+		if r, ok := imports[p]; ok {
+			return r
+		}
+		imports[p] = false // break import cycles
+		for _, imp := range from.Imports {
+			if reaches(imp, to) {
+				imports[p] = true
+				return true
+			}
+		}
+		return false
+	}
+	called := make(map[*osv.Entry]bool)
+	for _, v := range res.Vulns {
+		if v.CallSink != nil {
+			called[v.OSV] = true
+		}
+	}
+	for _, v := range res.Vulns {
+		if called[v.OSV] || v.ImportSink == nil {
+			continue
+		}
+		for _, b := range res.Boundaries {
+			if b.Func.Package != nil && reaches(b.Func.Package, v.ImportSink) {
+				out[v] = append(out[v], b)
+			}
+		}
+	}
+	return out
+}
@@ -10,10 +10,14 @@ import (
 	"golang.org/x/tools/go/ssa"
 )
 
-func entryPoints(topPackages []*ssa.Package) []*ssa.Function {
+// entryPoints returns the functions of topPackages that the call graph
+// starts from. With testOnly, only those declared in _test.go files are
+// entry points, even in main packages, so that the calls of production
+// code are only followed from tests.
+func entryPoints(topPackages []*ssa.Package, testOnly bool) []*ssa.Function {
 	var entries []*ssa.Function
 	for _, pkg := range topPackages {
-		if pkg.Pkg.Name() == "main" {
+		if pkg.Pkg.Name() == "main" && !testOnly {
 			// for "main" packages the only valid entry points are the "main"
 			// function and any "init#" functions, even if there are other
 			// exported functions or types. similarly to isEntry it should be
@@ -30,7 +34,7 @@ func entryPoints(topPackages []*ssa.Package) []*ssa.Function {
 		}
 		for _, member := range pkg.Members {
 			for _, f := range memberFuncs(member, pkg.Prog) {
-				if isEntry(f) {
+				if isEntry(f) && (!testOnly || inTestFile(f)) {
 					entries = append(entries, f)
 				}
 			}
@@ -39,6 +43,11 @@ func entryPoints(topPackages []*ssa.Package) []*ssa.Function {
 	return entries
 }
 
+// inTestFile reports whether f is declared in a _test.go file.
+func inTestFile(f *ssa.Function) bool {
+	return strings.HasSuffix(f.Prog.Fset.Position(f.Pos()).Filename, "_test.go")
+}
+
 func isEntry(f *ssa.Function) bool {
 	// it should be safe to ignore checking that the signature of the "init" function
 	// is valid, since it is synthetic
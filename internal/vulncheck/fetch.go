@@ -9,6 +9,7 @@ import (
 
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/vuln/internal/client"
+	"golang.org/x/vuln/internal/osv"
 )
 
 // FetchVulnerabilities fetches vulnerabilities that affect the supplied modules.
@@ -39,3 +40,18 @@ func FetchVulnerabilities(ctx context.Context, c *client.Client, modules []*pack
 	}
 	return mv, nil
 }
+
+// consideredEntries returns the OSV entries of mv, without duplicates.
+func consideredEntries(mv []*ModVulns) []*osv.Entry {
+	var entries []*osv.Entry
+	seen := make(map[string]bool)
+	for _, mod := range mv {
+		for _, e := range mod.Vulns {
+			if !seen[e.ID] {
+				seen[e.ID] = true
+				entries = append(entries, e)
+			}
+		}
+	}
+	return entries
+}
@@ -67,6 +67,21 @@ func (x *elfExe) progContaining(addr uint64) *elf.Prog {
 	return nil
 }
 
+// symbolRange returns the data between the symbols start and end, or nil
+// if either of them is missing.
+func (x *elfExe) symbolRange(start, end string) []byte {
+	s, _ := x.lookupSymbol(start)
+	e, _ := x.lookupSymbol(end)
+	if s == nil || e == nil || e.Value <= s.Value {
+		return nil
+	}
+	b, err := x.ReadData(s.Value, e.Value-s.Value)
+	if err != nil || uint64(len(b)) != e.Value-s.Value {
+		return nil
+	}
+	return b
+}
+
 const go12magic = 0xfffffffb
 const go116magic = 0xfffffffa
 
@@ -82,6 +97,11 @@ func (x *elfExe) PCLNTab() ([]byte, uint64) {
 		// Addition: this code is added to support some form of stripping.
 		pclntab = x.f.Section(".data.rel.ro.gopclntab")
 		if pclntab == nil {
+			// Plugins keep the PCLN table in .data.rel.ro, but it is
+			// delimited by runtime symbols.
+			if b := x.symbolRange("runtime.pclntab", "runtime.epclntab"); b != nil {
+				return b, offset
+			}
 			pclntab = x.f.Section(".data.rel.ro")
 			if pclntab == nil {
 				return nil, 0
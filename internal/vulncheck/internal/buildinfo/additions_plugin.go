@@ -0,0 +1,215 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package buildinfo
+
+// This file adds to buildinfo the functionality for approximating the
+// build information of Go plugins, which have none of their own.
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"encoding/binary"
+	"io"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/vuln/internal/vulncheck/internal/gosym"
+)
+
+// pluginSymbol is defined by the linker in every Go plugin.
+const pluginSymbol = "go:plugin.tabs"
+
+// toolchainModule is the module of the Go toolchains in the module cache.
+const toolchainModule = "golang.org/toolchain"
+
+// pluginBuildInfo returns the build information of the Go plugin bin, or
+// nil if bin is not a Go plugin. Plugins are not built with a build
+// information blob, so it is approximated from what the plugin does
+// have: the dependencies are the module versions in the paths of the
+// source files of its line table, GOOS and GOARCH come from its object
+// file header, and the Go version comes from the path of the toolchain,
+// or else is the oldest version with the format of its line table.
+func pluginBuildInfo(bin io.ReaderAt) (*debug.BuildInfo, error) {
+	x, err := openExe(bin)
+	if err != nil {
+		return nil, err
+	}
+	goos, goarch, ok := pluginPlatform(x)
+	if !ok {
+		return nil, nil
+	}
+	pclntab, textOffset := x.PCLNTab()
+	if pclntab == nil {
+		return nil, nil
+	}
+	lineTab := gosym.NewLineTable(pclntab, textOffset)
+	if lineTab == nil {
+		return nil, nil
+	}
+	tab, err := gosym.NewTable(nil, lineTab)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(tab.Files))
+	for f := range tab.Files {
+		files = append(files, f)
+	}
+	bi := &debug.BuildInfo{
+		Settings: []debug.BuildSetting{
+			{Key: "-buildmode", Value: "plugin"},
+			{Key: "GOARCH", Value: goarch},
+			{Key: "GOOS", Value: goos},
+		},
+	}
+	for _, m := range modulesFromFiles(files) {
+		if m.Path == toolchainModule {
+			bi.GoVersion = toolchainGoVersion(m.Version)
+			continue
+		}
+		bi.Deps = append(bi.Deps, m)
+	}
+	if bi.GoVersion == "" {
+		bi.GoVersion = lineTableGoVersion(pclntab)
+	}
+	return bi, nil
+}
+
+// pluginPlatform returns the GOOS and GOARCH of x if it is a Go plugin.
+// Plugins are only supported on linux, freebsd and darwin.
+func pluginPlatform(x exe) (goos, goarch string, ok bool) {
+	switch x := x.(type) {
+	case *elfExe:
+		if s, _ := x.lookupSymbol(pluginSymbol); s == nil {
+			return "", "", false
+		}
+		goos = "linux"
+		if x.f.OSABI == elf.ELFOSABI_FREEBSD {
+			goos = "freebsd"
+		}
+		switch x.f.Machine {
+		case elf.EM_X86_64:
+			goarch = "amd64"
+		case elf.EM_386:
+			goarch = "386"
+		case elf.EM_AARCH64:
+			goarch = "arm64"
+		case elf.EM_ARM:
+			goarch = "arm"
+		case elf.EM_PPC64:
+			goarch = "ppc64"
+			if x.f.ByteOrder == binary.LittleEndian {
+				goarch = "ppc64le"
+			}
+		case elf.EM_S390:
+			goarch = "s390x"
+		case elf.EM_RISCV:
+			goarch = "riscv64"
+		}
+		return goos, goarch, true
+	case *machoExe:
+		if x.f.Symtab == nil || x.lookupSymbol(pluginSymbol) == nil {
+			return "", "", false
+		}
+		switch x.f.Cpu {
+		case macho.CpuAmd64:
+			goarch = "amd64"
+		case macho.CpuArm64:
+			goarch = "arm64"
+		}
+		return "darwin", goarch, true
+	}
+	return "", "", false
+}
+
+// modulesFromFiles returns the modules, sorted by path, of the files in
+// the module cache among files. Such files are either under a pkg/mod
+// directory or, when built with -trimpath, relative to it. In both cases
+// the module path and version come right before the path of the file in
+// the module, as in golang.org/x/text@v0.3.5/language/parse.go.
+func modulesFromFiles(files []string) []*debug.Module {
+	seen := map[string]bool{}
+	var mods []*debug.Module
+	for _, f := range files {
+		at := strings.Index(f, "@")
+		if at < 0 {
+			continue
+		}
+		path := f[:at]
+		if i := strings.LastIndex(path, "/pkg/mod/"); i >= 0 {
+			path = path[i+len("/pkg/mod/"):]
+		} else if strings.HasPrefix(path, "/") {
+			continue
+		}
+		version := f[at+1:]
+		if i := strings.Index(version, "/"); i >= 0 {
+			version = version[:i]
+		}
+		path, err := module.UnescapePath(path)
+		if err != nil {
+			continue
+		}
+		version, err = module.UnescapeVersion(version)
+		if err != nil || !semver.IsValid(version) {
+			continue
+		}
+		if key := path + "@" + version; !seen[key] {
+			seen[key] = true
+			mods = append(mods, &debug.Module{Path: path, Version: version})
+		}
+	}
+	sort.Slice(mods, func(i, j int) bool {
+		if mods[i].Path != mods[j].Path {
+			return mods[i].Path < mods[j].Path
+		}
+		return semver.Compare(mods[i].Version, mods[j].Version) < 0
+	})
+	return mods
+}
+
+// toolchainGoVersion returns the Go version of a version of the toolchain
+// module, such as go1.20.14 for v0.0.1-go1.20.14.linux-amd64.
+func toolchainGoVersion(version string) string {
+	i := strings.Index(version, "-go")
+	if i < 0 {
+		return ""
+	}
+	v := version[i+1:]
+	if j := strings.LastIndex(v, "."); j >= 0 && strings.Contains(v[j:], "-") {
+		v = v[:j]
+	}
+	return v
+}
+
+const (
+	go118magic = 0xfffffff0
+	go120magic = 0xfffffff1
+)
+
+// lineTableGoVersion returns the oldest Go version that writes line tables
+// in the format of pclntab.
+func lineTableGoVersion(pclntab []byte) string {
+	if len(pclntab) < 4 {
+		return ""
+	}
+	for _, magic := range []uint32{binary.LittleEndian.Uint32(pclntab), binary.BigEndian.Uint32(pclntab)} {
+		switch magic {
+		case go120magic:
+			return "go1.20"
+		case go118magic:
+			return "go1.18"
+		case go116magic:
+			return "go1.16"
+		case go12magic:
+			return "go1.2"
+		}
+	}
+	return ""
+}
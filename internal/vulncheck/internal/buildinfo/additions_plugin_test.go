@@ -0,0 +1,51 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package buildinfo
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestModulesFromFiles(t *testing.T) {
+	files := []string{
+		"/home/gopher/go/pkg/mod/golang.org/x/text@v0.3.5/language/parse.go",
+		"/home/gopher/go/pkg/mod/golang.org/x/text@v0.3.5/internal/language/parse.go",
+		"github.com/!burnt!sushi/toml@v1.2.0/decode.go", // -trimpath
+		"/home/gopher/go/pkg/mod/golang.org/toolchain@v0.0.1-go1.20.14.linux-amd64/src/runtime/proc.go",
+		"/home/gopher/plugin/main.go",
+		"/home/gopher/plugin@v1/main.go", // not in the module cache
+		"runtime/proc.go",
+		"vendor/golang.org/x/net/http2/hpack/hpack.go",
+	}
+	got := modulesFromFiles(files)
+	want := []*debug.Module{
+		{Path: "github.com/BurntSushi/toml", Version: "v1.2.0"},
+		{Path: "golang.org/toolchain", Version: "v0.0.1-go1.20.14.linux-amd64"},
+		{Path: "golang.org/x/text", Version: "v0.3.5"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want,+got):%s", diff)
+	}
+}
+
+func TestToolchainGoVersion(t *testing.T) {
+	for _, tc := range []struct {
+		version, want string
+	}{
+		{"v0.0.1-go1.20.14.linux-amd64", "go1.20.14"},
+		{"v0.0.1-go1.21rc2.darwin-arm64", "go1.21rc2"},
+		{"v0.0.1", ""},
+	} {
+		if got := toolchainGoVersion(tc.version); got != tc.want {
+			t.Errorf("toolchainGoVersion(%q) = %q; want %q", tc.version, got, tc.want)
+		}
+	}
+}
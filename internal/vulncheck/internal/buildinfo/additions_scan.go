@@ -46,10 +46,19 @@ func debugModulesToPackagesModules(debugModules []*debug.Module) []*packages.Mod
 //
 // If the symbol table is not available, such as in the case of stripped
 // binaries, returns module and binary info but without the symbol info.
+//
+// bin can also be a Go plugin, whose build information is approximated
+// as described for pluginBuildInfo.
 func ExtractPackagesAndSymbols(bin io.ReaderAt) ([]*packages.Module, map[string][]string, *debug.BuildInfo, error) {
 	bi, err := buildinfo.Read(bin)
 	if err != nil {
-		return nil, nil, nil, err
+		// Go plugins have no build information, so fall back
+		// to approximating it.
+		pbi, perr := pluginBuildInfo(bin)
+		if perr != nil || pbi == nil {
+			return nil, nil, nil, err
+		}
+		bi = pbi
 	}
 
 	funcSymName := gosym.FuncSymName(bi.GoVersion)
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"go/token"
 	"sync"
+	"time"
 
 	"golang.org/x/tools/go/callgraph"
 	"golang.org/x/tools/go/packages"
@@ -61,21 +62,24 @@ func Source(ctx context.Context, pkgs []*packages.Package, cfg *govulncheck.Conf
 		go func() {
 			defer wg.Done()
 			prog, ssaPkgs := buildSSA(pkgs, fset)
-			entries = entryPoints(ssaPkgs)
+			entries = entryPoints(ssaPkgs, cfg.TestOnly)
 			cg, buildErr = callGraph(ctx, prog, entries)
 		}()
 	}
 
 	mods := extractModules(pkgs)
+	start := time.Now()
 	mv, err := FetchVulnerabilities(ctx, client, mods)
 	if err != nil {
 		return nil, err
 	}
+	fetchTime := time.Since(start)
 	modVulns := moduleVulnerabilities(mv)
 	modVulns = modVulns.filter("", "")
-	result := &Result{}
+	result := &Result{Considered: consideredEntries(mv), FetchTime: fetchTime}
 
 	vulnPkgModSlice(pkgs, modVulns, result)
+	setScanLevel(result, govulncheck.ScanLevelPackage)
 	// Return result immediately if not in symbol mode or
 	// if there are no vulnerable packages.
 	if !cfg.ScanLevel.WantSymbols() || len(result.EntryPackages) == 0 {
@@ -88,10 +92,20 @@ func Source(ctx context.Context, pkgs []*packages.Package, cfg *govulncheck.Conf
 	}
 
 	vulnCallGraphSlice(entries, modVulns, cg, result, graph)
+	result.Boundaries = boundaries(cg, graph)
+	setScanLevel(result, govulncheck.ScanLevelSymbol)
 
 	return result, nil
 }
 
+// setScanLevel records level as the scan level of every vulnerability
+// in result.
+func setScanLevel(result *Result, level govulncheck.ScanLevel) {
+	for _, vv := range result.Vulns {
+		vv.ScanLevel = level
+	}
+}
+
 // vulnPkgModSlice computes the slice of pkgs imports and requires graph
 // leading to imports/requires of vulnerable packages/modules in modVulns
 // and stores the computed slices to result.
@@ -319,10 +333,11 @@ func createNode(nodes map[*ssa.Function]*FuncNode, f *ssa.Function, graph *Packa
 		return fn
 	}
 	fn := &FuncNode{
-		Name:     f.Name(),
-		Package:  graph.GetPackage(pkgPath(f)),
-		RecvType: funcRecvType(f),
-		Pos:      funcPosition(f),
+		Name:      f.Name(),
+		Package:   graph.GetPackage(pkgPath(f)),
+		RecvType:  funcRecvType(f),
+		Signature: funcSignature(f),
+		Pos:       funcPosition(f),
 	}
 	nodes[f] = fn
 	return fn
@@ -8,8 +8,10 @@ import (
 	"context"
 	"path"
 	"reflect"
+	"strings"
 	"testing"
 
+	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/go/packages/packagestest"
 	"golang.org/x/vuln/internal/client"
 	"golang.org/x/vuln/internal/govulncheck"
@@ -227,6 +229,9 @@ func TestCalls(t *testing.T) {
 		if v.CallSink == nil {
 			t.Errorf("want CallSink !=0 for %v; got 0", v.Symbol)
 		}
+		if v.ScanLevel != govulncheck.ScanLevelSymbol {
+			t.Errorf("want symbol scan level for %v; got %q", v.Symbol, v.ScanLevel)
+		}
 	}
 
 	wantCalls := map[string][]string{
@@ -526,3 +531,182 @@ func TestIssue57174(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestTestOnly(t *testing.T) {
+	e := packagestest.Export(t, packagestest.Modules, []packagestest.Module{
+		{
+			Name: "golang.org/entry",
+			Files: map[string]interface{}{
+				"x/x.go": `
+			package x
+
+			import (
+				"golang.org/amod/avuln"
+				"golang.org/bmod/bvuln"
+			)
+
+			func X() {
+				avuln.VulnData{}.Vuln1()
+			}
+
+			func Y() {
+				bvuln.Vuln()
+			}
+			`,
+				"x/x_test.go": `
+			package x
+
+			import "testing"
+
+			func TestY(t *testing.T) {
+				Y()
+			}
+			`,
+			},
+		},
+		{
+			Name: "golang.org/amod@v1.1.3",
+			Files: map[string]interface{}{"avuln/avuln.go": `
+			package avuln
+
+			type VulnData struct {}
+			func (v VulnData) Vuln1() {}
+			`},
+		},
+		{
+			Name: "golang.org/bmod@v0.5.0",
+			Files: map[string]interface{}{"bvuln/bvuln.go": `
+			package bvuln
+
+			func Vuln() {}
+			`},
+		},
+	})
+	defer e.Cleanup()
+
+	e.Config.Tests = true
+	graph := NewPackageGraph("go1.18")
+	all, err := graph.LoadPackages(e.Config, nil, []string{path.Join(e.Temp(), "entry/x")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Scan the test variant of x, as with -test-only.
+	var pkgs []*packages.Package
+	for _, p := range all {
+		if strings.HasSuffix(p.ID, ".test]") {
+			pkgs = append(pkgs, p)
+		}
+	}
+	if len(pkgs) != 1 {
+		t.Fatalf("got %d test packages; want the test variant of x", len(pkgs))
+	}
+
+	c, err := newTestClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, testOnly := range []bool{false, true} {
+		cfg := &govulncheck.Config{ScanLevel: "symbol", TestOnly: testOnly}
+		result, err := Source(context.Background(), pkgs, cfg, c, graph)
+		if err != nil {
+			t.Fatal(err)
+		}
+		called := map[string]bool{}
+		for _, v := range result.Vulns {
+			if v.CallSink != nil {
+				called[v.OSV.ID] = true
+			}
+		}
+		// X is only an entry point when production code is scanned too.
+		want := map[string]bool{"VA": true, "VB": true}
+		if testOnly {
+			delete(want, "VA")
+		}
+		if !reflect.DeepEqual(called, want) {
+			t.Errorf("testOnly %v: got called %v; want %v", testOnly, called, want)
+		}
+	}
+}
+
+func TestBoundaries(t *testing.T) {
+	e := packagestest.Export(t, packagestest.Modules, []packagestest.Module{
+		{
+			Name: "golang.org/entry",
+			Files: map[string]interface{}{
+				"x/x.go": `
+			package x
+
+			import (
+				"golang.org/amod/avuln"
+				_ "golang.org/bmod/bvuln"
+				"golang.org/entry/y"
+			)
+
+			func X() int {
+				avuln.VulnData{}.Vuln1()
+				return add(1, 2) + y.Sum(3)
+			}
+
+			// add is implemented in assembly.
+			func add(a, b int) int
+			`,
+				"y/y.go": `
+			package y
+
+			// Sum is implemented in assembly.
+			func Sum(n int) int
+			`,
+			},
+		},
+		{
+			Name: "golang.org/amod@v1.1.3",
+			Files: map[string]interface{}{"avuln/avuln.go": `
+			package avuln
+
+			type VulnData struct {}
+			func (v VulnData) Vuln1() {}
+			`},
+		},
+		{
+			Name: "golang.org/bmod@v0.5.0",
+			Files: map[string]interface{}{"bvuln/bvuln.go": `
+			package bvuln
+
+			func Vuln() {}
+			`},
+		},
+	})
+	defer e.Cleanup()
+
+	graph := NewPackageGraph("go1.18")
+	pkgs, err := graph.LoadPackages(e.Config, nil, []string{path.Join(e.Temp(), "entry/x")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := newTestClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Source(context.Background(), pkgs, &govulncheck.Config{ScanLevel: "symbol"}, c, graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all []string
+	for _, b := range result.Boundaries {
+		all = append(all, b.Kind+" "+b.Func.String())
+	}
+	if want := []string{"assembly golang.org/entry/x.add", "assembly golang.org/entry/y.Sum"}; !reflect.DeepEqual(all, want) {
+		t.Errorf("got boundaries %v; want %v", all, want)
+	}
+	// Only x imports bvuln, so only the boundary in x could hide a call
+	// to it. VA is called, so no boundary matters to it.
+	got := map[string][]string{}
+	for v, bs := range Boundaries(result) {
+		for _, b := range bs {
+			got[v.OSV.ID] = append(got[v.OSV.ID], b.Func.String())
+		}
+	}
+	if want := map[string][]string{"VB": {"golang.org/entry/x.add"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got boundaries by vulnerability %v; want %v", got, want)
+	}
+}
@@ -227,6 +227,16 @@ func funcRecvType(f *ssa.Function) string {
 	return buf.String()
 }
 
+// funcSignature returns the signature of f, such as "(s string) (int, error)",
+// with the types of other packages qualified by package name.
+func funcSignature(f *ssa.Function) string {
+	buf := new(bytes.Buffer)
+	types.WriteSignature(buf, f.Signature, func(p *types.Package) string {
+		return p.Name()
+	})
+	return buf.String()
+}
+
 // allSymbols returns all top-level functions and methods defined in pkg.
 func allSymbols(pkg *types.Package) []string {
 	var names []string
@@ -12,6 +12,7 @@ import (
 
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/vuln/internal"
+	"golang.org/x/vuln/internal/govulncheck"
 	"golang.org/x/vuln/internal/osv"
 	"golang.org/x/vuln/internal/semver"
 )
@@ -31,6 +32,19 @@ type Result struct {
 	// or whose packages are imported in Imports, or whose modules are required in
 	// Requires, have an entry in Vulns.
 	Vulns []*Vuln
+
+	// Considered contains every OSV entry fetched for the modules of the
+	// analyzed code, including the ones found not to affect it.
+	Considered []*osv.Entry
+
+	// Boundaries contains the cgo and assembly boundaries of the call
+	// graph, past which calls are not followed. It is only set when
+	// the scan is at symbol level.
+	Boundaries []*Boundary
+
+	// FetchTime is the time spent fetching vulnerabilities from the
+	// database.
+	FetchTime time.Duration
 }
 
 // Vuln provides information on how a vulnerability is affecting user code by
@@ -60,6 +74,10 @@ type Vuln struct {
 	// When analyzing binaries or PkgPath is not imported, ImportSink will be
 	// unavailable and set to 0.
 	ImportSink *packages.Package
+
+	// ScanLevel is the level of detail at which the vulnerability was
+	// detected.
+	ScanLevel govulncheck.ScanLevel
 }
 
 // A FuncNode describes a function in the call graph.
@@ -70,6 +88,10 @@ type FuncNode struct {
 	// RecvType is the receiver object type of this function, if any.
 	RecvType string
 
+	// Signature is the signature of the function, without the func
+	// keyword and the receiver, if known.
+	Signature string
+
 	// Package is the package the function is part of.
 	Package *packages.Package
 
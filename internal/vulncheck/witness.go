@@ -295,6 +295,102 @@ func funcLess(f1, f2 *FuncNode) bool {
 	return f1.String() < f2.String()
 }
 
+// ImportChains returns, for each vulnerability in res with an import
+// sink, a shortest chain of imports from an entry package of res to the
+// vulnerable package. A chain starts with the entry package and ends
+// with the ImportSink of the vulnerability.
+//
+// ImportChains performs a breadth-first search of the imports graph
+// starting at the entry packages, visiting dependencies in sorted order
+// so that the chosen chains are deterministic.
+func ImportChains(res *Result) map[*Vuln][]*packages.Package {
+	parent, _ := importTree(res)
+	chains := make(map[*Vuln][]*packages.Package)
+	for _, v := range res.Vulns {
+		if _, ok := parent[v.ImportSink]; v.ImportSink == nil || !ok {
+			continue
+		}
+		chains[v] = importChain(parent, v.ImportSink)
+	}
+	return chains
+}
+
+// ModuleChains returns, for each vulnerability in res with an import
+// sink, a shortest chain of imports from an entry package of res to a
+// package of the vulnerable module, as go mod why -m reports it. The
+// package need not be the vulnerable one. The chains are found as in
+// ImportChains.
+func ModuleChains(res *Result) map[*Vuln][]*packages.Package {
+	parent, order := importTree(res)
+	// closest holds the first package of each module in breadth-first
+	// order, which is the closest one to the entry packages.
+	closest := make(map[string]*packages.Package)
+	for _, pkg := range order {
+		if pkg.Module == nil {
+			continue
+		}
+		if _, ok := closest[pkg.Module.Path]; !ok {
+			closest[pkg.Module.Path] = pkg
+		}
+	}
+	chains := make(map[*Vuln][]*packages.Package)
+	for _, v := range res.Vulns {
+		if v.ImportSink == nil || v.ImportSink.Module == nil {
+			continue
+		}
+		if pkg, ok := closest[v.ImportSink.Module.Path]; ok {
+			chains[v] = importChain(parent, pkg)
+		}
+	}
+	return chains
+}
+
+// importTree performs a breadth-first search of the imports graph
+// starting at the entry packages of res, visiting dependencies in sorted
+// order. It returns the importer through which each package was first
+// reached, nil for the entry packages, and the packages in the order
+// they were reached.
+func importTree(res *Result) (map[*packages.Package]*packages.Package, []*packages.Package) {
+	parent := make(map[*packages.Package]*packages.Package)
+	var order []*packages.Package
+	entries := append([]*packages.Package(nil), res.EntryPackages...)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].PkgPath < entries[j].PkgPath })
+	queue := list.New()
+	for _, e := range entries {
+		if _, ok := parent[e]; !ok {
+			parent[e] = nil
+			queue.PushBack(e)
+		}
+	}
+	for queue.Len() > 0 {
+		pkg := queue.Remove(queue.Front()).(*packages.Package)
+		order = append(order, pkg)
+		paths := make([]string, 0, len(pkg.Imports))
+		for path := range pkg.Imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			imp := pkg.Imports[path]
+			if _, ok := parent[imp]; !ok {
+				parent[imp] = pkg
+				queue.PushBack(imp)
+			}
+		}
+	}
+	return parent, order
+}
+
+// importChain returns the chain of imports from an entry package to pkg
+// in the tree of parent.
+func importChain(parent map[*packages.Package]*packages.Package, pkg *packages.Package) []*packages.Package {
+	var chain []*packages.Package
+	for ; pkg != nil; pkg = parent[pkg] {
+		chain = append([]*packages.Package{pkg}, chain...)
+	}
+	return chain
+}
+
 // updateInitPositions populates non-existing positions of init functions
 // and their respective calls in callStacks (see #51575).
 func updateInitPositions(callStacks map[*Vuln]CallStack) {
@@ -71,6 +71,73 @@ func TestCallStacks(t *testing.T) {
 	}
 }
 
+func TestImportChains(t *testing.T) {
+	// Import graph structure for the test program
+	//    entry1      entry2
+	//      |    \      |
+	//    interm1  \  interm2
+	//      |       \   |
+	//     vuln1    vuln2
+	v1 := &packages.Package{PkgPath: "vuln1"}
+	v2 := &packages.Package{PkgPath: "vuln2"}
+	i1 := &packages.Package{PkgPath: "interm1", Imports: map[string]*packages.Package{"vuln1": v1}}
+	i2 := &packages.Package{PkgPath: "interm2", Imports: map[string]*packages.Package{"vuln2": v2}}
+	e1 := &packages.Package{PkgPath: "entry1", Imports: map[string]*packages.Package{"interm1": i1, "vuln2": v2}}
+	e2 := &packages.Package{PkgPath: "entry2", Imports: map[string]*packages.Package{"interm2": i2}}
+
+	o := &osv.Entry{ID: "o"}
+	vuln1 := &Vuln{ImportSink: v1, OSV: o, Symbol: "vuln1"}
+	vuln2 := &Vuln{ImportSink: v2, OSV: o, Symbol: "vuln2"}
+	res := &Result{
+		EntryPackages: []*packages.Package{e2, e1},
+		Vulns:         []*Vuln{vuln1, vuln2},
+	}
+
+	want := map[string]string{
+		"vuln1": "entry1->interm1->vuln1",
+		"vuln2": "entry1->vuln2",
+	}
+	got := make(map[string]string)
+	for v, chain := range ImportChains(res) {
+		var paths []string
+		for _, pkg := range chain {
+			paths = append(paths, pkg.PkgPath)
+		}
+		got[v.Symbol] = strings.Join(paths, "->")
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("want %v; got %v", want, got)
+	}
+}
+
+func TestModuleChains(t *testing.T) {
+	// Import graph structure for the test program, where vuln and other
+	// are packages of the same module
+	//    entry
+	//      |    \
+	//    interm  other
+	//      |
+	//     vuln
+	m := &packages.Module{Path: "m"}
+	v := &packages.Package{PkgPath: "m/vuln", Module: m}
+	o := &packages.Package{PkgPath: "m/other", Module: m}
+	i := &packages.Package{PkgPath: "interm", Imports: map[string]*packages.Package{"m/vuln": v}}
+	e := &packages.Package{PkgPath: "entry", Imports: map[string]*packages.Package{"interm": i, "m/other": o}}
+
+	vuln := &Vuln{ImportSink: v, OSV: &osv.Entry{ID: "o"}, Symbol: "vuln"}
+	res := &Result{
+		EntryPackages: []*packages.Package{e},
+		Vulns:         []*Vuln{vuln},
+	}
+	var got []string
+	for _, pkg := range ModuleChains(res)[vuln] {
+		got = append(got, pkg.PkgPath)
+	}
+	if want := []string{"entry", "m/other"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v; got %v", want, got)
+	}
+}
+
 func TestUniqueCallStack(t *testing.T) {
 	// Call graph structure for the test program
 	//    entry1      entry2
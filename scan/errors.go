@@ -0,0 +1,26 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import "golang.org/x/vuln/internal/scan"
+
+// ConfigError reports flags or patterns that govulncheck cannot run
+// with. When the arguments of a Cmd are invalid, Wait returns a usage
+// error, with exit code 2, whose cause is a *ConfigError: use errors.As
+// to get it, or errors.Is to test for its kind.
+type ConfigError = scan.ConfigError
+
+// Kinds of ConfigError.
+var (
+	ErrUnsupportedMode      = scan.ErrUnsupportedMode
+	ErrUnsupportedFormat    = scan.ErrUnsupportedFormat
+	ErrInvalidFlagValue     = scan.ErrInvalidFlagValue
+	ErrUnreadableFile       = scan.ErrUnreadableFile
+	ErrUnsupportedForFormat = scan.ErrUnsupportedForFormat
+	ErrUnsupportedInMode    = scan.ErrUnsupportedInMode
+	ErrBinaryModeTest       = scan.ErrBinaryModeTest
+	ErrIncompatibleFlags    = scan.ErrIncompatibleFlags
+	ErrInvalidPatterns      = scan.ErrInvalidPatterns
+)
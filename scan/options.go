@@ -0,0 +1,28 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scan
+
+import (
+	"golang.org/x/vuln/internal/govulncheck"
+	"golang.org/x/vuln/internal/scan"
+)
+
+// A Finding is a finding of govulncheck, as in the finding messages of
+// its JSON output.
+type Finding = govulncheck.Finding
+
+// An Option configures optional behavior of a Cmd. Options are set in
+// Cmd.Options, before Start is called.
+type Option = scan.Option
+
+// WithFindingHook returns an Option that calls hook for each finding, in
+// addition to the output selected by the arguments of the Cmd.
+//
+// The hook is called after the output has accepted the finding, and all
+// hooks have been called by the time the output is flushed. Hooks are
+// called in the order they are supplied.
+func WithFindingHook(hook func(*Finding)) Option {
+	return scan.WithFindingHook(hook)
+}
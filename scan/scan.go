@@ -43,6 +43,9 @@ type Cmd struct {
 	//
 	Env []string
 
+	// Options configure optional behavior, such as WithFindingHook.
+	Options []Option
+
 	ctx  context.Context
 	args []string
 	done chan struct{}
@@ -102,5 +105,5 @@ func (c *Cmd) scan() error {
 	if err := c.ctx.Err(); err != nil {
 		return err
 	}
-	return scan.RunGovulncheck(c.ctx, c.Env, c.Stdin, c.Stdout, c.Stderr, c.args)
+	return scan.RunGovulncheck(c.ctx, c.Env, c.Stdin, c.Stdout, c.Stderr, c.args, c.Options...)
 }